@@ -0,0 +1,169 @@
+package aes
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+)
+
+// The reference AES of FIPS-197. The state is the 16 bytes block, byte 4c+r is row r of column c.
+
+// INV_SBOX is the inverse of SBOX.
+var INV_SBOX = func() (inv [256]byte) {
+	for i, v := range SBOX {
+		inv[v] = byte(i)
+	}
+	return
+}()
+
+// Rcon holds the round constants of the key expansion, Rcon[i] = x^i in GF(2^8).
+var Rcon = [10]byte{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80, 0x1b, 0x36}
+
+// KeySizeError is returned for key sizes other than 16, 24 and 32 bytes.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "aes: invalid key size " + strconv.Itoa(int(k))
+}
+
+// Mul returns a·b in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1.
+func Mul(a, b byte) byte {
+	var r byte
+	for ; b != 0; b >>= 1 {
+		r ^= a & -(b & 1)
+		a = a<<1 ^ 0x1b&-(a>>7)
+	}
+	return r
+}
+
+func SubBytes(s *[16]byte) {
+	for i, v := range s {
+		s[i] = SBOX[v]
+	}
+}
+
+func InvSubBytes(s *[16]byte) {
+	for i, v := range s {
+		s[i] = INV_SBOX[v]
+	}
+}
+
+// ShiftRows rotates row r left by r columns.
+func ShiftRows(s *[16]byte) {
+	t := *s
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			s[4*c+r] = t[4*((c+r)%4)+r]
+		}
+	}
+}
+
+// InvShiftRows rotates row r right by r columns.
+func InvShiftRows(s *[16]byte) {
+	t := *s
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			s[4*((c+r)%4)+r] = t[4*c+r]
+		}
+	}
+}
+
+func mixColumns(s *[16]byte, m [4]byte) {
+	t := *s
+	for c := 0; c < 4; c++ {
+		for r := 0; r < 4; r++ {
+			s[4*c+r] = Mul(m[0], t[4*c+r]) ^ Mul(m[1], t[4*c+(r+1)%4]) ^ Mul(m[2], t[4*c+(r+2)%4]) ^ Mul(m[3], t[4*c+(r+3)%4])
+		}
+	}
+}
+
+// MixColumns multiplies each column by {03}x^3 + {01}x^2 + {01}x + {02}.
+func MixColumns(s *[16]byte) {
+	mixColumns(s, [4]byte{2, 3, 1, 1})
+}
+
+// InvMixColumns multiplies each column by {0b}x^3 + {0d}x^2 + {09}x + {0e}.
+func InvMixColumns(s *[16]byte) {
+	mixColumns(s, [4]byte{0x0e, 0x0b, 0x0d, 0x09})
+}
+
+func AddRoundKey(s, rk *[16]byte) {
+	for i := range s {
+		s[i] ^= rk[i]
+	}
+}
+
+// SubWord applies the S-box to each byte of the big endian word w.
+func SubWord(w uint32) uint32 {
+	return uint32(SBOX[w>>24])<<24 | uint32(SBOX[w>>16&0xff])<<16 | uint32(SBOX[w>>8&0xff])<<8 | uint32(SBOX[w&0xff])
+}
+
+// RotWord rotates the big endian word [a0, a1, a2, a3] to [a1, a2, a3, a0].
+func RotWord(w uint32) uint32 {
+	return bits.RotateLeft32(w, 8)
+}
+
+// KeyExpansion returns the Nr+1 round keys of key, Nr is 10, 12 or 14.
+func KeyExpansion(key []byte) ([][16]byte, error) {
+	nk := len(key) / 4
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+	nr := nk + 6
+	w := make([]uint32, 4*(nr+1))
+	for i := 0; i < nk; i++ {
+		w[i] = binary.BigEndian.Uint32(key[4*i:])
+	}
+	for i := nk; i < len(w); i++ {
+		t := w[i-1]
+		if i%nk == 0 {
+			t = SubWord(RotWord(t)) ^ uint32(Rcon[i/nk-1])<<24
+		} else if nk > 6 && i%nk == 4 {
+			t = SubWord(t)
+		}
+		w[i] = w[i-nk] ^ t
+	}
+	rk := make([][16]byte, nr+1)
+	for i := range w {
+		binary.BigEndian.PutUint32(rk[i/4][4*(i%4):], w[i])
+	}
+	return rk, nil
+}
+
+// EncryptBlock encrypts the block src to dst with the round keys of KeyExpansion.
+func EncryptBlock(rk [][16]byte, dst, src []byte) {
+	var s [16]byte
+	copy(s[:], src)
+	nr := len(rk) - 1
+	AddRoundKey(&s, &rk[0])
+	for r := 1; r < nr; r++ {
+		SubBytes(&s)
+		ShiftRows(&s)
+		MixColumns(&s)
+		AddRoundKey(&s, &rk[r])
+	}
+	SubBytes(&s)
+	ShiftRows(&s)
+	AddRoundKey(&s, &rk[nr])
+	copy(dst, s[:])
+}
+
+// DecryptBlock decrypts the block src to dst with the round keys of KeyExpansion (the inverse cipher).
+func DecryptBlock(rk [][16]byte, dst, src []byte) {
+	var s [16]byte
+	copy(s[:], src)
+	nr := len(rk) - 1
+	AddRoundKey(&s, &rk[nr])
+	for r := nr - 1; r > 0; r-- {
+		InvShiftRows(&s)
+		InvSubBytes(&s)
+		AddRoundKey(&s, &rk[r])
+		InvMixColumns(&s)
+	}
+	InvShiftRows(&s)
+	InvSubBytes(&s)
+	AddRoundKey(&s, &rk[0])
+	copy(dst, s[:])
+}
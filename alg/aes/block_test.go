@@ -0,0 +1,84 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/internal/aestest"
+)
+
+func TestKeyExpansion(t *testing.T) {
+	// FIPS-197 Appendix A, the last word of the key schedule
+	cases := []struct {
+		key  string
+		last uint32
+	}{
+		{"2b7e151628aed2a6abf7158809cf4f3c", 0xb6630ca6},
+		{"8e73b0f7da0e6452c810f32b809079e562f8ead2522c6b7b", 0x01002202},
+		{"603deb1015ca71be2b73aef0857d77811f352c073b6108d72d9810a30914dff4", 0x706c631e},
+	}
+	for _, c := range cases {
+		key, _ := hex.DecodeString(c.key)
+		rk, err := KeyExpansion(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rk) != len(key)/4+7 {
+			t.Errorf("%d round keys, want %d", len(rk), len(key)/4+7)
+		}
+		if got := binary.BigEndian.Uint32(rk[len(rk)-1][12:]); got != c.last {
+			t.Errorf("key %s: last word %08x, want %08x", c.key, got, c.last)
+		}
+	}
+	if _, err := KeyExpansion(make([]byte, 20)); err == nil {
+		t.Error("KeyExpansion accepted a 20 bytes key")
+	}
+}
+
+func TestBlock(t *testing.T) {
+	for _, c := range aestest.Vectors {
+		key, _ := hex.DecodeString(c.Key)
+		in, _ := hex.DecodeString(c.In)
+		out, _ := hex.DecodeString(c.Out)
+		rk, _ := KeyExpansion(key)
+		dst := make([]byte, 16)
+		EncryptBlock(rk, dst, in)
+		if !bytes.Equal(dst, out) {
+			t.Errorf("key %s: encrypt got %x, want %x", c.Key, dst, out)
+		}
+		DecryptBlock(rk, dst, out)
+		if !bytes.Equal(dst, in) {
+			t.Errorf("key %s: decrypt got %x, want %x", c.Key, dst, in)
+		}
+		std, _ := aes.NewCipher(key)
+		std.Encrypt(dst, out)
+		EncryptBlock(rk, out, out)
+		if !bytes.Equal(dst, out) {
+			t.Errorf("key %s: got %x, crypto/aes %x", c.Key, out, dst)
+		}
+	}
+}
+
+func TestMixColumns(t *testing.T) {
+	// FIPS-197 section 4.2: {57}·{83} = {c1}
+	if got := Mul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("Mul(57, 83) = %02x, want c1", got)
+	}
+	var s [16]byte
+	for i := range s {
+		s[i] = byte(i * 29)
+	}
+	want := s
+	MixColumns(&s)
+	InvMixColumns(&s)
+	ShiftRows(&s)
+	InvShiftRows(&s)
+	SubBytes(&s)
+	InvSubBytes(&s)
+	if s != want {
+		t.Errorf("inverse round functions got %x, want %x", s, want)
+	}
+}
@@ -0,0 +1,84 @@
+// Package cmac implements CMAC (NIST SP 800-38B, RFC 4493) over a 128-bit block cipher,
+// e.g. AES-CMAC and SM4-CMAC.
+//
+// The multiplication by x of the subkey derivation is pluggable, so the SIMD doubling
+// of every architecture can be validated end to end.
+package cmac
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"hash"
+
+	"github.com/emmansun/simd/alg/gf128"
+)
+
+// BlockSize is the block size of the cipher and the size of the MAC.
+const BlockSize = 16
+
+type cmac struct {
+	block  cipher.Block
+	k1, k2 [BlockSize]byte
+	x      [BlockSize]byte // chaining value
+	buf    [BlockSize]byte // the last block is processed by Sum
+	n      int
+}
+
+// New returns a CMAC hash.Hash with the block cipher block, mulX multiplies a block by x
+// in the big endian convention, nil for gf128.MulXBE.
+func New(block cipher.Block, mulX func(*[16]byte)) (hash.Hash, error) {
+	if block.BlockSize() != BlockSize {
+		return nil, errors.New("cmac: requires 128-bit block cipher")
+	}
+	if mulX == nil {
+		mulX = gf128.MulXBE
+	}
+	c := &cmac{block: block}
+	// subkeys, K1 = L·x and K2 = L·x^2 with L = CIPH(0)
+	block.Encrypt(c.k1[:], c.k1[:])
+	mulX(&c.k1)
+	c.k2 = c.k1
+	mulX(&c.k2)
+	return c, nil
+}
+
+func (c *cmac) Size() int      { return BlockSize }
+func (c *cmac) BlockSize() int { return BlockSize }
+
+func (c *cmac) Reset() {
+	clear(c.x[:])
+	c.n = 0
+}
+
+func (c *cmac) Write(p []byte) (int, error) {
+	nn := len(p)
+	for len(p) > 0 {
+		if c.n == BlockSize {
+			subtle.XORBytes(c.x[:], c.x[:], c.buf[:])
+			c.block.Encrypt(c.x[:], c.x[:])
+			c.n = 0
+		}
+		k := copy(c.buf[c.n:], p)
+		c.n += k
+		p = p[k:]
+	}
+	return nn, nil
+}
+
+// Sum appends the MAC to in, the state is not changed.
+func (c *cmac) Sum(in []byte) []byte {
+	last := c.buf
+	if c.n == BlockSize {
+		subtle.XORBytes(last[:], last[:], c.k1[:])
+	} else {
+		// pad with 10*
+		last[c.n] = 0x80
+		clear(last[c.n+1:])
+		subtle.XORBytes(last[:], last[:], c.k2[:])
+	}
+	var mac [BlockSize]byte
+	subtle.XORBytes(mac[:], c.x[:], last[:])
+	c.block.Encrypt(mac[:], mac[:])
+	return append(in, mac[:]...)
+}
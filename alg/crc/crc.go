@@ -0,0 +1,124 @@
+// Package crc computes the folding constants of the carry-less multiplication CRC kernels for any
+// bit reflected CRC of width 32 or 64, e.g. CRC32 IEEE, CRC32C and CRC64-ECMA/ISO, and is the
+// bitwise reference of these CRCs.
+//
+// The kernels load 16 bytes of the message little-endian, register bit i is message bit i, so the
+// 128 bits block is the polynomial H·x^64 + L with the low qword R(H) and the high qword R(L),
+// where R reverses the 64 bits of a polynomial of degree < 64. The carry-less product of the
+// reversed operands is the reversed product times x, R(a)·R(b) = R128(a·b·x). The constants take
+// the extra x into account:
+//
+//	fold over d bits:  H·x^(d+64) + L·x^d ≡ H·(x^(d+63) mod P)·x + L·(x^(d-1) mod P)·x
+//
+// Folding the last block over w bits gives R128(A), A ≡ M·x^w of degree < 64 + w, A = Ah·x^w + Al.
+// The Barrett reduction computes q = floor(A/P) = floor(Ah·floor(x^(63+w)/P) / x^63), the low qword
+// of the product, and A mod P = Al + q·(P - x^w) mod x^w, the product is split into
+// q·(P - x^w - 1)/x·x and q so that both terms are aligned with Al.
+package crc
+
+import "math/bits"
+
+const (
+	// IEEE is the reflected CRC32 IEEE polynomial of hash/crc32.
+	IEEE = 0xedb88320
+	// Castagnoli is the reflected CRC32C polynomial of hash/crc32.
+	Castagnoli = 0x82f63b78
+	// ISO is the reflected CRC64-ISO polynomial of hash/crc64.
+	ISO = 0xd800000000000000
+	// ECMA is the reflected CRC64-ECMA polynomial of hash/crc64.
+	ECMA = 0xc96c5795d7870f42
+)
+
+// FoldConstants holds the constants of the folding kernels, a pair [2]uint64 multiplies the low
+// qword with [0] and the high qword with [1]. All constants are bit reversed 64 bits values.
+type FoldConstants struct {
+	Width int
+	Poly  uint64 // the reflected polynomial without x^Width
+	// Fold1, Fold4 and Fold8 fold a block over 128, 512 and 1024 bits.
+	Fold1, Fold4, Fold8 [2]uint64
+	// FoldWidth folds the last block over Width bits before the Barrett reduction.
+	FoldWidth [2]uint64
+	// Mu is floor(x^(63+Width) / P).
+	Mu uint64
+	// PolyX is (P - x^Width - 1) / x.
+	PolyX uint64
+}
+
+// NewFoldConstants generates the constants of the reflected CRC of width 32 or 64 with the
+// reflected polynomial poly, the form of hash/crc32 and hash/crc64.
+func NewFoldConstants(width int, poly uint64) *FoldConstants {
+	if width != 32 && width != 64 {
+		panic("crc: unsupported width")
+	}
+	c := &FoldConstants{Width: width, Poly: poly}
+	c.Fold1 = c.FoldPair(128)
+	c.Fold4 = c.FoldPair(4 * 128)
+	c.Fold8 = c.FoldPair(8 * 128)
+	c.FoldWidth = c.FoldPair(width)
+	c.Mu = bits.Reverse64(c.quotient(63 + width))
+	c.PolyX = bits.Reverse64(c.normal() >> 1)
+	return c
+}
+
+// normal returns the polynomial without x^Width, bit i is the coefficient of x^i.
+func (c *FoldConstants) normal() uint64 {
+	return bits.Reverse64(c.Poly) >> (64 - c.Width)
+}
+
+// xmod returns x^n mod P, bit i is the coefficient of x^i.
+func (c *FoldConstants) xmod(n int) uint64 {
+	p := c.normal()
+	r := uint64(1)
+	for i := 0; i < n; i++ {
+		top := r >> (c.Width - 1) & 1
+		r <<= 1
+		if c.Width < 64 {
+			r &= 1<<c.Width - 1
+		}
+		if top == 1 {
+			r ^= p
+		}
+	}
+	return r
+}
+
+// quotient returns floor(x^n / P), n - Width < 64.
+func (c *FoldConstants) quotient(n int) uint64 {
+	p := c.normal()
+	var q, r uint64
+	// the dividend bits of x^n from x^n down to x^0, r holds the remainder of degree < Width
+	for i := n; i >= 0; i-- {
+		top := r >> (c.Width - 1) & 1
+		r <<= 1
+		if c.Width < 64 {
+			r &= 1<<c.Width - 1
+		}
+		if i == n {
+			r |= 1
+		}
+		if top == 1 {
+			r ^= p
+		}
+		// the quotient coefficient of x^i, the bits above x^(n-Width) are zero
+		q = q<<1 | top
+	}
+	return q
+}
+
+// FoldPair returns the constants which fold a 128 bits block over d bits.
+func (c *FoldConstants) FoldPair(d int) [2]uint64 {
+	return [2]uint64{bits.Reverse64(c.xmod(d + 63)), bits.Reverse64(c.xmod(d - 1))}
+}
+
+// Update returns the CRC of p updated from crc bit by bit, with the inversions of hash/crc32 and hash/crc64.
+func Update(width int, poly, crc uint64, p []byte) uint64 {
+	mask := ^uint64(0) >> (64 - width)
+	crc = ^crc & mask
+	for _, b := range p {
+		crc ^= uint64(b)
+		for i := 0; i < 8; i++ {
+			crc = crc>>1 ^ poly&-(crc&1)
+		}
+	}
+	return ^crc & mask
+}
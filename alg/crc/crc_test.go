@@ -0,0 +1,101 @@
+package crc
+
+import (
+	"hash/crc32"
+	"hash/crc64"
+	"math/bits"
+	"testing"
+)
+
+// mulmod returns a·b mod x^w + p of the polynomials a, b of degree < w.
+func mulmod(a, b, p uint64, w int) uint64 {
+	var r uint64
+	for i := w - 1; i >= 0; i-- {
+		top := r >> (w - 1) & 1
+		r <<= 1
+		if w < 64 {
+			r &= 1<<w - 1
+		}
+		if top == 1 {
+			r ^= p
+		}
+		if b>>i&1 == 1 {
+			r ^= a
+		}
+	}
+	return r
+}
+
+func TestUpdate(t *testing.T) {
+	msg := make([]byte, 300)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n += 13 {
+		if got, want := Update(32, IEEE, 0, msg[:n]), uint64(crc32.ChecksumIEEE(msg[:n])); got != want {
+			t.Errorf("IEEE(%d bytes) = %08x, want %08x", n, got, want)
+		}
+		if got, want := Update(32, Castagnoli, 0x12345678, msg[:n]), uint64(crc32.Update(0x12345678, crc32.MakeTable(crc32.Castagnoli), msg[:n])); got != want {
+			t.Errorf("Castagnoli(%d bytes) = %08x, want %08x", n, got, want)
+		}
+		if got, want := Update(64, ISO, 0, msg[:n]), crc64.Checksum(msg[:n], crc64.MakeTable(crc64.ISO)); got != want {
+			t.Errorf("ISO(%d bytes) = %016x, want %016x", n, got, want)
+		}
+		if got, want := Update(64, ECMA, 1, msg[:n]), crc64.Update(1, crc64.MakeTable(crc64.ECMA), msg[:n]); got != want {
+			t.Errorf("ECMA(%d bytes) = %016x, want %016x", n, got, want)
+		}
+	}
+}
+
+func TestFoldConstants(t *testing.T) {
+	for _, tt := range []struct {
+		width int
+		poly  uint64
+	}{{32, IEEE}, {32, Castagnoli}, {64, ISO}, {64, ECMA}} {
+		c := NewFoldConstants(tt.width, tt.poly)
+		p := c.normal()
+		// x^(d+63) = x^d·x^63 and x^(d-1)·x = x^d
+		for _, d := range []int{tt.width, 128, 512, 1024} {
+			k := c.FoldPair(d)
+			hi, lo := bits.Reverse64(k[0]), bits.Reverse64(k[1])
+			if got, want := mulmod(lo, c.xmod(1), p, tt.width), c.xmod(d); got != want {
+				t.Errorf("%x: x^(%d-1) mod P = %x, times x is %x, want %x", tt.poly, d, lo, got, want)
+			}
+			if got, want := mulmod(c.xmod(d), c.xmod(63), p, tt.width), hi; got != want {
+				t.Errorf("%x: x^(%d+63) mod P = %x, want %x", tt.poly, d, hi, want)
+			}
+		}
+		// q·P + r = x^(63+w), r of degree < w: the product q·P has the coefficients x^(63+w) .. x^w of x^(63+w)
+		q := bits.Reverse64(c.Mu)
+		if q>>63 != 1 {
+			t.Errorf("%x: Mu = %x is not of degree 63", tt.poly, q)
+		}
+		var hi, lo uint64 // q·(x^w + p), 128 bits
+		for i := 0; i < 64; i++ {
+			if p>>i&1 == 1 {
+				lo ^= q << i
+				if i > 0 {
+					hi ^= q >> (64 - i)
+				}
+			}
+		}
+		if tt.width == 64 {
+			hi ^= q
+		} else {
+			lo ^= q << 32
+			hi ^= q >> 32
+		}
+		// x^(63+w) is bit 63+w, the bits from x^w to x^(62+w) are zero
+		wantHi, wantLo := uint64(1)<<63, uint64(0)
+		if tt.width == 32 {
+			wantHi = 1 << 31
+			hi &= 1<<32 - 1
+			lo &^= 1<<32 - 1
+		} else {
+			lo = 0
+		}
+		if hi != wantHi || lo != wantLo {
+			t.Errorf("%x: Mu·P = %016x%016x, want x^%d in the high bits", tt.poly, hi, lo, 63+tt.width)
+		}
+	}
+}
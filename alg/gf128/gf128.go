@@ -0,0 +1,69 @@
+// Package gf128 multiplies the elements of GF(2^128) by x and x^k in the conventions of the block cipher modes.
+//
+// All of them use the polynomial x^128 + x^7 + x^2 + x + 1, the difference is the bit order of the 16 bytes block:
+//   - BE, the block is a big endian integer, bit i is the coefficient of x^i (CMAC, OCB, SIV).
+//   - LE, the block is a little endian integer, bit i is the coefficient of x^i (IEEE 1619 XTS).
+//   - GB, the bits are reflected, the most significant bit of byte 0 is the coefficient of x^0
+//     (GB/T 17964 XTS and GHASH).
+//
+// The functions have no secret dependent branches or table lookups.
+//
+// The architecture packages implement GF128MulX{LE,BE,GB} and GF128MulXPow{LE,BE,GB} for 1 <= k <= 8
+// on the simulated registers. The doubling broadcasts the carry bits with an arithmetic shift or a
+// negation, and the multiplication by x^k shifts the block and reduces the k bits shifted out with a
+// carry-less multiplication. No branch depends on the block.
+package gf128
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// mulXPow returns [hi:lo]·x^k mod x^128 + x^7 + x^2 + x + 1, bit i of [hi:lo] is the coefficient of x^i.
+func mulXPow(hi, lo uint64, k int) (uint64, uint64) {
+	for k > 0 {
+		// the carry times 0x87 fits in 64 bits
+		n := min(k, 56)
+		carry := hi >> (64 - n)
+		hi = hi<<n | lo>>(64-n)
+		lo = lo<<n ^ carry ^ carry<<1 ^ carry<<2 ^ carry<<7
+		k -= n
+	}
+	return hi, lo
+}
+
+// MulXBE sets v to v·x, v is in the big endian convention.
+func MulXBE(v *[16]byte) {
+	MulXPowBE(v, 1)
+}
+
+// MulXPowBE sets v to v·x^k, v is in the big endian convention.
+func MulXPowBE(v *[16]byte, k int) {
+	hi, lo := mulXPow(binary.BigEndian.Uint64(v[:8]), binary.BigEndian.Uint64(v[8:]), k)
+	binary.BigEndian.PutUint64(v[:8], hi)
+	binary.BigEndian.PutUint64(v[8:], lo)
+}
+
+// MulXLE sets v to v·x, v is in the little endian convention.
+func MulXLE(v *[16]byte) {
+	MulXPowLE(v, 1)
+}
+
+// MulXPowLE sets v to v·x^k, v is in the little endian convention.
+func MulXPowLE(v *[16]byte, k int) {
+	hi, lo := mulXPow(binary.LittleEndian.Uint64(v[8:]), binary.LittleEndian.Uint64(v[:8]), k)
+	binary.LittleEndian.PutUint64(v[8:], hi)
+	binary.LittleEndian.PutUint64(v[:8], lo)
+}
+
+// MulXGB sets v to v·x, v is in the GB (bit reflected) convention.
+func MulXGB(v *[16]byte) {
+	MulXPowGB(v, 1)
+}
+
+// MulXPowGB sets v to v·x^k, v is in the GB (bit reflected) convention.
+func MulXPowGB(v *[16]byte, k int) {
+	hi, lo := mulXPow(bits.Reverse64(binary.BigEndian.Uint64(v[8:])), bits.Reverse64(binary.BigEndian.Uint64(v[:8])), k)
+	binary.BigEndian.PutUint64(v[8:], bits.Reverse64(hi))
+	binary.BigEndian.PutUint64(v[:8], bits.Reverse64(lo))
+}
@@ -0,0 +1,72 @@
+package gf128
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMulXPow(t *testing.T) {
+	var cases = []struct {
+		name    string
+		mulX    func(*[16]byte)
+		mulXPow func(*[16]byte, int)
+	}{
+		{"BE", MulXBE, MulXPowBE},
+		{"LE", MulXLE, MulXPowLE},
+		{"GB", MulXGB, MulXPowGB},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, c := range cases {
+		for _, k := range []int{0, 1, 2, 4, 7, 8, 55, 56, 57, 63, 64, 127, 128, 129, 300} {
+			var v [16]byte
+			for i := range v {
+				v[i] = byte(r.Uint32())
+			}
+			want := v
+			for i := 0; i < k; i++ {
+				c.mulX(&want)
+			}
+			c.mulXPow(&v, k)
+			if v != want {
+				t.Errorf("%s k=%d: got %x, want %x", c.name, k, v, want)
+			}
+		}
+	}
+}
+
+// TestConventions checks the conventions are the same field with different bit orders.
+func TestConventions(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := 0; i < 100; i++ {
+		var be, le, gb [16]byte
+		for j := range be {
+			be[j] = byte(r.Uint32())
+		}
+		for j := range be {
+			le[15-j] = be[j]
+			for k := 0; k < 8; k++ {
+				gb[15-j] |= (be[j] >> k & 1) << (7 - k)
+			}
+		}
+		k := r.IntN(200)
+		MulXPowBE(&be, k)
+		MulXPowLE(&le, k)
+		MulXPowGB(&gb, k)
+		for j := range be {
+			var rev byte
+			for b := 0; b < 8; b++ {
+				rev |= (be[j] >> b & 1) << (7 - b)
+			}
+			if le[15-j] != be[j] || gb[15-j] != rev {
+				t.Fatalf("k=%d: be %x, le %x, gb %x", k, be, le, gb)
+			}
+		}
+	}
+}
+
+func BenchmarkMulXPowBE(b *testing.B) {
+	var v [16]byte
+	for i := 0; i < b.N; i++ {
+		MulXPowBE(&v, 8)
+	}
+}
@@ -0,0 +1,13 @@
+package gf128_test
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/alg/gf128"
+	"github.com/emmansun/simd/internal/gf128test"
+)
+
+// TestMulX is in an external test package, internal/gf128test imports gf128.
+func TestMulX(t *testing.T) {
+	gf128test.TestMulX(t, gf128.MulXLE, gf128.MulXBE, gf128.MulXGB)
+}
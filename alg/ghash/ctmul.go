@@ -0,0 +1,123 @@
+package ghash
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+)
+
+// tracing records the table lookups, branches and multiplications of a method, the tracer is only
+// set by the constant-time tests and is nil otherwise, a nil Tracer records nothing.
+type tracing struct {
+	tracer *ctcheck.Tracer
+}
+
+func (t *tracing) setTracer(tr *ctcheck.Tracer) {
+	t.tracer = tr
+}
+
+func (t *tracing) lookup(table string, i int) int {
+	t.tracer.Lookup(table, i)
+	return i
+}
+
+func (t *tracing) branch(site string, cond bool) bool {
+	return t.tracer.Branch(site, cond)
+}
+
+// mul returns bmul64(x, y) and records it as an operation, the constant-time methods have no
+// lookups and branches to record.
+func (t *tracing) mul(x, y uint64) uint64 {
+	t.tracer.Op("bmul64")
+	return bmul64(x, y)
+}
+
+// bmul64 returns the low 64 bits of the carry-less product of x and y with integer multiplications.
+// Each operand is split into 4 parts with holes of 3 bits, so the carries of integer additions
+// never spill into the next valid bit.
+func bmul64(x, y uint64) uint64 {
+	x0 := x & 0x1111111111111111
+	x1 := x & 0x2222222222222222
+	x2 := x & 0x4444444444444444
+	x3 := x & 0x8888888888888888
+	y0 := y & 0x1111111111111111
+	y1 := y & 0x2222222222222222
+	y2 := y & 0x4444444444444444
+	y3 := y & 0x8888888888888888
+	z0 := (x0 * y0) ^ (x1 * y3) ^ (x2 * y2) ^ (x3 * y1)
+	z1 := (x0 * y1) ^ (x1 * y0) ^ (x2 * y3) ^ (x3 * y2)
+	z2 := (x0 * y2) ^ (x1 * y1) ^ (x2 * y0) ^ (x3 * y3)
+	z3 := (x0 * y3) ^ (x1 * y2) ^ (x2 * y1) ^ (x3 * y0)
+	z0 &= 0x1111111111111111
+	z1 &= 0x2222222222222222
+	z2 &= 0x4444444444444444
+	z3 &= 0x8888888888888888
+	return z0 | z1 | z2 | z3
+}
+
+// ctmul64Method is the constant-time GHASH method of BearSSL (ghash_ctmul64),
+// no table lookups and no branches on secret data.
+// The high 64 bits of a product are computed as the low bits of the bit reversed product.
+type ctmul64Method struct {
+	tracing
+	h0, h1, h2    uint64
+	h0r, h1r, h2r uint64
+}
+
+func NewCTMul64Method(key []byte) *ctmul64Method {
+	m := &ctmul64Method{}
+	m.h1 = binary.BigEndian.Uint64(key[:8])
+	m.h0 = binary.BigEndian.Uint64(key[8:])
+	m.h0r = bits.Reverse64(m.h0)
+	m.h1r = bits.Reverse64(m.h1)
+	m.h2 = m.h0 ^ m.h1
+	m.h2r = m.h0r ^ m.h1r
+	return m
+}
+
+func (m *ctmul64Method) Mul(y *[16]byte) {
+	y1 := binary.BigEndian.Uint64(y[:8])
+	y0 := binary.BigEndian.Uint64(y[8:])
+	y0r := bits.Reverse64(y0)
+	y1r := bits.Reverse64(y1)
+	y2 := y0 ^ y1
+	y2r := y0r ^ y1r
+
+	// Karatsuba multiplication
+	z0 := m.mul(y0, m.h0)
+	z1 := m.mul(y1, m.h1)
+	z2 := m.mul(y2, m.h2)
+	z0h := m.mul(y0r, m.h0r)
+	z1h := m.mul(y1r, m.h1r)
+	z2h := m.mul(y2r, m.h2r)
+	z2 ^= z0 ^ z1
+	z2h ^= z0h ^ z1h
+	z0h = bits.Reverse64(z0h) >> 1
+	z1h = bits.Reverse64(z1h) >> 1
+	z2h = bits.Reverse64(z2h) >> 1
+
+	v0 := z0
+	v1 := z0h ^ z2
+	v2 := z1 ^ z2h
+	v3 := z1h
+
+	// the product of the bit reflected values is shifted by 1
+	v3 = v3<<1 | v2>>63
+	v2 = v2<<1 | v1>>63
+	v1 = v1<<1 | v0>>63
+	v0 = v0 << 1
+
+	// reduction by x^128 + x^7 + x^2 + x + 1
+	v2 ^= v0 ^ (v0 >> 1) ^ (v0 >> 2) ^ (v0 >> 7)
+	v1 ^= (v0 << 63) ^ (v0 << 62) ^ (v0 << 57)
+	v3 ^= v1 ^ (v1 >> 1) ^ (v1 >> 2) ^ (v1 >> 7)
+	v2 ^= (v1 << 63) ^ (v1 << 62) ^ (v1 << 57)
+
+	binary.BigEndian.PutUint64(y[:8], v3)
+	binary.BigEndian.PutUint64(y[8:], v2)
+}
+
+func (m *ctmul64Method) Hash(T *[16]byte, data []byte) {
+	ghash(m, T, data)
+}
@@ -0,0 +1,85 @@
+package ghash
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+)
+
+func TestCTMul64MethodMul(t *testing.T) {
+	for i, c := range mulCases {
+		key, _ := hex.DecodeString(c.key)
+		m := NewCTMul64Method(key)
+		var y [16]byte
+		y1, _ := hex.DecodeString(c.y)
+		copy(y[:], y1)
+		m.Mul(&y)
+		if hex.EncodeToString(y[:]) != c.out {
+			t.Errorf("case %d: got %v, want %v", i, hex.EncodeToString(y[:]), c.out)
+		}
+	}
+}
+
+func TestCTMul64Method(t *testing.T) {
+	for i, c := range streamCases {
+		key, _ := hex.DecodeString(c.key)
+		data, _ := hex.DecodeString(c.aad + c.ct)
+		var T1, T2 [16]byte
+		NewCTMul64Method(key).Hash(&T1, data)
+		NewGCMMethod(key).Hash(&T2, data)
+		if T1 != T2 {
+			t.Errorf("case %d: got %x, want %x", i, T1, T2)
+		}
+	}
+}
+
+// TestConstantTime treats both the key and the data as secret, the first 16 bytes of a secret are the key.
+// The tracer of a method is set after its construction, so only Hash is traced.
+func TestConstantTime(t *testing.T) {
+	secrets := make([][]byte, 4)
+	for i := range secrets {
+		secrets[i] = make([]byte, 16+64)
+		for j := range secrets[i] {
+			secrets[i][j] = byte(i*67 + j*13)
+		}
+	}
+	for name, newMethod := range gcmMethods {
+		events := 0
+		err := ctcheck.Check(func(tr *ctcheck.Tracer, secret []byte) {
+			m := newMethod(secret[:16])
+			if m, ok := m.(interface{ setTracer(*ctcheck.Tracer) }); ok {
+				m.setTracer(tr)
+			}
+			var T [16]byte
+			m.Hash(&T, secret[16:])
+			events = tr.Len()
+		}, secrets...)
+		if events == 0 {
+			t.Errorf("%s: empty trace, the method is not instrumented", name)
+		}
+		var leak *ctcheck.Leak
+		if name == "ctmul64" {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+		} else if !errors.As(err, &leak) {
+			t.Errorf("%s: secret dependent lookups or branches are not flagged", name)
+		} else {
+			t.Logf("%s: %v", name, err)
+		}
+	}
+}
+
+func BenchmarkCTMul64Method(b *testing.B) {
+	key, _ := hex.DecodeString("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	data := make([]byte, 1024)
+	m := NewCTMul64Method(key)
+	var T [16]byte
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Hash(&T, data)
+	}
+}
@@ -0,0 +1,144 @@
+package ghash
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	gcmBlockSize         = 16
+	gcmStandardNonceSize = 12
+	gcmTagSize           = 16
+	gcmMinimumTagSize    = 4 // NIST SP 800-38D allows 32-bit tags with restrictions.
+)
+
+// gcm is the reference GCM mode (NIST SP 800-38D) over a 128-bit block cipher,
+// the GHASH method is pluggable so every method can be validated end to end.
+type gcm struct {
+	cipher    cipher.Block
+	ghash     Hasher
+	nonceSize int
+	tagSize   int
+}
+
+// NewGCM returns GCM with the standard nonce and tag sizes,
+// newGHASH creates the GHASH method from H, e.g. NewGCMMethod or a CLMUL kernel.
+func NewGCM(block cipher.Block, newGHASH func(key []byte) Hasher) (cipher.AEAD, error) {
+	return NewGCMWithNonceAndTagSize(block, newGHASH, gcmStandardNonceSize, gcmTagSize)
+}
+
+// NewGCMWithNonceAndTagSize returns GCM with the given nonce and tag sizes,
+// the tag is truncated to the leftmost tagSize bytes.
+func NewGCMWithNonceAndTagSize(block cipher.Block, newGHASH func(key []byte) Hasher, nonceSize, tagSize int) (cipher.AEAD, error) {
+	if tagSize < gcmMinimumTagSize || tagSize > gcmTagSize {
+		return nil, errors.New("ghash: incorrect tag size given to GCM")
+	}
+	if nonceSize <= 0 {
+		return nil, errors.New("ghash: the nonce can't have zero length")
+	}
+	if block.BlockSize() != gcmBlockSize {
+		return nil, errors.New("ghash: NewGCM requires 128-bit block cipher")
+	}
+	var h [16]byte
+	block.Encrypt(h[:], h[:])
+	return &gcm{block, newGHASH(h[:]), nonceSize, tagSize}, nil
+}
+
+func (g *gcm) NonceSize() int {
+	return g.nonceSize
+}
+
+func (g *gcm) Overhead() int {
+	return g.tagSize
+}
+
+// deriveCounter computes the pre-counter block J0,
+// IV || 0^31 || 1 for a 96-bit IV, otherwise GHASH(IV || 0^64 || [len(IV)]_64).
+func (g *gcm) deriveCounter(counter *[16]byte, nonce []byte) {
+	if len(nonce) == gcmStandardNonceSize {
+		copy(counter[:], nonce)
+		counter[15] = 1
+		return
+	}
+	s := NewStream(g.ghash)
+	s.Write(nonce)
+	// Sum appends the length block of the AAD and ciphertext sections, [0]_64 || [len(IV)]_64 here.
+	copy(counter[:], s.Sum(nil))
+}
+
+// gcmInc32 increments the rightmost 32 bits of the counter block.
+func gcmInc32(counter *[16]byte) {
+	binary.BigEndian.PutUint32(counter[12:], binary.BigEndian.Uint32(counter[12:])+1)
+}
+
+func (g *gcm) counterCrypt(out, in []byte, counter *[16]byte) {
+	var mask [16]byte
+	for len(in) > 0 {
+		g.cipher.Encrypt(mask[:], counter[:])
+		gcmInc32(counter)
+		n := subtle.XORBytes(out, in, mask[:])
+		out, in = out[n:], in[n:]
+	}
+}
+
+// auth computes the full 16 bytes tag E(K, J0) xor GHASH(H, A, C).
+func (g *gcm) auth(tag *[16]byte, ciphertext, additionalData []byte, tagMask *[16]byte) {
+	s := NewStream(g.ghash)
+	s.WriteAAD(additionalData)
+	s.Write(ciphertext)
+	subtle.XORBytes(tag[:], s.Sum(nil), tagMask[:])
+}
+
+func (g *gcm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != g.nonceSize {
+		panic("ghash: incorrect nonce length given to GCM")
+	}
+	var counter, tagMask, tag [16]byte
+	g.deriveCounter(&counter, nonce)
+	g.cipher.Encrypt(tagMask[:], counter[:])
+	gcmInc32(&counter)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+g.tagSize)
+	g.counterCrypt(out, plaintext, &counter)
+	g.auth(&tag, out[:len(plaintext)], additionalData, &tagMask)
+	copy(out[len(plaintext):], tag[:g.tagSize])
+	return ret
+}
+
+func (g *gcm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != g.nonceSize {
+		panic("ghash: incorrect nonce length given to GCM")
+	}
+	if len(ciphertext) < g.tagSize {
+		return nil, errOpen
+	}
+	tag := ciphertext[len(ciphertext)-g.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-g.tagSize]
+
+	var counter, tagMask, expectedTag [16]byte
+	g.deriveCounter(&counter, nonce)
+	g.cipher.Encrypt(tagMask[:], counter[:])
+	gcmInc32(&counter)
+	g.auth(&expectedTag, ciphertext, additionalData, &tagMask)
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	if subtle.ConstantTimeCompare(expectedTag[:g.tagSize], tag) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, errOpen
+	}
+	g.counterCrypt(out, ciphertext, &counter)
+	return ret, nil
+}
+
+// GMAC returns the GCM tag of data with an empty plaintext, truncated to tagSize bytes.
+func GMAC(block cipher.Block, newGHASH func(key []byte) Hasher, nonce, data []byte, tagSize int) ([]byte, error) {
+	g, err := NewGCMWithNonceAndTagSize(block, newGHASH, len(nonce), tagSize)
+	if err != nil {
+		return nil, err
+	}
+	return g.Seal(nil, nonce, nil, data), nil
+}
@@ -0,0 +1,213 @@
+package ghash
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm4"
+)
+
+var gcmMethods = map[string]func([]byte) Hasher{
+	"raw": func(key []byte) Hasher {
+		m := &rawMethod{}
+		copy(m.key[:], key)
+		return m
+	},
+	"simple8": func(key []byte) Hasher { return NewSimpleMethod8Bits(key) },
+	"simple4": func(key []byte) Hasher { return NewSimpleMethod4Bits(key) },
+	"shoup8":  func(key []byte) Hasher { return NewShoupMethod8Bits(key) },
+	"shoup4":  func(key []byte) Hasher { return NewShoupMethod4Bits(key) },
+	"gcmRaw":  func(key []byte) Hasher { return NewGCMRawMethod(key) },
+	"gcm":     func(key []byte) Hasher { return NewGCMMethod(key) },
+	"ctmul64": func(key []byte) Hasher { return NewCTMul64Method(key) },
+}
+
+// AES-GCM test cases 1-6 from the GCM spec and SM4-GCM from RFC 8998 appendix A.1.
+var gcmCases = []struct {
+	sm4                        bool
+	key, nonce, plaintext, aad string
+	ciphertext, tag            string
+}{
+	{
+		false,
+		"00000000000000000000000000000000",
+		"000000000000000000000000",
+		"",
+		"",
+		"",
+		"58e2fccefa7e3061367f1d57a4e7455a",
+	},
+	{
+		false,
+		"00000000000000000000000000000000",
+		"000000000000000000000000",
+		"00000000000000000000000000000000",
+		"",
+		"0388dace60b6a392f328c2b971b2fe78",
+		"ab6e47d42cec13bdf53a67b21257bddf",
+	},
+	{
+		false,
+		"feffe9928665731c6d6a8f9467308308",
+		"cafebabefacedbaddecaf888",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b391aafd255",
+		"",
+		"42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091473f5985",
+		"4d5c2af327cd64a62cf35abd2ba6fab4",
+	},
+	{
+		false,
+		"feffe9928665731c6d6a8f9467308308",
+		"cafebabefacedbaddecaf888",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091",
+		"5bc94fbc3221a5db94fae95ae7121a47",
+	},
+	{
+		false,
+		"feffe9928665731c6d6a8f9467308308",
+		"cafebabefacedbad",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"61353b4c2806934a777ff51fa22a4755699b2a714fcdc6f83766e5f97b6c742373806900e49f24b22b097544d4896b424989b5e1ebac0f07c23f4598",
+		"3612d2e79e3b0785561be14aaca2fccb",
+	},
+	{
+		false,
+		"feffe9928665731c6d6a8f9467308308",
+		"9313225df88406e555909c5aff5269aa6a7a9538534f7da1e4c303d2a318a728c3c0c95156809539fcf0e2429a6b525416aedbf5a0de6a57a637b39b",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"8ce24998625615b603a033aca13fb894be9112a5c3a211a8ba262a3cca7e2ca701e4a9a4fba43c90ccdcb281d48c7c6fd62875d2aca417034c34aee5",
+		"619cc5aefffe0bfa462af43c1699d050",
+	},
+	{
+		true,
+		"0123456789abcdeffedcba9876543210",
+		"00001234567800000000abcd",
+		"aaaaaaaaaaaaaaaabbbbbbbbbbbbbbbbccccccccccccccccddddddddddddddddeeeeeeeeeeeeeeeeffffffffffffffffeeeeeeeeeeeeeeeeaaaaaaaaaaaaaaaa",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"17f399f08c67d5ee19d0dc9969c4bb7d5fd46fd3756489069157b282bb200735d82710ca5c22f0ccfa7cbf93d496ac15a56834cbcf98c397b4024a2691233b8d",
+		"83de3541e4c2b58177e065a9bf7b62ec",
+	},
+}
+
+func newGCMBlock(t testing.TB, isSM4 bool, key []byte) cipher.Block {
+	var (
+		block cipher.Block
+		err   error
+	)
+	if isSM4 {
+		block, err = sm4.NewCipher(key)
+	} else {
+		block, err = aes.NewCipher(key)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+func TestGCM(t *testing.T) {
+	for i, c := range gcmCases {
+		key, _ := hex.DecodeString(c.key)
+		nonce, _ := hex.DecodeString(c.nonce)
+		plaintext, _ := hex.DecodeString(c.plaintext)
+		aad, _ := hex.DecodeString(c.aad)
+		want := c.ciphertext + c.tag
+		block := newGCMBlock(t, c.sm4, key)
+		for name, m := range gcmMethods {
+			aead, err := NewGCMWithNonceAndTagSize(block, m, len(nonce), 16)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ct := aead.Seal(nil, nonce, plaintext, aad)
+			if got := hex.EncodeToString(ct); got != want {
+				t.Errorf("case %d %s: got %v, want %v", i, name, got, want)
+			}
+			pt, err := aead.Open(nil, nonce, ct, aad)
+			if err != nil || !bytes.Equal(pt, plaintext) {
+				t.Errorf("case %d %s: Open got %x, %v", i, name, pt, err)
+			}
+			ct[len(ct)-1] ^= 1
+			if _, err := aead.Open(nil, nonce, ct, aad); err == nil {
+				t.Errorf("case %d %s: Open accepted a modified tag", i, name)
+			}
+		}
+	}
+}
+
+func TestGCMTagTruncation(t *testing.T) {
+	c := gcmCases[3]
+	key, _ := hex.DecodeString(c.key)
+	nonce, _ := hex.DecodeString(c.nonce)
+	plaintext, _ := hex.DecodeString(c.plaintext)
+	aad, _ := hex.DecodeString(c.aad)
+	block := newGCMBlock(t, false, key)
+	for _, tagSize := range []int{4, 8, 12, 13, 14, 15, 16} {
+		aead, err := NewGCMWithNonceAndTagSize(block, gcmMethods["gcm"], len(nonce), tagSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct := aead.Seal(nil, nonce, plaintext, aad)
+		if got, want := hex.EncodeToString(ct), c.ciphertext+c.tag[:2*tagSize]; got != want {
+			t.Errorf("tag size %d: got %v, want %v", tagSize, got, want)
+		}
+		if _, err := aead.Open(nil, nonce, ct, aad); err != nil {
+			t.Errorf("tag size %d: %v", tagSize, err)
+		}
+	}
+	for _, tagSize := range []int{0, 3, 17} {
+		if _, err := NewGCMWithNonceAndTagSize(block, gcmMethods["gcm"], len(nonce), tagSize); err == nil {
+			t.Errorf("tag size %d: no error", tagSize)
+		}
+	}
+}
+
+func TestGMAC(t *testing.T) {
+	for i, c := range gcmCases {
+		key, _ := hex.DecodeString(c.key)
+		nonce, _ := hex.DecodeString(c.nonce)
+		aad, _ := hex.DecodeString(c.aad + c.ciphertext)
+		block := newGCMBlock(t, c.sm4, key)
+		aead, _ := NewGCMWithNonceAndTagSize(block, gcmMethods["gcm"], len(nonce), 16)
+		want := aead.Seal(nil, nonce, nil, aad)
+		for name, m := range gcmMethods {
+			tag, err := GMAC(block, m, nonce, aad, 16)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(tag, want) {
+				t.Errorf("case %d %s: got %x, want %x", i, name, tag, want)
+			}
+		}
+	}
+	// GCM spec test case 1, the empty GMAC.
+	key := make([]byte, 16)
+	tag, _ := GMAC(newGCMBlock(t, false, key), gcmMethods["gcm"], make([]byte, 12), nil, 16)
+	if got, want := hex.EncodeToString(tag), gcmCases[0].tag; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkGCM(b *testing.B) {
+	key := make([]byte, 16)
+	nonce := make([]byte, 12)
+	plaintext := make([]byte, 8192)
+	block := newGCMBlock(b, false, key)
+	for name, m := range gcmMethods {
+		b.Run(name, func(b *testing.B) {
+			aead, _ := NewGCM(block, m)
+			dst := make([]byte, 0, len(plaintext)+16)
+			b.SetBytes(int64(len(plaintext)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				aead.Seal(dst[:0], nonce, plaintext, nil)
+			}
+		})
+	}
+}
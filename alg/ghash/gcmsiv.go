@@ -0,0 +1,143 @@
+package ghash
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+)
+
+var errOpen = errors.New("ghash: message authentication failed")
+
+// aesGCMSIV is the RFC 8452 AES-GCM-SIV AEAD, the POLYVAL method is pluggable.
+type aesGCMSIV struct {
+	block      cipher.Block
+	keyLen     int
+	newPolyval func(key []byte) Hasher
+}
+
+// NewAESGCMSIV returns AES-GCM-SIV with a 16 or 32 bytes key,
+// newPolyval creates the POLYVAL method from the per-nonce authentication key,
+// e.g. NewPolyvalMethod or a CLMUL POLYVAL kernel.
+func NewAESGCMSIV(key []byte, newPolyval func(key []byte) Hasher) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, aes.KeySizeError(len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesGCMSIV{block, len(key), newPolyval}, nil
+}
+
+func (g *aesGCMSIV) NonceSize() int {
+	return gcmSIVNonceSize
+}
+
+func (g *aesGCMSIV) Overhead() int {
+	return gcmSIVTagSize
+}
+
+// deriveKeys derives the message authentication and encryption keys from the nonce.
+func (g *aesGCMSIV) deriveKeys(nonce []byte) (authKey []byte, enc cipher.Block) {
+	var in, out [16]byte
+	copy(in[4:], nonce)
+	keys := make([]byte, 0, 16+g.keyLen)
+	for i := 0; i < (16+g.keyLen)/8; i++ {
+		binary.LittleEndian.PutUint32(in[:], uint32(i))
+		g.block.Encrypt(out[:], in[:])
+		keys = append(keys, out[:8]...)
+	}
+	enc, _ = aes.NewCipher(keys[16:])
+	return keys[:16], enc
+}
+
+func (g *aesGCMSIV) tag(authKey []byte, enc cipher.Block, nonce, plaintext, additionalData []byte) (tag [16]byte) {
+	aadLen := (len(additionalData) + 15) &^ 15
+	ptLen := (len(plaintext) + 15) &^ 15
+	data := make([]byte, aadLen+ptLen+16)
+	copy(data, additionalData)
+	copy(data[aadLen:], plaintext)
+	binary.LittleEndian.PutUint64(data[aadLen+ptLen:], uint64(len(additionalData))*8)
+	binary.LittleEndian.PutUint64(data[aadLen+ptLen+8:], uint64(len(plaintext))*8)
+
+	var s [16]byte
+	g.newPolyval(authKey).Hash(&s, data)
+	for i := range nonce {
+		s[i] ^= nonce[i]
+	}
+	s[15] &= 0x7f
+	enc.Encrypt(tag[:], s[:])
+	return
+}
+
+// ctr encrypts in into out, the initial counter block is the tag with the most significant bit set,
+// the counter is the first 32-bit little endian word.
+func (g *aesGCMSIV) ctr(enc cipher.Block, tag *[16]byte, out, in []byte) {
+	var counter, keyStream [16]byte
+	counter = *tag
+	counter[15] |= 0x80
+	for len(in) > 0 {
+		enc.Encrypt(keyStream[:], counter[:])
+		binary.LittleEndian.PutUint32(counter[:], binary.LittleEndian.Uint32(counter[:])+1)
+		n := subtle.XORBytes(out, in, keyStream[:])
+		out, in = out[n:], in[n:]
+	}
+}
+
+func (g *aesGCMSIV) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("ghash: incorrect nonce length given to AES-GCM-SIV")
+	}
+	authKey, enc := g.deriveKeys(nonce)
+	tag := g.tag(authKey, enc, nonce, plaintext, additionalData)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmSIVTagSize)
+	g.ctr(enc, &tag, out, plaintext)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+func (g *aesGCMSIV) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != gcmSIVNonceSize {
+		panic("ghash: incorrect nonce length given to AES-GCM-SIV")
+	}
+	if len(ciphertext) < gcmSIVTagSize {
+		return nil, errOpen
+	}
+	var tag [16]byte
+	copy(tag[:], ciphertext[len(ciphertext)-gcmSIVTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-gcmSIVTagSize]
+
+	authKey, enc := g.deriveKeys(nonce)
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	g.ctr(enc, &tag, out, ciphertext)
+	expectedTag := g.tag(authKey, enc, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(expectedTag[:], tag[:]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, errOpen
+	}
+	return ret, nil
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -1,6 +1,10 @@
 package ghash
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/gf128"
+)
 
 // ref: GCM revised spec.
 // Each element is a vector of 128 bits. The ith bit of an element X is denoted as Xi. The leftmost
@@ -28,31 +32,20 @@ func ghash(mul mulInterface, T *[16]byte, data []byte) {
 
 // rawMethod represents a raw GHASH method, no optimzation.
 type rawMethod struct {
+	tracing
 	key [16]byte
 }
 
 func double(v *[16]byte) {
-	var carryIn byte
-	for j := range v {
-		carryOut := (v[j] << 7) & 0x80
-		v[j] = (v[j] >> 1) + carryIn
-		carryIn = carryOut
-	}
-	if carryIn != 0 {
-		v[0] ^= 0xE1 //  1<<7 | 1<<6 | 1<<5 | 1
-	}
+	gf128.MulXGB(v)
 }
 
 func double4(v *[16]byte) {
-	for i := 0; i < 4; i++ {
-		double(v)
-	}
+	gf128.MulXPowGB(v, 4)
 }
 
 func double8(v *[16]byte) {
-	for i := 0; i < 8; i++ {
-		double(v)
-	}
+	gf128.MulXPowGB(v, 8)
 }
 
 func xor(dst, z, y *[16]byte) {
@@ -66,7 +59,7 @@ func (m *rawMethod) Mul(y *[16]byte) {
 	var z [16]byte
 	for _, i := range m.key {
 		for k := 0; k < 8; k++ {
-			if (i>>(7-k))&1 == 1 {
+			if m.branch("rawMethod", (i>>(7-k))&1 == 1) {
 				xor(&z, &z, y) // z ^= y
 			}
 			double(y)
@@ -98,6 +91,7 @@ func reflect8Bits(i int) int {
 }
 
 type simpleMethod8Bits struct {
+	tracing
 	key [16]byte
 	m   [16][256][16]byte
 }
@@ -140,7 +134,7 @@ func NewSimpleMethod8Bits(key []byte) *simpleMethod8Bits {
 func (m *simpleMethod8Bits) Mul(y *[16]byte) {
 	var z [16]byte
 	for i := 0; i < 16; i++ {
-		xor(&z, &z, &m.m[i][y[i]])
+		xor(&z, &z, &m.m[i][m.lookup("simpleMethod8Bits", int(y[i]))])
 	}
 	copy(y[:], z[:])
 }
@@ -150,6 +144,7 @@ func (m *simpleMethod8Bits) Hash(T *[16]byte, data []byte) {
 }
 
 type simpleMethod4Bits struct {
+	tracing
 	key [16]byte
 	m   [32][16][16]byte
 }
@@ -191,8 +186,8 @@ func NewSimpleMethod4Bits(key []byte) *simpleMethod4Bits {
 func (m *simpleMethod4Bits) Mul(y *[16]byte) {
 	var z [16]byte
 	for i := 0; i < 32; i += 2 {
-		xor(&z, &z, &m.m[i][y[i/2]>>4])
-		xor(&z, &z, &m.m[i+1][y[i/2]&0xf])
+		xor(&z, &z, &m.m[i][m.lookup("simpleMethod4Bits", int(y[i/2]>>4))])
+		xor(&z, &z, &m.m[i+1][m.lookup("simpleMethod4Bits", int(y[i/2]&0xf))])
 	}
 	copy(y[:], z[:])
 }
@@ -202,6 +197,7 @@ func (m *simpleMethod4Bits) Hash(T *[16]byte, data []byte) {
 }
 
 type shoupMethod8Bits struct {
+	tracing
 	key [16]byte
 	m0  [256][16]byte // *H precomputed table
 	r   [256][2]byte  // reduction table
@@ -243,17 +239,17 @@ func (m *shoupMethod8Bits) Mul(y *[16]byte) {
 	var a byte
 	for i := 15; i > 0; i-- {
 		// z = y * H
-		xor(&z, &z, &m.m0[y[i]])
+		xor(&z, &z, &m.m0[m.lookup("shoupMethod8Bits.m0", int(y[i]))])
 
 		// z = z * P^8
-		a = z[15]
+		a = byte(m.lookup("shoupMethod8Bits.r", int(z[15])))
 		for j := 15; j > 0; j-- {
 			z[j] = z[j-1]
 		}
 		z[0] = m.r[a][0]
 		z[1] ^= m.r[a][1]
 	}
-	xor(&z, &z, &m.m0[y[0]])
+	xor(&z, &z, &m.m0[m.lookup("shoupMethod8Bits.m0", int(y[0]))])
 	copy(y[:], z[:])
 }
 
@@ -281,6 +277,7 @@ func (m *shoupMethod8Bits) Hash(T *[16]byte, data []byte) {
 }
 
 type shoupMethod4Bits struct {
+	tracing
 	key [16]byte
 	m0  [16][16]byte // *H precomputed table
 	r   [16][2]byte  // reduction table
@@ -318,7 +315,7 @@ func NewShoupMethod4Bits(key []byte) *shoupMethod4Bits {
 }
 
 func (m *shoupMethod4Bits) double4(v *[16]byte) {
-	a := v[15] & 0xf
+	a := m.lookup("shoupMethod4Bits.r", int(v[15]&0xf))
 	for j := 15; j > 0; j-- {
 		v[j] = (v[j] >> 4) | (v[j-1] << 4)
 	}
@@ -368,14 +365,14 @@ func (m *shoupMethod4Bits) Mul(y *[16]byte) {
 	for i := 15; i >= 1; i-- {
 		w := y[i]
 		for j := 0; j < 2; j++ {
-			xor(&z, &z, &m.m0[w&0xf])
+			xor(&z, &z, &m.m0[m.lookup("shoupMethod4Bits.m0", int(w&0xf))])
 			m.double4(&z)
 			w >>= 4
 		}
 	}
-	xor(&z, &z, &m.m0[y[0]&0xf])
+	xor(&z, &z, &m.m0[m.lookup("shoupMethod4Bits.m0", int(y[0]&0xf))])
 	double4(&z)
-	xor(&z, &z, &m.m0[y[0]>>4])
+	xor(&z, &z, &m.m0[m.lookup("shoupMethod4Bits.m0", int(y[0]>>4))])
 
 	copy(y[:], z[:])
 }
@@ -410,6 +407,7 @@ type gcmFieldElement struct {
 }
 
 type gcmRawMethod struct {
+	tracing
 	key gcmFieldElement
 }
 
@@ -436,11 +434,11 @@ func (m *gcmRawMethod) Mul(y *[16]byte) {
 			word = yField.high
 		}
 		for j := 0; j < 64; j++ {
-			if word>>63 == 1 {
+			if m.branch("gcmRawMethod", word>>63 == 1) {
 				z = gcmAdd(&z, &v)
 			}
 			// double
-			v = gcmDouble(&v)
+			v = gcmDouble(&v, &m.tracing)
 			word <<= 1
 		}
 	}
@@ -454,6 +452,7 @@ func (m *gcmRawMethod) Hash(T *[16]byte, data []byte) {
 
 // It's similar to the Shoup method.
 type gcmMethod struct {
+	tracing
 	productTable [16]gcmFieldElement
 }
 
@@ -471,7 +470,7 @@ func NewGCMMethod(key []byte) *gcmMethod {
 	m.productTable[8] = x
 
 	for j := 4; j > 0; j /= 2 {
-		m.productTable[j] = gcmDouble(&m.productTable[j*2])
+		m.productTable[j] = gcmDouble(&m.productTable[j*2], &m.tracing)
 	}
 
 	for j := 2; j < 16; j *= 2 {
@@ -488,8 +487,8 @@ func gcmAdd(x, y *gcmFieldElement) gcmFieldElement {
 	return gcmFieldElement{x.low ^ y.low, x.high ^ y.high}
 }
 
-// gcmDouble returns the result of doubling an element of GF(2¹²⁸).
-func gcmDouble(x *gcmFieldElement) (double gcmFieldElement) {
+// gcmDouble returns the result of doubling an element of GF(2¹²⁸), t records the reduction branch.
+func gcmDouble(x *gcmFieldElement, t *tracing) (double gcmFieldElement) {
 	msbSet := x.high&1 == 1
 
 	// Because of the bit-ordering, doubling is actually a right shift.
@@ -504,7 +503,7 @@ func gcmDouble(x *gcmFieldElement) (double gcmFieldElement) {
 	// eliminate the term at x^128 which also means subtracting the other
 	// four terms. In characteristic 2 fields, subtraction == addition ==
 	// XOR.
-	if msbSet {
+	if t.branch("gcmDouble", msbSet) {
 		double.low ^= 0xe100000000000000
 	}
 
@@ -527,7 +526,7 @@ func (m *gcmMethod) Mul(y *[16]byte) {
 		// Multiplication works by multiplying z by 16 and adding in
 		// one of the precomputed multiples of H.
 		for j := 0; j < 64; j += 4 {
-			msw := z.high & 0xf
+			msw := m.lookup("gcmReductionTable", int(z.high&0xf))
 			z.high >>= 4
 			z.high |= z.low << 60
 			z.low >>= 4
@@ -536,7 +535,7 @@ func (m *gcmMethod) Mul(y *[16]byte) {
 			// the values in |table| are ordered for
 			// little-endian bit positions. See the comment
 			// in NewGCMWithNonceSize.
-			t := &m.productTable[word&0xf]
+			t := &m.productTable[m.lookup("gcmMethod", int(word&0xf))]
 
 			z.low ^= t.low
 			z.high ^= t.high
@@ -0,0 +1,128 @@
+package ghash
+
+import "encoding/binary"
+
+// ref: RFC 8452, AES-GCM-SIV.
+// POLYVAL works in GF(2^128) defined by x^128 + x^127 + x^126 + x^121 + 1, the bits are stored in
+// little endian order: bit i of byte j is the coefficient of x^(8j+i).
+// POLYVAL(H, X_1, ..., X_n) = S_n, S_0 = 0, S_j = dot(S_(j-1) + X_j, H), dot(a, b) = a * b * x^-128.
+//
+// It relates to GHASH as
+//
+//	POLYVAL(H, X_1, ..., X_n) = ByteReverse(GHASH(mulX_GHASH(ByteReverse(H)), ByteReverse(X_1), ..., ByteReverse(X_n)))
+//	GHASH(H, X_1, ..., X_n) = ByteReverse(POLYVAL(mulX_POLYVAL(ByteReverse(H)), ByteReverse(X_1), ..., ByteReverse(X_n)))
+
+// ByteReverse reverses the byte order of v.
+func ByteReverse(v *[16]byte) {
+	for i, j := 0, 15; i < j; i, j = i+1, j-1 {
+		v[i], v[j] = v[j], v[i]
+	}
+}
+
+// MulXGHASH sets v to v * x in the GHASH field.
+func MulXGHASH(v *[16]byte) {
+	double(v)
+}
+
+// MulXPOLYVAL sets v to v * x in the POLYVAL field.
+func MulXPOLYVAL(v *[16]byte) {
+	lo := binary.LittleEndian.Uint64(v[:8])
+	hi := binary.LittleEndian.Uint64(v[8:])
+	msb := hi >> 63
+	hi = hi<<1 | lo>>63
+	lo <<= 1
+	// x^128 = x^127 + x^126 + x^121 + 1
+	lo ^= msb
+	hi ^= -msb & 0xc200000000000000
+	binary.LittleEndian.PutUint64(v[:8], lo)
+	binary.LittleEndian.PutUint64(v[8:], hi)
+}
+
+// PolyvalKeyToGHASH returns the GHASH key which computes POLYVAL with key h on byte reversed blocks.
+func PolyvalKeyToGHASH(h []byte) (key [16]byte) {
+	copy(key[:], h)
+	ByteReverse(&key)
+	MulXGHASH(&key)
+	return
+}
+
+// GHASHKeyToPolyval returns the POLYVAL key which computes GHASH with key h on byte reversed blocks.
+func GHASHKeyToPolyval(h []byte) (key [16]byte) {
+	copy(key[:], h)
+	ByteReverse(&key)
+	MulXPOLYVAL(&key)
+	return
+}
+
+// polyvalRawMethod represents a raw POLYVAL method, bit by bit multiplication and then
+// Montgomery reduction by x^128.
+type polyvalRawMethod struct {
+	lo, hi uint64
+}
+
+func NewPolyvalRawMethod(key []byte) *polyvalRawMethod {
+	return &polyvalRawMethod{
+		binary.LittleEndian.Uint64(key[:8]),
+		binary.LittleEndian.Uint64(key[8:]),
+	}
+}
+
+// Mul sets y to dot(y, m.key).
+func (m *polyvalRawMethod) Mul(y *[16]byte) {
+	var r [4]uint64
+	a := [4]uint64{binary.LittleEndian.Uint64(y[:8]), binary.LittleEndian.Uint64(y[8:])}
+	for i := 0; i < 128; i++ {
+		word := m.lo
+		if i >= 64 {
+			word = m.hi
+		}
+		if (word>>(i&63))&1 == 1 {
+			for k := range r {
+				r[k] ^= a[k]
+			}
+		}
+		// a <<= 1
+		a[3] = a[3]<<1 | a[2]>>63
+		a[2] = a[2]<<1 | a[1]>>63
+		a[1] = a[1]<<1 | a[0]>>63
+		a[0] <<= 1
+	}
+	// r * x^-128, add P to make r divisible by x and then shift.
+	for i := 0; i < 128; i++ {
+		if r[0]&1 == 1 {
+			r[0] ^= 1
+			r[1] ^= 0xc200000000000000
+			r[2] ^= 1
+		}
+		r[0] = r[0]>>1 | r[1]<<63
+		r[1] = r[1]>>1 | r[2]<<63
+		r[2] = r[2]>>1 | r[3]<<63
+		r[3] >>= 1
+	}
+	binary.LittleEndian.PutUint64(y[:8], r[0])
+	binary.LittleEndian.PutUint64(y[8:], r[1])
+}
+
+func (m *polyvalRawMethod) Hash(T *[16]byte, data []byte) {
+	ghash(m, T, data)
+}
+
+// polyvalMethod computes POLYVAL by the GHASH table method, see the relation above.
+type polyvalMethod struct {
+	m *gcmMethod
+}
+
+func NewPolyvalMethod(key []byte) *polyvalMethod {
+	k := PolyvalKeyToGHASH(key)
+	return &polyvalMethod{NewGCMMethod(k[:])}
+}
+
+func (m *polyvalMethod) Mul(y *[16]byte) {
+	ByteReverse(y)
+	m.m.Mul(y)
+	ByteReverse(y)
+}
+
+func (m *polyvalMethod) Hash(T *[16]byte, data []byte) {
+	ghash(m, T, data)
+}
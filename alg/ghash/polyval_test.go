@@ -0,0 +1,80 @@
+package ghash
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// RFC 8452 appendix A.
+const (
+	polyvalKey  = "25629347589242761d31f826ba4b757b"
+	polyvalData = "4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362"
+	polyvalOut  = "f7a3b47b846119fae5b7866cf5e5b77e"
+)
+
+func newPolyvalMethods(key []byte) map[string]Hasher {
+	return map[string]Hasher{
+		"raw": NewPolyvalRawMethod(key),
+		"gcm": NewPolyvalMethod(key),
+	}
+}
+
+func TestPolyval(t *testing.T) {
+	key, _ := hex.DecodeString(polyvalKey)
+	data, _ := hex.DecodeString(polyvalData)
+	for name, m := range newPolyvalMethods(key) {
+		var T [16]byte
+		m.Hash(&T, data)
+		if got := hex.EncodeToString(T[:]); got != polyvalOut {
+			t.Errorf("%s: got %v, want %v", name, got, polyvalOut)
+		}
+	}
+}
+
+func TestPolyvalKeyToGHASH(t *testing.T) {
+	key, _ := hex.DecodeString(polyvalKey)
+	k := PolyvalKeyToGHASH(key)
+	if got, want := hex.EncodeToString(k[:]), "dcbaa5dd137c188ebb21492c23c9b112"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// POLYVAL -> GHASH -> POLYVAL is the identity.
+	k = GHASHKeyToPolyval(k[:])
+	if got := hex.EncodeToString(k[:]); got != polyvalKey {
+		t.Errorf("round trip: got %v, want %v", got, polyvalKey)
+	}
+}
+
+// GHASH computed by POLYVAL with the converted key on byte reversed blocks.
+func TestGHASHByPolyval(t *testing.T) {
+	for i, c := range streamCases {
+		key, _ := hex.DecodeString(c.key)
+		data, _ := hex.DecodeString(c.aad + c.ct)
+		data = append(data, make([]byte, 15-(len(data)+15)%16)...)
+		for j := 0; j < len(data); j += 16 {
+			ByteReverse((*[16]byte)(data[j:]))
+		}
+		var T1, T2 [16]byte
+		pk := GHASHKeyToPolyval(key)
+		NewPolyvalRawMethod(pk[:]).Hash(&T1, data)
+		ByteReverse(&T1)
+		for j := 0; j < len(data); j += 16 {
+			ByteReverse((*[16]byte)(data[j:]))
+		}
+		NewGCMMethod(key).Hash(&T2, data)
+		if T1 != T2 {
+			t.Errorf("case %d: got %x, want %x", i, T1, T2)
+		}
+	}
+}
+
+func BenchmarkPolyvalRawMethod(b *testing.B) {
+	key, _ := hex.DecodeString(polyvalKey)
+	data := make([]byte, 1024)
+	m := NewPolyvalRawMethod(key)
+	var T [16]byte
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Hash(&T, data)
+	}
+}
@@ -0,0 +1,122 @@
+package ghash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Hasher is implemented by all the GHASH methods, including the CLMUL ones of
+// amd64, arm64 and ppc64. Hash computes GHASH of data from a zero state into T,
+// the trailing partial block is zero padded.
+type Hasher interface {
+	Hash(T *[16]byte, data []byte)
+}
+
+var errAADAfterCiphertext = errors.New("ghash: AAD written after ciphertext")
+
+// Stream is a stateful GHASH which accepts data in arbitrary pieces.
+// The AAD section (WriteAAD) must be written before the ciphertext section (Write),
+// each section is zero padded to a block boundary and Sum appends the 128-bit length block.
+// Stream implements hash.Hash.
+type Stream struct {
+	h            Hasher
+	y            [16]byte
+	buf          [16]byte
+	nbuf         int
+	aadLen       uint64
+	ctLen        uint64
+	inCiphertext bool
+}
+
+// NewStream returns a streaming GHASH on top of h.
+func NewStream(h Hasher) *Stream {
+	return &Stream{h: h}
+}
+
+func (s *Stream) Size() int {
+	return 16
+}
+
+func (s *Stream) BlockSize() int {
+	return 16
+}
+
+func (s *Stream) Reset() {
+	clear(&s.y)
+	clear(&s.buf)
+	s.nbuf = 0
+	s.aadLen = 0
+	s.ctLen = 0
+	s.inCiphertext = false
+}
+
+// update continues GHASH from state y with full blocks,
+// the state is folded into the first block as Hash always starts from zero.
+func (s *Stream) update(y *[16]byte, blocks []byte) {
+	if len(blocks) == 0 {
+		return
+	}
+	data := make([]byte, len(blocks))
+	copy(data, blocks)
+	xor((*[16]byte)(data), (*[16]byte)(data), y)
+	s.h.Hash(y, data)
+}
+
+func (s *Stream) write(p []byte) {
+	if s.nbuf > 0 {
+		n := copy(s.buf[s.nbuf:], p)
+		s.nbuf += n
+		p = p[n:]
+		if s.nbuf < 16 {
+			return
+		}
+		s.update(&s.y, s.buf[:])
+		s.nbuf = 0
+	}
+	full := len(p) &^ 15
+	s.update(&s.y, p[:full])
+	s.nbuf = copy(s.buf[:], p[full:])
+}
+
+// flush zero pads and hashes the buffered partial block.
+func (s *Stream) flush(y *[16]byte) {
+	if s.nbuf == 0 {
+		return
+	}
+	var block [16]byte
+	copy(block[:], s.buf[:s.nbuf])
+	s.update(y, block[:])
+}
+
+// WriteAAD adds more data to the AAD section.
+func (s *Stream) WriteAAD(p []byte) (int, error) {
+	if s.inCiphertext {
+		return 0, errAADAfterCiphertext
+	}
+	s.aadLen += uint64(len(p))
+	s.write(p)
+	return len(p), nil
+}
+
+// Write adds more data to the ciphertext section, the AAD section is closed on the first call.
+func (s *Stream) Write(p []byte) (int, error) {
+	if !s.inCiphertext {
+		s.flush(&s.y)
+		s.nbuf = 0
+		s.inCiphertext = true
+	}
+	s.ctLen += uint64(len(p))
+	s.write(p)
+	return len(p), nil
+}
+
+// Sum appends the GHASH of AAD || ciphertext || lengths to b, it does not change the state.
+func (s *Stream) Sum(b []byte) []byte {
+	y := s.y
+	s.flush(&y)
+	var lens [16]byte
+	binary.BigEndian.PutUint64(lens[:], s.aadLen*8)
+	binary.BigEndian.PutUint64(lens[8:], s.ctLen*8)
+	s.update(&y, lens[:])
+	return append(b, y[:]...)
+}
@@ -0,0 +1,89 @@
+package ghash
+
+import (
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+var _ hash.Hash = (*Stream)(nil)
+
+// GHASH(H, A, C) test vectors from the GCM spec, test case 2, 4 and 6.
+var streamCases = []struct {
+	key string
+	aad string
+	ct  string
+	out string
+}{
+	{
+		"66e94bd4ef8a2c3b884cfa59ca342b2e",
+		"",
+		"0388dace60b6a392f328c2b971b2fe78",
+		"f38cbb1ad69223dcc3457ae5b6b0f885",
+	},
+	{
+		"b83b533708bf535d0aa6e52980d53b78",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091",
+		"698e57f70e6ecc7fd9463b7260a9ae5f",
+	},
+	{
+		"b83b533708bf535d0aa6e52980d53b78",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"8ce24998625615b603a033aca13fb894be9112a5c3a211a8ba262a3cca7e2ca701e4a9a4fba43c90ccdcb281d48c7c6fd62875d2aca417034c34aee5",
+		"1c5afe9760d3932f3c9a878aac3dc3de",
+	},
+}
+
+func newMethods(key []byte) map[string]Hasher {
+	raw := &rawMethod{}
+	copy(raw.key[:], key)
+	return map[string]Hasher{
+		"raw":     raw,
+		"simple8": NewSimpleMethod8Bits(key),
+		"simple4": NewSimpleMethod4Bits(key),
+		"shoup8":  NewShoupMethod8Bits(key),
+		"shoup4":  NewShoupMethod4Bits(key),
+		"gcmRaw":  NewGCMRawMethod(key),
+		"gcm":     NewGCMMethod(key),
+	}
+}
+
+func TestStream(t *testing.T) {
+	for i, c := range streamCases {
+		key, _ := hex.DecodeString(c.key)
+		aad, _ := hex.DecodeString(c.aad)
+		ct, _ := hex.DecodeString(c.ct)
+		for name, m := range newMethods(key) {
+			for _, step := range []int{1, 5, 16, 17, 64} {
+				s := NewStream(m)
+				for p := aad; len(p) > 0; p = p[min(step, len(p)):] {
+					s.WriteAAD(p[:min(step, len(p))])
+				}
+				for p := ct; len(p) > 0; p = p[min(step, len(p)):] {
+					s.Write(p[:min(step, len(p))])
+				}
+				if got := hex.EncodeToString(s.Sum(nil)); got != c.out {
+					t.Errorf("case %d %s step %d: got %v, want %v", i, name, step, got, c.out)
+				}
+				// Sum does not change the state
+				if got := hex.EncodeToString(s.Sum(nil)); got != c.out {
+					t.Errorf("case %d %s step %d: second Sum got %v, want %v", i, name, step, got, c.out)
+				}
+			}
+		}
+	}
+}
+
+func TestStreamAADAfterCiphertext(t *testing.T) {
+	key, _ := hex.DecodeString("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	s := NewStream(NewGCMMethod(key))
+	s.Write([]byte{1})
+	if _, err := s.WriteAAD([]byte{1}); err == nil {
+		t.Error("expected error")
+	}
+	s.Reset()
+	if _, err := s.WriteAAD([]byte{1}); err != nil {
+		t.Error(err)
+	}
+}
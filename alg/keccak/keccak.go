@@ -0,0 +1,57 @@
+// Package keccak is the reference Keccak-f[1600] permutation of FIPS 202, lane x + 5y of the state is A[x, y].
+package keccak
+
+import "math/bits"
+
+// RC holds the round constants of the ι step.
+var RC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// Rotc holds the left rotation of lane x + 5y in the ρ step.
+var Rotc = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// Pi returns the lane which A[x, y] moves to in the π step, B[y, 2x + 3y] = A[x, y].
+func Pi(i int) int {
+	x, y := i%5, i/5
+	return y + 5*((2*x+3*y)%5)
+}
+
+// Round is one round of Keccak-f[1600] with the round constant rc.
+func Round(a *[25]uint64, rc uint64) {
+	var c, d [5]uint64
+	for x := 0; x < 5; x++ {
+		c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+	}
+	for x := 0; x < 5; x++ {
+		d[x] = c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+	}
+	var b [25]uint64
+	for i := range a {
+		b[Pi(i)] = bits.RotateLeft64(a[i]^d[i%5], Rotc[i])
+	}
+	for y := 0; y < 25; y += 5 {
+		for x := 0; x < 5; x++ {
+			a[y+x] = b[y+x] ^ ^b[y+(x+1)%5]&b[y+(x+2)%5]
+		}
+	}
+	a[0] ^= rc
+}
+
+// F1600 is the Keccak-f[1600] permutation.
+func F1600(a *[25]uint64) {
+	for _, rc := range RC {
+		Round(a, rc)
+	}
+}
@@ -0,0 +1,61 @@
+package keccak
+
+import "encoding/binary"
+
+const (
+	// Rate128 is the rate in bytes of SHAKE128.
+	Rate128 = 168
+	// Rate256 is the rate in bytes of SHAKE256.
+	Rate256 = 136
+	// DomainShake is the domain separation byte of SHAKE with the first bit of the padding.
+	DomainShake = 0x1f
+)
+
+// Absorb xors msg with the padding of the domain separation byte ds into the zero state a, the
+// full blocks are permuted, the last padded block is not, Squeeze permutes it.
+func Absorb(a *[25]uint64, rate int, ds byte, msg []byte) {
+	*a = [25]uint64{}
+	for ; len(msg) >= rate; msg = msg[rate:] {
+		for i := 0; i < rate/8; i++ {
+			a[i] ^= binary.LittleEndian.Uint64(msg[8*i:])
+		}
+		F1600(a)
+	}
+	block := make([]byte, rate)
+	copy(block, msg)
+	block[len(msg)] ^= ds
+	block[rate-1] ^= 0x80
+	for i := 0; i < rate/8; i++ {
+		a[i] ^= binary.LittleEndian.Uint64(block[8*i:])
+	}
+}
+
+// Squeeze fills out with the output of the absorbed state a, the state is permuted before each block.
+func Squeeze(a *[25]uint64, rate int, out []byte) {
+	block := make([]byte, rate)
+	for len(out) > 0 {
+		F1600(a)
+		for i := 0; i < rate/8; i++ {
+			binary.LittleEndian.PutUint64(block[8*i:], a[i])
+		}
+		out = out[copy(out, block):]
+	}
+}
+
+// Shake128 returns n bytes of SHAKE128 of msg.
+func Shake128(msg []byte, n int) []byte {
+	var a [25]uint64
+	out := make([]byte, n)
+	Absorb(&a, Rate128, DomainShake, msg)
+	Squeeze(&a, Rate128, out)
+	return out
+}
+
+// Shake256 returns n bytes of SHAKE256 of msg.
+func Shake256(msg []byte, n int) []byte {
+	var a [25]uint64
+	out := make([]byte, n)
+	Absorb(&a, Rate256, DomainShake, msg)
+	Squeeze(&a, Rate256, out)
+	return out
+}
@@ -0,0 +1,44 @@
+package keccak
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+var shakeTests = []struct {
+	in             string
+	out128, out256 string
+}{
+	{
+		"",
+		"7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26",
+		"46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762fd75dc4ddd8c0f200cb05019d67b592f6fc821c49479ab48640292eacb3b7c4be",
+	},
+	{
+		"abc",
+		"5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8",
+		"483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4",
+	},
+	{
+		strings.Repeat("a", 200),
+		"70ac9b97e891be583e08929ce4cce50d346b05f9597356d6af94d4643d2af3b6",
+		"e49647491c9d12d125a2f75826c96f6307d2fabebcbb9fb1616d76b09499380e8bcf60f72750879140e73fb7453a979b69d25efa8de613462f108ce7f2f1d7c5",
+	},
+}
+
+func TestShake(t *testing.T) {
+	for _, tt := range shakeTests {
+		if got := hex.EncodeToString(Shake128([]byte(tt.in), 32)); got != tt.out128 {
+			t.Errorf("Shake128(%q) = %s, want %s", tt.in, got, tt.out128)
+		}
+		if got := hex.EncodeToString(Shake256([]byte(tt.in), 64)); got != tt.out256 {
+			t.Errorf("Shake256(%q) = %s, want %s", tt.in, got, tt.out256)
+		}
+	}
+	// the last 32 bytes of 200 bytes output, two squeezed blocks
+	out := Shake128([]byte("abc"), 200)
+	if got, want := hex.EncodeToString(out[168:]), "6aa01b3f5af057805f973ff8ecb8b226ac32ada6f01c1fcd4818cb006aa5b4cd"; got != want {
+		t.Errorf("Shake128(\"abc\")[168:200] = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+// Package md5 is the reference MD5 of RFC 1321 with a helper to hash a message with a block function.
+package md5
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// Size is the size of a MD5 checksum in bytes.
+	Size = 16
+	// BlockSize is the block size of MD5 in bytes.
+	BlockSize = 64
+)
+
+// IV is the initial state of MD5.
+var IV = [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+
+// T holds the round constants, T[i] = floor(2^32 * |sin(i + 1)|).
+var T = [64]uint32{
+	0xd76aa478, 0xe8c7b756, 0x242070db, 0xc1bdceee, 0xf57c0faf, 0x4787c62a, 0xa8304613, 0xfd469501,
+	0x698098d8, 0x8b44f7af, 0xffff5bb1, 0x895cd7be, 0x6b901122, 0xfd987193, 0xa679438e, 0x49b40821,
+	0xf61e2562, 0xc040b340, 0x265e5a51, 0xe9b6c7aa, 0xd62f105d, 0x02441453, 0xd8a1e681, 0xe7d3fbc8,
+	0x21e1cde6, 0xc33707d6, 0xf4d50d87, 0x455a14ed, 0xa9e3e905, 0xfcefa3f8, 0x676f02d9, 0x8d2a4c8a,
+	0xfffa3942, 0x8771f681, 0x6d9d6122, 0xfde5380c, 0xa4beea44, 0x4bdecfa9, 0xf6bb4b60, 0xbebfbc70,
+	0x289b7ec6, 0xeaa127fa, 0xd4ef3085, 0x04881d05, 0xd9d4d039, 0xe6db99e5, 0x1fa27cf8, 0xc4ac5665,
+	0xf4292244, 0x432aff97, 0xab9423a7, 0xfc93a039, 0x655b59c3, 0x8f0ccc92, 0xffeff47d, 0x85845dd1,
+	0x6fa87e4f, 0xfe2ce6e0, 0xa3014314, 0x4e0811a1, 0xf7537e82, 0xbd3af235, 0x2ad7d2bb, 0xeb86d391,
+}
+
+// S holds the left rotations of the rounds.
+var S = [64]int{
+	7, 12, 17, 22, 7, 12, 17, 22, 7, 12, 17, 22, 7, 12, 17, 22,
+	5, 9, 14, 20, 5, 9, 14, 20, 5, 9, 14, 20, 5, 9, 14, 20,
+	4, 11, 16, 23, 4, 11, 16, 23, 4, 11, 16, 23, 4, 11, 16, 23,
+	6, 10, 15, 21, 6, 10, 15, 21, 6, 10, 15, 21, 6, 10, 15, 21,
+}
+
+// X returns the index of the message word of round i.
+func X(i int) int {
+	switch {
+	case i < 16:
+		return i
+	case i < 32:
+		return (5*i + 1) % 16
+	case i < 48:
+		return (3*i + 5) % 16
+	default:
+		return 7 * i % 16
+	}
+}
+
+// F returns the round function of round i, F, G, H or I of RFC 1321.
+func F(i int, b, c, d uint32) uint32 {
+	switch {
+	case i < 16:
+		return b&c | ^b&d
+	case i < 32:
+		return b&d | c&^d
+	case i < 48:
+		return b ^ c ^ d
+	default:
+		return c ^ (b | ^d)
+	}
+}
+
+// Block is the reference MD5 block function, it compresses each 64 bytes block of p into state.
+func Block(state *[4]uint32, p []byte) {
+	var x [16]uint32
+	for len(p) >= BlockSize {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(p[4*i:])
+		}
+		a, b, c, d := state[0], state[1], state[2], state[3]
+		for i := 0; i < 64; i++ {
+			t := b + bits.RotateLeft32(a+F(i, b, c, d)+x[X(i)]+T[i], S[i])
+			a, b, c, d = d, t, b, c
+		}
+		state[0] += a
+		state[1] += b
+		state[2] += c
+		state[3] += d
+		p = p[BlockSize:]
+	}
+}
+
+// Pad returns msg with the padding of RFC 1321, the length is a multiple of BlockSize.
+func Pad(msg []byte) []byte {
+	n := (len(msg) + 1 + 8 + BlockSize - 1) / BlockSize * BlockSize
+	p := make([]byte, n)
+	copy(p, msg)
+	p[len(msg)] = 0x80
+	binary.LittleEndian.PutUint64(p[n-8:], uint64(len(msg))<<3)
+	return p
+}
+
+// Sum returns the MD5 checksum of msg with the block function.
+func Sum(block func(state *[4]uint32, p []byte), msg []byte) [Size]byte {
+	state := IV
+	block(&state, Pad(msg))
+	var sum [Size]byte
+	for i, v := range state {
+		binary.LittleEndian.PutUint32(sum[4*i:], v)
+	}
+	return sum
+}
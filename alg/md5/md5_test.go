@@ -0,0 +1,35 @@
+package md5
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+)
+
+var md5Tests = []struct {
+	in, out string
+}{
+	// RFC 1321 test suite
+	{"", "d41d8cd98f00b204e9800998ecf8427e"},
+	{"abc", "900150983cd24fb0d6963f7d28e17f72"},
+	{"message digest", "f96b697d7cb7938d525a2f31aaf161d0"},
+	{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "57edf4a22be3c955ac49da2e2107b67a"},
+}
+
+func TestSum(t *testing.T) {
+	for _, tt := range md5Tests {
+		sum := Sum(Block, []byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+	msg := make([]byte, 200)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n++ {
+		if got, want := Sum(Block, msg[:n]), md5.Sum(msg[:n]); got != want {
+			t.Errorf("Sum(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
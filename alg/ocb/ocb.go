@@ -0,0 +1,214 @@
+// Package ocb implements OCB3 (RFC 7253) over a 128-bit block cipher.
+//
+// The offsets are L_ntz(i) of the L table, which is built by multiplications by x
+// (the doubling of RFC 7253), the multiplication is pluggable so the SIMD doubling
+// of every architecture can be validated end to end.
+package ocb
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/gf128"
+)
+
+const (
+	blockSize         = 16
+	standardNonceSize = 12
+	maxNonceSize      = 15
+	standardTagSize   = 16
+)
+
+var errOpen = errors.New("ocb: message authentication failed")
+
+type ocb struct {
+	block     cipher.Block
+	nonceSize int
+	tagSize   int
+	lStar     [blockSize]byte
+	lDollar   [blockSize]byte
+	l         [64][blockSize]byte // L_0, ..., L_63, ntz of a 64-bit block index
+}
+
+// New returns OCB3 with the standard nonce and tag sizes, mulX multiplies a block by x
+// in the big endian convention, nil for gf128.MulXBE.
+func New(block cipher.Block, mulX func(*[16]byte)) (cipher.AEAD, error) {
+	return NewWithNonceAndTagSize(block, mulX, standardNonceSize, standardTagSize)
+}
+
+// NewWithNonceAndTagSize returns OCB3 with the nonce size 1..15 bytes and the tag size 1..16 bytes.
+func NewWithNonceAndTagSize(block cipher.Block, mulX func(*[16]byte), nonceSize, tagSize int) (cipher.AEAD, error) {
+	if block.BlockSize() != blockSize {
+		return nil, errors.New("ocb: requires 128-bit block cipher")
+	}
+	if nonceSize < 1 || nonceSize > maxNonceSize {
+		return nil, errors.New("ocb: incorrect nonce size")
+	}
+	if tagSize < 1 || tagSize > standardTagSize {
+		return nil, errors.New("ocb: incorrect tag size")
+	}
+	if mulX == nil {
+		mulX = gf128.MulXBE
+	}
+	o := &ocb{block: block, nonceSize: nonceSize, tagSize: tagSize}
+	block.Encrypt(o.lStar[:], o.lStar[:])
+	o.lDollar = o.lStar
+	mulX(&o.lDollar)
+	o.l[0] = o.lDollar
+	mulX(&o.l[0])
+	for i := 1; i < len(o.l); i++ {
+		o.l[i] = o.l[i-1]
+		mulX(&o.l[i])
+	}
+	return o, nil
+}
+
+func (o *ocb) NonceSize() int {
+	return o.nonceSize
+}
+
+func (o *ocb) Overhead() int {
+	return o.tagSize
+}
+
+// lNtz returns L_ntz(i), i > 0.
+func (o *ocb) lNtz(i uint64) *[blockSize]byte {
+	return &o.l[bits.TrailingZeros64(i)]
+}
+
+// initialOffset returns Offset_0 of the nonce.
+func (o *ocb) initialOffset(nonce []byte) [blockSize]byte {
+	// Nonce = num2str(TAGLEN mod 128, 7) || zeros(120 - bitlen(N)) || 1 || N
+	var n, ktop [blockSize]byte
+	n[0] = byte(o.tagSize*8%128) << 1
+	n[blockSize-1-len(nonce)] |= 1
+	copy(n[blockSize-len(nonce):], nonce)
+	bottom := uint(n[blockSize-1] & 0x3f)
+	n[blockSize-1] &^= 0x3f
+	o.block.Encrypt(ktop[:], n[:])
+
+	// Stretch = Ktop || (Ktop[1..64] xor Ktop[9..72]), Offset_0 = Stretch[1+bottom..128+bottom]
+	var stretch [blockSize + 8 + 1]byte
+	copy(stretch[:], ktop[:])
+	subtle.XORBytes(stretch[blockSize:blockSize+8], ktop[:8], ktop[1:9])
+	var offset [blockSize]byte
+	byteShift, bitShift := bottom/8, bottom%8
+	for i := range offset {
+		offset[i] = stretch[uint(i)+byteShift]<<bitShift | byte(uint16(stretch[uint(i)+byteShift+1])>>(8-bitShift))
+	}
+	return offset
+}
+
+// hash returns HASH(K, A).
+func (o *ocb) hash(sum *[blockSize]byte, ad []byte) {
+	var offset, t [blockSize]byte
+	clear(sum[:])
+	i := uint64(1)
+	for ; len(ad) >= blockSize; i++ {
+		subtle.XORBytes(offset[:], offset[:], o.lNtz(i)[:])
+		subtle.XORBytes(t[:], ad[:blockSize], offset[:])
+		o.block.Encrypt(t[:], t[:])
+		subtle.XORBytes(sum[:], sum[:], t[:])
+		ad = ad[blockSize:]
+	}
+	if len(ad) > 0 {
+		subtle.XORBytes(offset[:], offset[:], o.lStar[:])
+		clear(t[:])
+		copy(t[:], ad)
+		t[len(ad)] = 0x80
+		subtle.XORBytes(t[:], t[:], offset[:])
+		o.block.Encrypt(t[:], t[:])
+		subtle.XORBytes(sum[:], sum[:], t[:])
+	}
+}
+
+// crypt encrypts or decrypts in to out and returns the tag.
+func (o *ocb) crypt(out, nonce, in, ad []byte, encrypt bool) [blockSize]byte {
+	var checksum, t [blockSize]byte
+	offset := o.initialOffset(nonce)
+	i := uint64(1)
+	for ; len(in) >= blockSize; i++ {
+		subtle.XORBytes(offset[:], offset[:], o.lNtz(i)[:])
+		if encrypt {
+			subtle.XORBytes(checksum[:], checksum[:], in[:blockSize])
+		}
+		subtle.XORBytes(t[:], in[:blockSize], offset[:])
+		if encrypt {
+			o.block.Encrypt(t[:], t[:])
+		} else {
+			o.block.Decrypt(t[:], t[:])
+		}
+		subtle.XORBytes(out[:blockSize], t[:], offset[:])
+		if !encrypt {
+			subtle.XORBytes(checksum[:], checksum[:], out[:blockSize])
+		}
+		in, out = in[blockSize:], out[blockSize:]
+	}
+	if len(in) > 0 {
+		subtle.XORBytes(offset[:], offset[:], o.lStar[:])
+		var pad [blockSize]byte
+		o.block.Encrypt(pad[:], offset[:])
+		subtle.XORBytes(out, in, pad[:len(in)])
+		// Checksum_* = Checksum_m xor (P_* || 1 || zeros(127-bitlen(P_*)))
+		p := in
+		if !encrypt {
+			p = out
+		}
+		clear(t[:])
+		copy(t[:], p)
+		t[len(p)] = 0x80
+		subtle.XORBytes(checksum[:], checksum[:], t[:])
+	}
+	// Tag = ENCIPHER(K, Checksum xor Offset xor L_$) xor HASH(K, A)
+	var tag [blockSize]byte
+	subtle.XORBytes(tag[:], checksum[:], offset[:])
+	subtle.XORBytes(tag[:], tag[:], o.lDollar[:])
+	o.block.Encrypt(tag[:], tag[:])
+	o.hash(&t, ad)
+	subtle.XORBytes(tag[:], tag[:], t[:])
+	return tag
+}
+
+func (o *ocb) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != o.nonceSize {
+		panic("ocb: incorrect nonce length given to OCB")
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+o.tagSize)
+	tag := o.crypt(out, nonce, plaintext, additionalData, true)
+	copy(out[len(plaintext):], tag[:o.tagSize])
+	return ret
+}
+
+func (o *ocb) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != o.nonceSize {
+		panic("ocb: incorrect nonce length given to OCB")
+	}
+	if len(ciphertext) < o.tagSize {
+		return nil, errOpen
+	}
+	tag := ciphertext[len(ciphertext)-o.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-o.tagSize]
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	expected := o.crypt(out, nonce, ciphertext, additionalData, false)
+	if subtle.ConstantTimeCompare(expected[:o.tagSize], tag) != 1 {
+		clear(out)
+		return nil, errOpen
+	}
+	return ret, nil
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
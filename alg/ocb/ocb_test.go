@@ -0,0 +1,120 @@
+package ocb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm4"
+)
+
+// RFC 7253 appendix A, AES-128 with K = 000102030405060708090A0B0C0D0E0F and 128-bit tags.
+var ocbCases = []struct {
+	nonce, ad, plaintext, ciphertext string
+}{
+	{"bbaa99887766554433221100", "", "", "785407bfffc8ad9edcc5520ac9111ee6"},
+	{"bbaa99887766554433221101", "0001020304050607", "0001020304050607", "6820b3657b6f615a5725bda0d3b4eb3a257c9af1f8f03009"},
+	{"bbaa99887766554433221102", "0001020304050607", "", "81017f8203f081277152fade694a0a00"},
+	{"bbaa99887766554433221103", "", "0001020304050607", "45dd69f8f5aae72414054cd1f35d82760b2cd00d2f99bfa9"},
+	{"bbaa99887766554433221104", "000102030405060708090a0b0c0d0e0f", "000102030405060708090a0b0c0d0e0f", "571d535b60b277188be5147170a9a22c3ad7a4ff3835b8c5701c1ccec8fc3358"},
+	{"bbaa99887766554433221105", "000102030405060708090a0b0c0d0e0f", "", "8cf761b6902ef764462ad86498ca6b97"},
+	{"bbaa99887766554433221106", "", "000102030405060708090a0b0c0d0e0f", "5ce88ec2e0692706a915c00aeb8b2396f40e1c743f52436bdf06d8fa1eca343d"},
+	{"bbaa99887766554433221107", "000102030405060708090a0b0c0d0e0f1011121314151617", "000102030405060708090a0b0c0d0e0f1011121314151617", "1ca2207308c87c010756104d8840ce1952f09673a448a122c92c62241051f57356d7f3c90bb0e07f"},
+	{"bbaa99887766554433221108", "000102030405060708090a0b0c0d0e0f1011121314151617", "", "6dc225a071fc1b9f7c69f93b0f1e10de"},
+	{"bbaa99887766554433221109", "", "000102030405060708090a0b0c0d0e0f1011121314151617", "221bd0de7fa6fe993eccd769460a0af2d6cded0c395b1c3ce725f32494b9f914d85c0b1eb38357ff"},
+	{"bbaa9988776655443322110a", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "bd6f6c496201c69296c11efd138a467abd3c707924b964deaffc40319af5a48540fbba186c5553c68ad9f592a79a4240"},
+	{"bbaa9988776655443322110b", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "", "fe80690bee8a485d11f32965bc9d2a32"},
+	{"bbaa9988776655443322110c", "", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "2942bfc773bda23cabc6acfd9bfd5835bd300f0973792ef46040c53f1432bcdfb5e1dde3bc18a5f840b52e653444d5df"},
+}
+
+func TestOCB(t *testing.T) {
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	block, _ := aes.NewCipher(key)
+	aead, err := New(block, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range ocbCases {
+		nonce, _ := hex.DecodeString(c.nonce)
+		ad, _ := hex.DecodeString(c.ad)
+		plaintext, _ := hex.DecodeString(c.plaintext)
+		ct := aead.Seal(nil, nonce, plaintext, ad)
+		if got := hex.EncodeToString(ct); got != c.ciphertext {
+			t.Errorf("case %d: got %v, want %v", i, got, c.ciphertext)
+		}
+		pt, err := aead.Open(nil, nonce, ct, ad)
+		if err != nil || !bytes.Equal(pt, plaintext) {
+			t.Errorf("case %d: Open got %x, %v", i, pt, err)
+		}
+		ct[0] ^= 1
+		if _, err := aead.Open(nil, nonce, ct, ad); err == nil {
+			t.Errorf("case %d: Open accepted a modified ciphertext", i)
+		}
+	}
+}
+
+// TestOCBIterative is the tag length check of RFC 7253 appendix A.
+func TestOCBIterative(t *testing.T) {
+	for _, c := range []struct {
+		tagSize int
+		want    string
+	}{
+		{16, "67e944d23256c5e0b6c61fa22fdf1ea2"},
+		{12, "77a3d8e73589158d25d01209"},
+		{8, "192c9b7bd90ba06a"},
+	} {
+		key := make([]byte, 16)
+		key[15] = byte(c.tagSize * 8)
+		block, _ := aes.NewCipher(key)
+		aead, err := NewWithNonceAndTagSize(block, nil, 12, c.tagSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []byte
+		nonce := make([]byte, 12)
+		for i := 0; i < 128; i++ {
+			s := make([]byte, i)
+			binary.BigEndian.PutUint32(nonce[8:], uint32(3*i+1))
+			out = aead.Seal(out, nonce, s, s)
+			binary.BigEndian.PutUint32(nonce[8:], uint32(3*i+2))
+			out = aead.Seal(out, nonce, s, nil)
+			binary.BigEndian.PutUint32(nonce[8:], uint32(3*i+3))
+			out = aead.Seal(out, nonce, nil, s)
+		}
+		binary.BigEndian.PutUint32(nonce[8:], 385)
+		if got := hex.EncodeToString(aead.Seal(nil, nonce, nil, out)); got != c.want {
+			t.Errorf("tag size %d: got %v, want %v", c.tagSize, got, c.want)
+		}
+	}
+}
+
+func TestSM4OCB(t *testing.T) {
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	block, _ := sm4.NewCipher(key)
+	for _, nonceSize := range []int{1, 8, 12, 15} {
+		aead, err := NewWithNonceAndTagSize(block, nil, nonceSize, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce := make([]byte, nonceSize)
+		for n := 0; n < 100; n += 7 {
+			plaintext := bytes.Repeat([]byte{0xa5}, n)
+			ct := aead.Seal(nil, nonce, plaintext, plaintext[:n/2])
+			pt, err := aead.Open(nil, nonce, ct, plaintext[:n/2])
+			if err != nil || !bytes.Equal(pt, plaintext) {
+				t.Errorf("nonce size %d, length %d: Open got %x, %v", nonceSize, n, pt, err)
+			}
+		}
+	}
+}
+
+func TestNewError(t *testing.T) {
+	block, _ := aes.NewCipher(make([]byte, 16))
+	for _, c := range []struct{ nonceSize, tagSize int }{{0, 16}, {16, 16}, {12, 0}, {12, 17}} {
+		if _, err := NewWithNonceAndTagSize(block, nil, c.nonceSize, c.tagSize); err == nil {
+			t.Errorf("nonce size %d, tag size %d: no error", c.nonceSize, c.tagSize)
+		}
+	}
+}
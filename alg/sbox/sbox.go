@@ -0,0 +1,252 @@
+// Package sbox finds the affine transforms which map an 8-bit S-box onto
+// the AES S-box (AESNI) or the GF(2^8) inversion (GFNI).
+//
+// An S-box S which is affine equivalent to the inversion can be written as
+//
+//	S(x) = M2·f(M1·x+C1)+C2
+//
+// where f is the inversion in the AES field GF(2^8)/(x^8+x^4+x^3+x+1) or the AES S-box itself.
+// Camellia, ARIA S2, SM4 and ZUC S1 S-boxes are such examples.
+package sbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/emmansun/simd/alg/aes"
+	"github.com/emmansun/simd/internal/gf256"
+)
+
+// ErrNotAffineEquivalent is returned if the S-box is not affine equivalent to the GF(2^8) inversion.
+var ErrNotAffineEquivalent = errors.New("sbox: not affine equivalent to GF(2^8) inversion")
+
+// AffineParams are the constants of S(x) = M2·f(M1·x+C1)+C2.
+// M1 and M2 are in the qword layout of GF2P8AFFINEQB and GenLookupTable.
+type AffineParams = gf256.AffineParams
+
+const aesPoly = 0x11b
+
+// aesAffine is the affine transform matrix of AES S-box, AES.SBOX(x) = aesAffine·inv(x)+0x63.
+var aesAffine = gf256.Matrix{0x1f, 0x3e, 0x7c, 0xf8, 0xf1, 0xe3, 0xc7, 0x8f}
+
+var inverse = func() (inv [256]byte) {
+	for i := range inv {
+		inv[i] = gf256.Inv(byte(i), aesPoly)
+	}
+	return
+}()
+
+// partialMap is a linear map known on a subspace only.
+type partialMap struct {
+	val    [256]byte
+	known  [256]bool // domain
+	image  [256]bool // image
+	points []byte    // known domain points
+}
+
+func newPartialMap() *partialMap {
+	p := &partialMap{}
+	p.known[0] = true
+	p.image[0] = true
+	p.points = append(make([]byte, 0, 256), 0)
+	return p
+}
+
+func (p *partialMap) clone() *partialMap {
+	c := *p
+	c.points = append(make([]byte, 0, 256), p.points...)
+	return &c
+}
+
+func (p *partialMap) full() bool {
+	return len(p.points) == 256
+}
+
+// add sets p(x) = y and extends p to the linear span, the new domain points are appended to queue.
+// It returns false if it conflicts with the known values or p is no longer injective.
+func (p *partialMap) add(x, y byte, queue []byte) ([]byte, bool) {
+	if p.known[x] {
+		return queue, p.val[x] == y
+	}
+	if p.image[y] {
+		return queue, false
+	}
+	for _, k := range p.points {
+		n := k ^ x
+		p.val[n] = p.val[k] ^ y
+		p.known[n] = true
+		p.image[p.val[n]] = true
+		p.points = append(p.points, n)
+		queue = append(queue, n)
+	}
+	return queue, true
+}
+
+func (p *partialMap) linearMap() gf256.Matrix {
+	var l gf256.Matrix
+	for j := 0; j < 8; j++ {
+		l[j] = p.val[1<<j]
+	}
+	return l
+}
+
+// equivalence searches linear maps A and B with B(g(x)) = inv(A(x)), so g = B^-1·inv·A.
+type equivalence struct {
+	g, gInv *[256]byte
+}
+
+func (e *equivalence) propagate(a, b *partialMap, queueA []byte) bool {
+	var queueB []byte
+	var ok bool
+	for len(queueA) > 0 || len(queueB) > 0 {
+		for len(queueA) > 0 {
+			x := queueA[0]
+			queueA = queueA[1:]
+			if queueB, ok = b.add(e.g[x], inverse[a.val[x]], queueB); !ok {
+				return false
+			}
+		}
+		for len(queueB) > 0 {
+			y := queueB[0]
+			queueB = queueB[1:]
+			if queueA, ok = a.add(e.gInv[y], inverse[b.val[y]], queueA); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *equivalence) solve(a, b *partialMap) (*partialMap, *partialMap, bool) {
+	if a.full() {
+		return a, b, true
+	}
+	x := 0
+	for a.known[x] {
+		x++
+	}
+	for v := 1; v < 256; v++ {
+		if a.image[v] {
+			continue
+		}
+		a1, b1 := a.clone(), b.clone()
+		queue, _ := a1.add(byte(x), byte(v), nil)
+		if !e.propagate(a1, b1, queue) {
+			continue
+		}
+		if a2, b2, ok := e.solve(a1, b1); ok {
+			return a2, b2, true
+		}
+	}
+	return nil, nil, false
+}
+
+// differentialUniformity returns the max number of solutions of S(x)^S(x^a) = b, a != 0.
+func differentialUniformity(s *[256]byte) int {
+	max := 0
+	for a := 1; a < 256; a++ {
+		var count [256]int
+		for x := 0; x < 256; x++ {
+			count[s[x]^s[x^a]]++
+		}
+		for _, c := range count {
+			if c > max {
+				max = c
+			}
+		}
+	}
+	return max
+}
+
+// FindGFNIParams returns the affine constants of S(x) = M2·inv(M1·x+C1)+C2,
+// inv is the GF(2^8) inversion which GF2P8AFFINEINVQB uses.
+func FindGFNIParams(s *[256]byte) (AffineParams, error) {
+	var sInv [256]byte
+	var seen [256]bool
+	for x, y := range s {
+		if seen[y] {
+			return AffineParams{}, fmt.Errorf("%w: not a permutation, S(%#02x) = S(%#02x)", ErrNotAffineEquivalent, x, sInv[y])
+		}
+		seen[y] = true
+		sInv[y] = byte(x)
+	}
+	// differential uniformity is affine invariant, it's 4 for GF(2^8) inversion.
+	if du := differentialUniformity(s); du != 4 {
+		return AffineParams{}, fmt.Errorf("%w: differential uniformity is %d, want 4", ErrNotAffineEquivalent, du)
+	}
+
+	var g, gInv [256]byte
+	e := &equivalence{&g, &gInv}
+	for d := 0; d < 256; d++ {
+		// S(x) = B^-1·inv(A·(x+d)) + S(d)
+		c := s[d]
+		for x := 0; x < 256; x++ {
+			g[x] = s[x^d] ^ c
+			gInv[g[x]] = byte(x)
+		}
+		// inv(a·x) = a^-1·inv(x), so A(1) = 1 can be assumed.
+		a, b := newPartialMap(), newPartialMap()
+		queue, _ := a.add(1, 1, nil)
+		if !e.propagate(a, b, queue) {
+			continue
+		}
+		a, b, ok := e.solve(a, b)
+		if !ok {
+			continue
+		}
+		m1 := a.linearMap()
+		var m2 gf256.Matrix
+		for j := 0; j < 8; j++ {
+			// B^-1
+			for y := 0; y < 256; y++ {
+				if b.val[y] == 1<<j {
+					m2[j] = byte(y)
+					break
+				}
+			}
+		}
+		return AffineParams{M1: m1.Qword(), M2: m2.Qword(), C1: m1.Apply(byte(d)), C2: c}, nil
+	}
+	return AffineParams{}, fmt.Errorf("%w: no affine transforms found", ErrNotAffineEquivalent)
+}
+
+// FindAESNIParams returns the affine constants of S(x) = M2·AES.SBOX(M1·x+C1)+C2.
+func FindAESNIParams(s *[256]byte) (AffineParams, error) {
+	p, err := FindGFNIParams(s)
+	if err != nil {
+		return p, err
+	}
+	return toAESNIParams(p), nil
+}
+
+// toAESNIParams converts GFNI constants to AESNI constants.
+func toAESNIParams(p AffineParams) AffineParams {
+	// inv(x) = aesAffine^-1·(AES.SBOX(x)+0x63)
+	aesAffineInv, _ := aesAffine.Inverse()
+	m2 := gf256.FromQword(p.M2).Mul(aesAffineInv)
+	p.M2 = m2.Qword()
+	p.C2 ^= m2.Apply(0x63)
+	return p
+}
+
+// Affine computes M·x+c, the same as GF2P8AFFINEQB.
+func Affine(m uint64, x, c byte) byte {
+	return gf256.Affine(m, x, c)
+}
+
+// Check verifies S(x) = M2·f(M1·x+C1)+C2 for all x, f is the AES S-box if aesni is true,
+// otherwise the GF(2^8) inversion.
+func Check(s *[256]byte, p AffineParams, aesni bool) bool {
+	for x := 0; x < 256; x++ {
+		t := Affine(p.M1, byte(x), p.C1)
+		if aesni {
+			t = aes.SBOX[t]
+		} else {
+			t = inverse[t]
+		}
+		if Affine(p.M2, t, p.C2) != s[x] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package sbox
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/emmansun/simd/alg/aes"
+	"github.com/emmansun/simd/alg/camellia"
+	"github.com/emmansun/simd/alg/sm4"
+	"github.com/emmansun/simd/alg/zuc"
+	"github.com/emmansun/simd/internal/gf256"
+)
+
+func randomLinearMap(r *rand.Rand) gf256.Matrix {
+	for {
+		var l gf256.Matrix
+		for j := range l {
+			l[j] = byte(r.Intn(256))
+		}
+		if _, ok := l.Inverse(); ok {
+			return l
+		}
+	}
+}
+
+// randomSbox returns S(x) = L2·f(L1·x+c1)+c2 with random L1, L2, c1 and c2.
+func randomSbox(r *rand.Rand, f func(byte) byte) *[256]byte {
+	l1, l2 := randomLinearMap(r), randomLinearMap(r)
+	c1, c2 := byte(r.Intn(256)), byte(r.Intn(256))
+	var s [256]byte
+	for x := 0; x < 256; x++ {
+		s[x] = l2.Apply(f(l1.Apply(byte(x))^c1)) ^ c2
+	}
+	return &s
+}
+
+// ariaS2 is the S-box S2 of ARIA (RFC 5794), an affine transform of x^247, S1 is the AES S-box.
+// It was checked with an ARIA-128 built on it, which gives the ciphertext of the RFC 5794 example.
+var ariaS2 = [256]byte{
+	0xe2, 0x4e, 0x54, 0xfc, 0x94, 0xc2, 0x4a, 0xcc, 0x62, 0x0d, 0x6a, 0x46, 0x3c, 0x4d, 0x8b, 0xd1,
+	0x5e, 0xfa, 0x64, 0xcb, 0xb4, 0x97, 0xbe, 0x2b, 0xbc, 0x77, 0x2e, 0x03, 0xd3, 0x19, 0x59, 0xc1,
+	0x1d, 0x06, 0x41, 0x6b, 0x55, 0xf0, 0x99, 0x69, 0xea, 0x9c, 0x18, 0xae, 0x63, 0xdf, 0xe7, 0xbb,
+	0x00, 0x73, 0x66, 0xfb, 0x96, 0x4c, 0x85, 0xe4, 0x3a, 0x09, 0x45, 0xaa, 0x0f, 0xee, 0x10, 0xeb,
+	0x2d, 0x7f, 0xf4, 0x29, 0xac, 0xcf, 0xad, 0x91, 0x8d, 0x78, 0xc8, 0x95, 0xf9, 0x2f, 0xce, 0xcd,
+	0x08, 0x7a, 0x88, 0x38, 0x5c, 0x83, 0x2a, 0x28, 0x47, 0xdb, 0xb8, 0xc7, 0x93, 0xa4, 0x12, 0x53,
+	0xff, 0x87, 0x0e, 0x31, 0x36, 0x21, 0x58, 0x48, 0x01, 0x8e, 0x37, 0x74, 0x32, 0xca, 0xe9, 0xb1,
+	0xb7, 0xab, 0x0c, 0xd7, 0xc4, 0x56, 0x42, 0x26, 0x07, 0x98, 0x60, 0xd9, 0xb6, 0xb9, 0x11, 0x40,
+	0xec, 0x20, 0x8c, 0xbd, 0xa0, 0xc9, 0x84, 0x04, 0x49, 0x23, 0xf1, 0x4f, 0x50, 0x1f, 0x13, 0xdc,
+	0xd8, 0xc0, 0x9e, 0x57, 0xe3, 0xc3, 0x7b, 0x65, 0x3b, 0x02, 0x8f, 0x3e, 0xe8, 0x25, 0x92, 0xe5,
+	0x15, 0xdd, 0xfd, 0x17, 0xa9, 0xbf, 0xd4, 0x9a, 0x7e, 0xc5, 0x39, 0x67, 0xfe, 0x76, 0x9d, 0x43,
+	0xa7, 0xe1, 0xd0, 0xf5, 0x68, 0xf2, 0x1b, 0x34, 0x70, 0x05, 0xa3, 0x8a, 0xd5, 0x79, 0x86, 0xa8,
+	0x30, 0xc6, 0x51, 0x4b, 0x1e, 0xa6, 0x27, 0xf6, 0x35, 0xd2, 0x6e, 0x24, 0x16, 0x82, 0x5f, 0xda,
+	0xe6, 0x75, 0xa2, 0xef, 0x2c, 0xb2, 0x1c, 0x9f, 0x5d, 0x6f, 0x80, 0x0a, 0x72, 0x44, 0x9b, 0x6c,
+	0x90, 0x0b, 0x5b, 0x33, 0x7d, 0x5a, 0x52, 0xf3, 0x61, 0xa1, 0xf7, 0xb0, 0xd6, 0x3f, 0x7c, 0x6d,
+	0xed, 0x14, 0xe0, 0xa5, 0x3d, 0x22, 0xb3, 0xf8, 0x89, 0xde, 0x71, 0x1a, 0xaf, 0xba, 0xb5, 0x81,
+}
+
+// inversePermutation returns the inverse of the permutation s.
+func inversePermutation(s *[256]byte) *[256]byte {
+	var inv [256]byte
+	for x, y := range s {
+		inv[y] = byte(x)
+	}
+	return &inv
+}
+
+func testSbox(t *testing.T, name string, s *[256]byte) {
+	t.Helper()
+	tables, err := Compile(s)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if !Check(s, tables.GFNI, false) {
+		t.Fatalf("%s: GFNI params %#v failed", name, tables.GFNI)
+	}
+	if !Check(s, tables.AESNI, true) {
+		t.Fatalf("%s: AESNI params %#v failed", name, tables.AESNI)
+	}
+	// SboxWithAESNI with the nibble tables
+	for x := 0; x < 256; x++ {
+		y := aes.SBOX[tables.M1L[x&0xf]^tables.M1H[x>>4]]
+		if got := tables.M2L[y&0xf] ^ tables.M2H[y>>4]; got != s[x] {
+			t.Fatalf("%s: lookup tables S(%#02x) = %#02x, want %#02x", name, x, got, s[x])
+		}
+	}
+	for _, m := range []struct {
+		reg  [16]byte
+		want uint64
+	}{{tables.GFNIM1, tables.GFNI.M1}, {tables.GFNIM2, tables.GFNI.M2}} {
+		if binary.LittleEndian.Uint64(m.reg[:]) != m.want || binary.LittleEndian.Uint64(m.reg[8:]) != m.want {
+			t.Fatalf("%s: GFNI matrix %x, want %#016x in both qwords", name, m.reg, m.want)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	testSbox(t, "AES", &aes.SBOX)
+	testSbox(t, "SM4", &sm4.SBOX)
+	testSbox(t, "ZUC S1", &zuc.SBOX)
+	testSbox(t, "Camellia s1", &camellia.SBOX1)
+	testSbox(t, "ARIA S1", &aes.SBOX)
+	testSbox(t, "ARIA S2", &ariaS2)
+	testSbox(t, "ARIA S1^-1", inversePermutation(&aes.SBOX))
+	testSbox(t, "ARIA S2^-1", inversePermutation(&ariaS2))
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 4; i++ {
+		testSbox(t, "random", randomSbox(r, func(x byte) byte { return inverse[x] }))
+	}
+	// x^247 = (x^-1)^8, the power function of ARIA S2.
+	pow247 := func(x byte) byte {
+		y := inverse[x]
+		for i := 0; i < 3; i++ {
+			y = gf256.Mul(y, y, aesPoly)
+		}
+		return y
+	}
+	testSbox(t, "x^247", randomSbox(r, pow247))
+}
+
+func TestARIAS2(t *testing.T) {
+	var seen [256]bool
+	for _, y := range ariaS2 {
+		if seen[y] {
+			t.Fatalf("ARIA S2 is not a permutation, %#02x repeats", y)
+		}
+		seen[y] = true
+	}
+	// S2(x) = B·x^247 + 0xe2, column j of B is S2(x) + 0xe2 with x^247 = 2^j
+	var pow247 [256]byte
+	var b gf256.Matrix
+	for x := range pow247 {
+		y := inverse[x]
+		for i := 0; i < 3; i++ {
+			y = gf256.Mul(y, y, aesPoly)
+		}
+		pow247[x] = y
+		for j := range b {
+			if y == 1<<j {
+				b[j] = ariaS2[x] ^ 0xe2
+			}
+		}
+	}
+	for x, y := range pow247 {
+		if got := b.Apply(y) ^ 0xe2; got != ariaS2[x] {
+			t.Fatalf("S2(%#02x) = %#02x, B·x^247+0xe2 = %#02x", x, ariaS2[x], got)
+		}
+	}
+}
+
+func TestCompileNotEquivalent(t *testing.T) {
+	var identity, notPermutation, pow7 [256]byte
+	for x := 0; x < 256; x++ {
+		identity[x] = byte(x)
+		notPermutation[x] = byte(x) | 1
+		y := byte(1)
+		for i := 0; i < 7; i++ {
+			y = gf256.Mul(y, byte(x), aesPoly)
+		}
+		pow7[x] = y
+	}
+	for _, s := range []*[256]byte{&identity, &notPermutation, &pow7} {
+		_, err := Compile(s)
+		if !errors.Is(err, ErrNotAffineEquivalent) {
+			t.Errorf("Compile() = %v; want %v", err, ErrNotAffineEquivalent)
+		}
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Compile(&sm4.SBOX)
+	}
+}
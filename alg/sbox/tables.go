@@ -0,0 +1,49 @@
+package sbox
+
+import "encoding/binary"
+
+// Tables holds the affine constants of an S-box for both AESNI and GFNI, and the operands of the
+// simulated S-box functions built from them, which the architectures load as they are.
+type Tables struct {
+	AESNI AffineParams
+	GFNI  AffineParams
+
+	// M1L, M1H, M2L and M2H are the nibble lookup tables of SboxWithAESNI in amd64/sse, arm64 and ppc64,
+	// in memory order. Byte i is M1·i+C1, M1·(i<<4), M2·i+C2 and M2·(i<<4) of AESNI, the tables
+	// GenLookupTable builds.
+	M1L, M1H, M2L, M2H [16]byte
+	// GFNIM1 and GFNIM2 are the matrix operands m1 and m2 of sse.SBOX in memory order,
+	// GFNI.M1 and GFNI.M2 in both qwords.
+	GFNIM1, GFNIM2 [16]byte
+}
+
+// Compile searches the affine constants of s and builds its tables, it returns an error which wraps
+// ErrNotAffineEquivalent with the reason if s is not affine equivalent to the GF(2^8) inversion.
+func Compile(s *[256]byte) (*Tables, error) {
+	gfni, err := FindGFNIParams(s)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tables{AESNI: toAESNIParams(gfni), GFNI: gfni}
+	t.M1L, t.M1H = lookupTables(t.AESNI.M1, t.AESNI.C1)
+	t.M2L, t.M2H = lookupTables(t.AESNI.M2, t.AESNI.C2)
+	t.GFNIM1 = broadcast(gfni.M1)
+	t.GFNIM2 = broadcast(gfni.M2)
+	return t, nil
+}
+
+// lookupTables returns the tables of M·x+c on the low and high nibbles of x, the constant is in the low one.
+func lookupTables(m uint64, c byte) (l, h [16]byte) {
+	for i := range l {
+		l[i] = Affine(m, byte(i), c)
+		h[i] = Affine(m, byte(i<<4), 0)
+	}
+	return
+}
+
+// broadcast returns the register of m in both qwords.
+func broadcast(m uint64) (r [16]byte) {
+	binary.LittleEndian.PutUint64(r[:], m)
+	binary.LittleEndian.PutUint64(r[8:], m)
+	return
+}
@@ -0,0 +1,77 @@
+// Package sha1 is the reference SHA-1 of FIPS 180-4 with a helper to hash a message with a block function.
+package sha1
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// Size is the size of a SHA-1 checksum in bytes.
+	Size = 20
+	// BlockSize is the block size of SHA-1 in bytes.
+	BlockSize = 64
+)
+
+// IV is the initial hash value of SHA-1.
+var IV = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+// K holds the round constants of the four groups of 20 rounds.
+var K = [4]uint32{0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xca62c1d6}
+
+// F returns the round function of round i.
+func F(i int, b, c, d uint32) uint32 {
+	switch {
+	case i < 20:
+		return b&c | ^b&d
+	case i < 40, i >= 60:
+		return b ^ c ^ d
+	default:
+		return b&c | b&d | c&d
+	}
+}
+
+// Block is the reference SHA-1 block function, it compresses each 64 bytes block of p into state.
+func Block(state *[5]uint32, p []byte) {
+	var w [80]uint32
+	for len(p) >= BlockSize {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint32(p[4*i:])
+		}
+		for i := 16; i < 80; i++ {
+			w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+		}
+		a, b, c, d, e := state[0], state[1], state[2], state[3], state[4]
+		for i := 0; i < 80; i++ {
+			t := bits.RotateLeft32(a, 5) + F(i, b, c, d) + e + w[i] + K[i/20]
+			a, b, c, d, e = t, a, bits.RotateLeft32(b, 30), c, d
+		}
+		state[0] += a
+		state[1] += b
+		state[2] += c
+		state[3] += d
+		state[4] += e
+		p = p[BlockSize:]
+	}
+}
+
+// Pad returns msg with the padding of FIPS 180-4, the length is a multiple of BlockSize.
+func Pad(msg []byte) []byte {
+	n := (len(msg) + 1 + 8 + BlockSize - 1) / BlockSize * BlockSize
+	p := make([]byte, n)
+	copy(p, msg)
+	p[len(msg)] = 0x80
+	binary.BigEndian.PutUint64(p[n-8:], uint64(len(msg))<<3)
+	return p
+}
+
+// Sum returns the SHA-1 checksum of msg with the block function.
+func Sum(block func(state *[5]uint32, p []byte), msg []byte) [Size]byte {
+	state := IV
+	block(&state, Pad(msg))
+	var sum [Size]byte
+	for i, v := range state {
+		binary.BigEndian.PutUint32(sum[4*i:], v)
+	}
+	return sum
+}
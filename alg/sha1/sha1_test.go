@@ -0,0 +1,34 @@
+package sha1
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+)
+
+var sha1Tests = []struct {
+	in, out string
+}{
+	{"", "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+	// FIPS 180-4 examples
+	{"abc", "a9993e364706816aba3e25717850c26c9cd0d89d"},
+	{"abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", "84983e441c3bd26ebaae4aa1f95129e5e54670f1"},
+}
+
+func TestSum(t *testing.T) {
+	for _, tt := range sha1Tests {
+		sum := Sum(Block, []byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+	msg := make([]byte, 200)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n++ {
+		if got, want := Sum(Block, msg[:n]), sha1.Sum(msg[:n]); got != want {
+			t.Errorf("Sum(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package sha2
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Sigma0 is Σ0 of SHA-256.
+func Sigma0(x uint32) uint32 {
+	return bits.RotateLeft32(x, -2) ^ bits.RotateLeft32(x, -13) ^ bits.RotateLeft32(x, -22)
+}
+
+// Sigma1 is Σ1 of SHA-256.
+func Sigma1(x uint32) uint32 {
+	return bits.RotateLeft32(x, -6) ^ bits.RotateLeft32(x, -11) ^ bits.RotateLeft32(x, -25)
+}
+
+// SmallSigma0 is σ0 of the SHA-256 message schedule.
+func SmallSigma0(x uint32) uint32 {
+	return bits.RotateLeft32(x, -7) ^ bits.RotateLeft32(x, -18) ^ x>>3
+}
+
+// SmallSigma1 is σ1 of the SHA-256 message schedule.
+func SmallSigma1(x uint32) uint32 {
+	return bits.RotateLeft32(x, -17) ^ bits.RotateLeft32(x, -19) ^ x>>10
+}
+
+func Ch(x, y, z uint32) uint32 {
+	return x&y ^ ^x&z
+}
+
+func Maj(x, y, z uint32) uint32 {
+	return x&y ^ x&z ^ y&z
+}
+
+// Block256 is the reference SHA-256 block function, it compresses each 64 bytes block of p into state.
+func Block256(state *[8]uint32, p []byte) {
+	var w [64]uint32
+	for len(p) >= 64 {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint32(p[4*i:])
+		}
+		for i := 16; i < 64; i++ {
+			w[i] = w[i-16] + SmallSigma0(w[i-15]) + w[i-7] + SmallSigma1(w[i-2])
+		}
+		a, b, c, d, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+		for i := 0; i < 64; i++ {
+			t1 := h + Sigma1(e) + Ch(e, f, g) + K256[i] + w[i]
+			t2 := Sigma0(a) + Maj(a, b, c)
+			h, g, f, e, d, c, b, a = g, f, e, d+t1, c, b, a, t1+t2
+		}
+		state[0] += a
+		state[1] += b
+		state[2] += c
+		state[3] += d
+		state[4] += e
+		state[5] += f
+		state[6] += g
+		state[7] += h
+		p = p[64:]
+	}
+}
+
+// Sigma0_512 is Σ0 of SHA-512.
+func Sigma0_512(x uint64) uint64 {
+	return bits.RotateLeft64(x, -28) ^ bits.RotateLeft64(x, -34) ^ bits.RotateLeft64(x, -39)
+}
+
+// Sigma1_512 is Σ1 of SHA-512.
+func Sigma1_512(x uint64) uint64 {
+	return bits.RotateLeft64(x, -14) ^ bits.RotateLeft64(x, -18) ^ bits.RotateLeft64(x, -41)
+}
+
+// SmallSigma0_512 is σ0 of the SHA-512 message schedule.
+func SmallSigma0_512(x uint64) uint64 {
+	return bits.RotateLeft64(x, -1) ^ bits.RotateLeft64(x, -8) ^ x>>7
+}
+
+// SmallSigma1_512 is σ1 of the SHA-512 message schedule.
+func SmallSigma1_512(x uint64) uint64 {
+	return bits.RotateLeft64(x, -19) ^ bits.RotateLeft64(x, -61) ^ x>>6
+}
+
+// Block512 is the reference SHA-512 block function, it compresses each 128 bytes block of p into state.
+func Block512(state *[8]uint64, p []byte) {
+	var w [80]uint64
+	for len(p) >= 128 {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint64(p[8*i:])
+		}
+		for i := 16; i < 80; i++ {
+			w[i] = w[i-16] + SmallSigma0_512(w[i-15]) + w[i-7] + SmallSigma1_512(w[i-2])
+		}
+		a, b, c, d, e, f, g, h := state[0], state[1], state[2], state[3], state[4], state[5], state[6], state[7]
+		for i := 0; i < 80; i++ {
+			t1 := h + Sigma1_512(e) + (e&f ^ ^e&g) + K512[i] + w[i]
+			t2 := Sigma0_512(a) + (a&b ^ a&c ^ b&c)
+			h, g, f, e, d, c, b, a = g, f, e, d+t1, c, b, a, t1+t2
+		}
+		state[0] += a
+		state[1] += b
+		state[2] += c
+		state[3] += d
+		state[4] += e
+		state[5] += f
+		state[6] += g
+		state[7] += h
+		p = p[128:]
+	}
+}
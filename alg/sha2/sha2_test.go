@@ -0,0 +1,56 @@
+package sha2
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+var sha256Tests = []struct {
+	in, out string
+}{
+	{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	// FIPS 180-4 examples
+	{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	{"abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", "248d6a61d20638b8e5c026930c3e6039a33ce45964ff2167f6ecedd419db06c1"},
+}
+
+func TestSum256(t *testing.T) {
+	for _, tt := range sha256Tests {
+		sum := Sum256(Block256, []byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum256(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+	for n := 0; n < 200; n++ {
+		msg := []byte(strings.Repeat("a", n))
+		if got, want := Sum256(Block256, msg), sha256.Sum256(msg); got != want {
+			t.Errorf("Sum256(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+var sha512Tests = []struct {
+	in, out string
+}{
+	{"", "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"},
+	// FIPS 180-4 examples
+	{"abc", "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+}
+
+func TestSum512(t *testing.T) {
+	for _, tt := range sha512Tests {
+		sum := Sum512(Block512, []byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum512(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+	for n := 0; n < 300; n++ {
+		msg := []byte(strings.Repeat("a", n))
+		if got, want := Sum512(Block512, msg), sha512.Sum512(msg); got != want {
+			t.Errorf("Sum512(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sm3
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Block is the reference compression function of GB/T 32905, it compresses each 64 bytes block of p into state.
+func Block(state *[8]uint32, p []byte) {
+	var w [68]uint32
+	for len(p) >= BlockSize {
+		for i := 0; i < 16; i++ {
+			w[i] = binary.BigEndian.Uint32(p[4*i:])
+		}
+		for i := 16; i < 68; i++ {
+			w[i] = P1(w[i-16]^w[i-9]^bits.RotateLeft32(w[i-3], 15)) ^ bits.RotateLeft32(w[i-13], 7) ^ w[i-6]
+		}
+		s := *state
+		for i := 0; i < 64; i++ {
+			Round(i, &s, w[i], w[i]^w[i+4])
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[BlockSize:]
+	}
+}
+
+// Round performs the round i on the state s = [A, B, C, D, E, F, G, H], w is W[i] and w1 is W'[i] = W[i] ^ W[i+4].
+//
+// The SIMD block functions of the arch packages without the SM3 extensions vectorize the message
+// expansion only: each step computes W[i+16..i+19] of the vectors W[i..i+3], ..., W[i+12..i+15] with
+//
+//	W[j] = P1(W[j-16] ^ W[j-9] ^ (W[j-3] <<< 15)) ^ (W[j-13] <<< 7) ^ W[j-6]
+//
+// and stores W[i..i+3] and W'[i..i+3] to a stack, which the four scalar Round calls of the step read.
+// W[i+19] depends on W[i+16] of the same step, its lane is computed without the term W[i+16] <<< 15
+// first and fixed with P1(W[i+16] <<< 15) = (W[i+16] <<< 15) ^ (W[i+16] <<< 30) ^ (W[i+16] <<< 6).
+func Round(i int, s *[8]uint32, w, w1 uint32) {
+	a, b, c, d, e, f, g, h := s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7]
+	t := uint32(CONST0)
+	if i >= 16 {
+		t = CONST1
+	}
+	ss1 := bits.RotateLeft32(bits.RotateLeft32(a, 12)+e+bits.RotateLeft32(t, i), 7)
+	ss2 := ss1 ^ bits.RotateLeft32(a, 12)
+	tt1 := FF(byte(i), a, b, c) + d + ss2 + w1
+	tt2 := GG(byte(i), e, f, g) + h + ss1 + w
+	s[0], s[1], s[2], s[3] = tt1, a, bits.RotateLeft32(b, 9), c
+	s[4], s[5], s[6], s[7] = P0(tt2), e, bits.RotateLeft32(f, 19), g
+}
@@ -0,0 +1,104 @@
+package sm3
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	// Size is the size of a SM3 checksum in bytes.
+	Size = 32
+	// BlockSize is the block size of SM3 in bytes.
+	BlockSize = 64
+)
+
+// BlockFunc compresses each 64 bytes block of p into state, the length of p is a multiple of BlockSize.
+// The sm3block functions of the SIMD packages are block functions.
+type BlockFunc func(state *[8]uint32, p []byte)
+
+type digest struct {
+	h     [8]uint32
+	x     [BlockSize]byte
+	nx    int
+	len   uint64
+	block BlockFunc
+}
+
+// New returns a new SM3 hash.Hash over the block function, nil means the reference Block.
+func New(block BlockFunc) hash.Hash {
+	if block == nil {
+		block = Block
+	}
+	d := &digest{block: block}
+	d.Reset()
+	return d
+}
+
+// Sum returns the SM3 checksum of data with the reference Block.
+func Sum(data []byte) [Size]byte {
+	var sum [Size]byte
+	d := New(nil)
+	d.Write(data)
+	d.Sum(sum[:0])
+	return sum
+}
+
+func (d *digest) Reset() {
+	d.h = IV
+	d.nx = 0
+	d.len = 0
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.x[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+		if d.nx < BlockSize {
+			return n, nil
+		}
+		d.block(&d.h, d.x[:])
+		d.nx = 0
+	}
+	if m := len(p) &^ (BlockSize - 1); m > 0 {
+		d.block(&d.h, p[:m])
+		p = p[m:]
+	}
+	d.nx = copy(d.x[:], p)
+	return n, nil
+}
+
+// Sum appends the checksum to in, it does not change the state of d.
+func (d *digest) Sum(in []byte) []byte {
+	d0 := *d
+	return d0.checkSum(in)
+}
+
+func (d *digest) checkSum(in []byte) []byte {
+	// padding: 0x80, zeros up to 56 mod 64, then the bit length
+	var tmp [BlockSize + 8]byte
+	tmp[0] = 0x80
+	n := 56 - d.len%BlockSize
+	if d.len%BlockSize >= 56 {
+		n += BlockSize
+	}
+	binary.BigEndian.PutUint64(tmp[n:], d.len<<3)
+	d.Write(tmp[:n+8])
+	if d.nx != 0 {
+		panic("sm3: d.nx != 0")
+	}
+	for _, v := range d.h {
+		in = binary.BigEndian.AppendUint32(in, v)
+	}
+	return in
+}
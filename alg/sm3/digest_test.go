@@ -0,0 +1,65 @@
+package sm3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+var sm3Tests = []struct {
+	in, out string
+}{
+	{"", "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+	// GB/T 32905 Appendix A
+	{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+	{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+}
+
+func TestSum(t *testing.T) {
+	for _, tt := range sm3Tests {
+		sum := Sum([]byte(tt.in))
+		if got := hex.EncodeToString(sum[:]); got != tt.out {
+			t.Errorf("Sum(%q) = %s, want %s", tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	for _, tt := range sm3Tests {
+		want, _ := hex.DecodeString(tt.out)
+		d := New(nil)
+		for i := 0; i < len(tt.in); i++ {
+			d.Write([]byte{tt.in[i]})
+		}
+		if got := d.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("byte by byte %q = %x, want %x", tt.in, got, want)
+		}
+		// Sum does not change the state
+		if got := d.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("second Sum of %q = %x, want %x", tt.in, got, want)
+		}
+		d.Reset()
+		d.Write([]byte(tt.in))
+		if got := d.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("after Reset %q = %x, want %x", tt.in, got, want)
+		}
+	}
+}
+
+func TestPadding(t *testing.T) {
+	// the padding boundaries, all lengths split in two writes must give the one shot checksum
+	msg := make([]byte, 3*BlockSize)
+	for i := range msg {
+		msg[i] = byte(i * 7)
+	}
+	for n := 0; n <= len(msg); n++ {
+		want := Sum(msg[:n])
+		d := New(nil)
+		d.Write(msg[:n/3])
+		d.Write(msg[n/3 : n])
+		if got := d.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("length %d: got %x, want %x", n, got, want)
+		}
+	}
+}
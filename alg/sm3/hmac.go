@@ -0,0 +1,11 @@
+package sm3
+
+import (
+	"crypto/hmac"
+	"hash"
+)
+
+// NewHMAC returns a new HMAC-SM3 hash.Hash with the key over the block function, nil means the reference Block.
+func NewHMAC(block BlockFunc, key []byte) hash.Hash {
+	return hmac.New(func() hash.Hash { return New(block) }, key)
+}
@@ -0,0 +1,63 @@
+package sm3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// hmacReference is HMAC of RFC 2104 with Sum.
+func hmacReference(key, msg []byte) []byte {
+	if len(key) > BlockSize {
+		sum := Sum(key)
+		key = sum[:]
+	}
+	ipad, opad := make([]byte, BlockSize), make([]byte, BlockSize)
+	copy(ipad, key)
+	copy(opad, key)
+	for i := range ipad {
+		ipad[i] ^= 0x36
+		opad[i] ^= 0x5c
+	}
+	inner := Sum(append(ipad, msg...))
+	outer := Sum(append(opad, inner[:]...))
+	return outer[:]
+}
+
+func TestHMAC(t *testing.T) {
+	msg := []byte("what do ya want for nothing?")
+	for _, n := range []int{0, 4, 32, 64, 65, 100} {
+		key := make([]byte, n)
+		for i := range key {
+			key[i] = byte(i + 1)
+		}
+		h := NewHMAC(nil, key)
+		h.Write(msg)
+		want := hmacReference(key, msg)
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("key length %d: got %x, want %x", n, got, want)
+		}
+	}
+}
+
+// The inputs of the RFC 4231 test cases 1, 2, 3 and 6 with HMAC-SM3, the outputs agree with
+// OpenSSL 3.0 and Node.js.
+var hmacTests = []struct {
+	key, msg, out string
+}{
+	{strings.Repeat("\x0b", 20), "Hi There", "51b00d1fb49832bfb01c3ce27848e59f871d9ba938dc563b338ca964755cce70"},
+	{"Jefe", "what do ya want for nothing?", "2e87f1d16862e6d964b50a5200bf2b10b764faa9680a296a2405f24bec39f882"},
+	{strings.Repeat("\xaa", 20), strings.Repeat("\xdd", 50), "dd9421e1c725bdf52ec1aa34edadb3c97f5951a83a2fa93f73a7902bc1dcc777"},
+	{strings.Repeat("\xaa", 131), "Test Using Larger Than Block-Size Key - Hash Key First", "b4fd844e13342002f0b2e0690ea7741f1497d993a70494cea601e657bedf67a0"},
+}
+
+func TestHMACVectors(t *testing.T) {
+	for i, tt := range hmacTests {
+		h := NewHMAC(nil, []byte(tt.key))
+		h.Write([]byte(tt.msg))
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.out {
+			t.Errorf("case %d: got %s, want %s", i, got, tt.out)
+		}
+	}
+}
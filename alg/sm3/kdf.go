@@ -0,0 +1,75 @@
+package sm3
+
+import "encoding/binary"
+
+// KDF is the key derivation function of GB/T 32918.3 (SM2), it returns the first keyLen bytes of
+// H(z || 1) || H(z || 2) || ..., the counter is a 32 bits big-endian integer.
+func KDF(block BlockFunc, z []byte, keyLen int) []byte {
+	k := make([]byte, 0, keyLen+Size)
+	var ct [4]byte
+	d := New(block)
+	for i := uint32(1); len(k) < keyLen; i++ {
+		binary.BigEndian.PutUint32(ct[:], i)
+		d.Reset()
+		d.Write(z)
+		d.Write(ct[:])
+		k = d.Sum(k)
+	}
+	return k[:keyLen]
+}
+
+// MultiBlockFunc compresses the blocks of len(state) messages of the same length, state[k] is the
+// state of message p[k]. avx2.SM3MultiBlock8 and arm64.SM3MultiBlock4 are such functions.
+type MultiBlockFunc func(state [][8]uint32, p [][]byte)
+
+// KDFMultiBuffer is KDF with the counter blocks hashed in parallel, lanes counters per call of mb.
+// The blocks of z before the counter are the same for all counters and compressed once.
+// It panics if lanes is not positive.
+func KDFMultiBuffer(mb MultiBlockFunc, lanes int, z []byte, keyLen int) []byte {
+	if lanes <= 0 {
+		panic("sm3: KDFMultiBuffer needs at least one lane")
+	}
+	state := make([][8]uint32, lanes)
+	p := make([][]byte, lanes)
+	for i := range state {
+		state[i] = IV
+	}
+	m := len(z) &^ (BlockSize - 1)
+	if m > 0 {
+		for i := range p {
+			p[i] = z[:m]
+		}
+		mb(state, p)
+	}
+	prefix := state[0]
+
+	// the tail of each message is z[m:] || counter || padding, one or two blocks
+	tailLen := len(z) - m + 4
+	n := BlockSize
+	if tailLen+9 > BlockSize {
+		n = 2 * BlockSize
+	}
+	tails := make([]byte, lanes*n)
+	for i := range p {
+		t := tails[i*n : (i+1)*n]
+		copy(t, z[m:])
+		t[tailLen] = 0x80
+		binary.BigEndian.PutUint64(t[n-8:], uint64(len(z)+4)<<3)
+		p[i] = t
+	}
+
+	k := make([]byte, 0, keyLen+lanes*Size)
+	for ct := uint32(1); len(k) < keyLen; ct += uint32(lanes) {
+		for i := range p {
+			binary.BigEndian.PutUint32(p[i][tailLen-4:], ct+uint32(i))
+			state[i] = prefix
+		}
+		mb(state, p)
+		for i := range state {
+			for _, v := range state[i] {
+				k = binary.BigEndian.AppendUint32(k, v)
+			}
+		}
+	}
+	return k[:keyLen]
+}
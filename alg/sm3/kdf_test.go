@@ -0,0 +1,68 @@
+package sm3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+// serialMultiBlock is a MultiBlockFunc which compresses the lanes one by one.
+func serialMultiBlock(state [][8]uint32, p [][]byte) {
+	for i := range state {
+		Block(&state[i], p[i])
+	}
+}
+
+func TestKDF(t *testing.T) {
+	z := []byte("the shared secret of the key exchange")
+	var want []byte
+	for ct := uint32(1); len(want) < 100; ct++ {
+		sum := Sum(binary.BigEndian.AppendUint32(append([]byte{}, z...), ct))
+		want = append(want, sum[:]...)
+	}
+	for _, n := range []int{0, 1, 16, 32, 33, 64, 100} {
+		if got := KDF(nil, z, n); !bytes.Equal(got, want[:n]) {
+			t.Errorf("KDF(%d) = %x, want %x", n, got, want[:n])
+		}
+	}
+}
+
+// GB/T 32918.4-2016 appendix A.2: t = KDF(x2 || y2, 152) is C2 xor M with M = "encryption standard".
+func TestKDFVector(t *testing.T) {
+	z, _ := hex.DecodeString("64d20d27d0632957f8028c1e024f6b02edf23102a566c932ae8bd613a8e865fe" +
+		"58d225eca784ae300a81a2d48281a828e1cedf11c4219099840265375077bf78")
+	want := "006e30dae231b071dfad8aa379e90264491603"
+	if got := hex.EncodeToString(KDF(nil, z, 19)); got != want {
+		t.Errorf("KDF = %s, want %s", got, want)
+	}
+	if got := hex.EncodeToString(KDFMultiBuffer(serialMultiBlock, 4, z, 19)); got != want {
+		t.Errorf("KDFMultiBuffer = %s, want %s", got, want)
+	}
+}
+
+func TestKDFMultiBufferLanes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("KDFMultiBuffer with 0 lanes did not panic")
+		}
+	}()
+	KDFMultiBuffer(serialMultiBlock, 0, nil, 32)
+}
+
+func TestKDFMultiBuffer(t *testing.T) {
+	z := make([]byte, 200)
+	for i := range z {
+		z[i] = byte(i*7 + 3)
+	}
+	for _, lanes := range []int{1, 4, 8} {
+		for zl := 0; zl <= len(z); zl += 7 {
+			for _, n := range []int{0, 16, 32, 100, 257} {
+				want := KDF(nil, z[:zl], n)
+				if got := KDFMultiBuffer(serialMultiBlock, lanes, z[:zl], n); !bytes.Equal(got, want) {
+					t.Fatalf("lanes %d, z length %d, key length %d: got %x, want %x", lanes, zl, n, got, want)
+				}
+			}
+		}
+	}
+}
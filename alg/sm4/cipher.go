@@ -0,0 +1,68 @@
+package sm4
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"strconv"
+)
+
+// BlockSize is the SM4 block size in bytes.
+const BlockSize = 16
+
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "sm4: invalid key size " + strconv.Itoa(int(k))
+}
+
+// sm4Cipher is the reference SM4 block cipher with T and T_KEY.
+type sm4Cipher struct {
+	enc [32]uint32
+	dec [32]uint32
+}
+
+// NewCipher creates and returns a new cipher.Block.
+func NewCipher(key []byte) (cipher.Block, error) {
+	if len(key) != 16 {
+		return nil, KeySizeError(len(key))
+	}
+	c := &sm4Cipher{}
+	var k [4]uint32
+	for i := 0; i < 4; i++ {
+		k[i] = binary.BigEndian.Uint32(key[4*i:]) ^ FK[i]
+	}
+	for i := 0; i < 32; i++ {
+		k[i&3] ^= T_KEY(k[(i+1)&3] ^ k[(i+2)&3] ^ k[(i+3)&3] ^ CK[i])
+		c.enc[i] = k[i&3]
+		c.dec[31-i] = k[i&3]
+	}
+	return c, nil
+}
+
+func (c *sm4Cipher) BlockSize() int {
+	return BlockSize
+}
+
+func crypt(rk *[32]uint32, dst, src []byte) {
+	if len(src) < BlockSize || len(dst) < BlockSize {
+		panic("sm4: input not full block")
+	}
+	var x [4]uint32
+	for i := 0; i < 4; i++ {
+		x[i] = binary.BigEndian.Uint32(src[4*i:])
+	}
+	for i := 0; i < 32; i++ {
+		x[i&3] ^= T(x[(i+1)&3] ^ x[(i+2)&3] ^ x[(i+3)&3] ^ rk[i])
+	}
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint32(dst[4*i:], x[3-i])
+	}
+}
+
+func (c *sm4Cipher) Encrypt(dst, src []byte) {
+	crypt(&c.enc, dst, src)
+}
+
+func (c *sm4Cipher) Decrypt(dst, src []byte) {
+	crypt(&c.dec, dst, src)
+}
@@ -0,0 +1,34 @@
+package sm4
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// GB/T 32907-2016 appendix A.
+func TestCipher(t *testing.T) {
+	key, _ := hex.DecodeString("0123456789abcdeffedcba9876543210")
+	want := "681edf34d206965e86b3e94f536e4246"
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dst [16]byte
+	c.Encrypt(dst[:], key)
+	if got := hex.EncodeToString(dst[:]); got != want {
+		t.Errorf("Encrypt = %v; want %v", got, want)
+	}
+	c.Decrypt(dst[:], dst[:])
+	if got := hex.EncodeToString(dst[:]); got != hex.EncodeToString(key) {
+		t.Errorf("Decrypt = %v; want %x", got, key)
+	}
+	// 1,000,000 times
+	src := key
+	for i := 0; i < 1000000; i++ {
+		c.Encrypt(dst[:], src)
+		src = dst[:]
+	}
+	if got, want := hex.EncodeToString(dst[:]), "595298c7c6fd271f0402f804c33d3f66"; got != want {
+		t.Errorf("Encrypt 1000000 times = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package amd64
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/aes"
+	"github.com/emmansun/simd/amd64/sse"
+)
+
+type aesniCipher struct {
+	enc []sse.XMM
+	dec []sse.XMM // round keys of the equivalent inverse cipher
+}
+
+// NewAESCipher returns the AES-128/192/256 cipher with AES-NI, the key schedule uses AESKEYGENASSIST and AESIMC.
+func NewAESCipher(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, aes.KeySizeError(len(key))
+	}
+	c := &aesniCipher{}
+	c.expandKey(key)
+	return c, nil
+}
+
+func (c *aesniCipher) expandKey(key []byte) {
+	nk := len(key) / 4
+	nr := nk + 6
+	w := make([]uint32, 4*(nr+1))
+	for i := 0; i < nk; i++ {
+		// the dwords of the key as MOVOU loads them
+		w[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	T := &sse.XMM{}
+	for i := nk; i < len(w); i++ {
+		// the previous word is the dword X1 of AESKEYGENASSIST
+		*T = sse.SetEpi32(0, w[i-1], 0, 0)
+		switch {
+		case i%nk == 0:
+			sse.AESKEYGENASSIST(T, T, aes.Rcon[i/nk-1])
+			w[i] = w[i-nk] ^ T.Uint32s()[1]
+		case nk > 6 && i%nk == 4:
+			sse.AESKEYGENASSIST(T, T, 0)
+			w[i] = w[i-nk] ^ T.Uint32s()[0]
+		default:
+			w[i] = w[i-nk] ^ w[i-1]
+		}
+	}
+	c.enc = make([]sse.XMM, nr+1)
+	c.dec = make([]sse.XMM, nr+1)
+	for r := range c.enc {
+		c.enc[r] = sse.SetEpi32(w[4*r], w[4*r+1], w[4*r+2], w[4*r+3])
+	}
+	c.dec[0] = c.enc[nr]
+	for r := 1; r < nr; r++ {
+		sse.AESIMC(&c.dec[r], &c.enc[nr-r])
+	}
+	c.dec[nr] = c.enc[0]
+}
+
+func (c *aesniCipher) BlockSize() int {
+	return 16
+}
+
+func (c *aesniCipher) Encrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("amd64: input not full block")
+	}
+	nr := len(c.enc) - 1
+	X := &sse.XMM{}
+	sse.SetBytes(X, src[:16])
+	sse.PXOR(X, &c.enc[0])
+	for r := 1; r < nr; r++ {
+		sse.AESENC(X, &c.enc[r])
+	}
+	sse.AESENCLAST(X, &c.enc[nr])
+	copy(dst, X.Bytes())
+}
+
+func (c *aesniCipher) Decrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("amd64: input not full block")
+	}
+	nr := len(c.dec) - 1
+	X := &sse.XMM{}
+	sse.SetBytes(X, src[:16])
+	sse.PXOR(X, &c.dec[0])
+	for r := 1; r < nr; r++ {
+		sse.AESDEC(X, &c.dec[r])
+	}
+	sse.AESDECLAST(X, &c.dec[nr])
+	copy(dst, X.Bytes())
+}
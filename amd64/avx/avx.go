@@ -4,39 +4,88 @@ import (
 	"encoding/binary"
 
 	"github.com/emmansun/simd/amd64/sse"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/opcount"
 )
 
+// counter records the executed instructions, see SetCounter.
+var counter *opcount.Counter
+
+// SetCounter makes the AVX, SM3 and SM4 instructions of this package record themselves into c, nil
+// stops the recording. The SSE instructions record into the counter of sse.SetCounter. The recording
+// is not safe for concurrent use.
+func SetCounter(c *opcount.Counter) {
+	counter = c
+}
+
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes VPSHUFB report the indices of its table lookup, the shuffle mask, to t, nil
+// stops the reporting. The reporting is not safe for concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
 func VPXOR(dst, src1, src2 *sse.XMM) {
+	counter.Op("VPXOR", dst, src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.Bytes()[i] = src1.Bytes()[i] ^ src2.Bytes()[i]
 	}
 }
 
 func VMOVDQU(dst *sse.XMM, src *sse.XMM) {
+	counter.Op("VMOVDQU", dst, src)
+	vmovdqu(dst, src)
+}
+
+// vmovdqu is VMOVDQU without the recording, for the instructions which compute into a temporary register.
+func vmovdqu(dst, src *sse.XMM) {
 	copy(dst.Bytes(), src.Bytes())
 }
 
 func VMOVDQU_L16B(dst *sse.XMM, src []byte) {
+	counter.Op("VMOVDQU", dst)
 	copy(dst.Bytes(), src)
 }
 
 func VMOVEDQU_S16B(dst []byte, src *sse.XMM) {
+	counter.Op("VMOVDQU", nil, src)
 	copy(dst, src.Bytes())
 }
 
 func VMOVDQU_L4S(dst *sse.XMM, src []uint32) {
+	counter.Op("VMOVDQU", dst)
+	vmovdqu_l4s(dst, src)
+}
+
+// vmovdqu_l4s is VMOVDQU_L4S without the recording, for the instructions which compute their words first.
+func vmovdqu_l4s(dst *sse.XMM, src []uint32) {
 	for i := 0; i < 4; i++ {
 		binary.LittleEndian.PutUint32(dst.Bytes()[i*4:], src[i])
 	}
 }
 
 func VMOVDQU_S4S(dst []uint32, src *sse.XMM) {
+	counter.Op("VMOVDQU", nil, src)
 	for i := 0; i < 4; i++ {
 		dst[i] = binary.LittleEndian.Uint32(src.Bytes()[i*4:])
 	}
 }
 
 func VPSHUFB(dst, src1, src2 *sse.XMM) {
+	counter.Op("VPSHUFB", dst, src1, src2)
+	lookup("VPSHUFB", src2.Bytes())
 	tmp := sse.XMM{}
 	tmpBytes := tmp.Bytes()
 	src1Bytes := src1.Bytes()
@@ -49,10 +98,11 @@ func VPSHUFB(dst, src1, src2 *sse.XMM) {
 			tmpBytes[i] = src1Bytes[idx]
 		}
 	}
-	VMOVDQU(dst, &tmp)
+	vmovdqu(dst, &tmp)
 }
 
 func VPSHUFD(dst, src *sse.XMM, imm uint) {
+	counter.Op("VPSHUFD", dst, src)
 	tmp := &sse.XMM{}
 	srcBytes := src.Bytes()
 	tmpBytes := tmp.Bytes()
@@ -60,10 +110,11 @@ func VPSHUFD(dst, src *sse.XMM, imm uint) {
 		idx := (imm >> (i * 2)) & 0x03
 		copy(tmpBytes[i*4:], srcBytes[idx*4:])
 	}
-	VMOVDQU(dst, tmp)
+	vmovdqu(dst, tmp)
 }
 
 func VPUNPCKLQDQ(dst, src1, src2 *sse.XMM) {
+	counter.Op("VPUNPCKLQDQ", dst, src1, src2)
 	tmp := &sse.XMM{}
 	src1Bytes := src1.Bytes()
 	src2Bytes := src2.Bytes()
@@ -72,10 +123,11 @@ func VPUNPCKLQDQ(dst, src1, src2 *sse.XMM) {
 		tmpBytes[i] = src1Bytes[i]
 		tmpBytes[i+8] = src2Bytes[i]
 	}
-	VMOVDQU(dst, tmp)
+	vmovdqu(dst, tmp)
 }
 
 func VPUNPCKHQDQ(dst, src1, src2 *sse.XMM) {
+	counter.Op("VPUNPCKHQDQ", dst, src1, src2)
 	tmp := &sse.XMM{}
 	src1Bytes := src1.Bytes()
 	src2Bytes := src2.Bytes()
@@ -84,10 +136,11 @@ func VPUNPCKHQDQ(dst, src1, src2 *sse.XMM) {
 		tmpBytes[i] = src1Bytes[i+8]
 		tmpBytes[i+8] = src2Bytes[i+8]
 	}
-	VMOVDQU(dst, tmp)
+	vmovdqu(dst, tmp)
 }
 
 func VPSRLD(dst, src *sse.XMM, imm uint) {
+	counter.Op("VPSRLD", dst, src)
 	e0 := binary.LittleEndian.Uint32(src.Bytes()[:])
 	e1 := binary.LittleEndian.Uint32(src.Bytes()[4:])
 	e2 := binary.LittleEndian.Uint32(src.Bytes()[8:])
@@ -110,6 +163,7 @@ func VPSRLD(dst, src *sse.XMM, imm uint) {
 }
 
 func VPSLLD(dst, src *sse.XMM, imm uint) {
+	counter.Op("VPSLLD", dst, src)
 	e0 := binary.LittleEndian.Uint32(src.Bytes()[:])
 	e1 := binary.LittleEndian.Uint32(src.Bytes()[4:])
 	e2 := binary.LittleEndian.Uint32(src.Bytes()[8:])
@@ -132,6 +186,7 @@ func VPSLLD(dst, src *sse.XMM, imm uint) {
 }
 
 func VPBLENDD(dst, src1, src2 *sse.XMM, imm uint) {
+	counter.Op("VPBLENDD", dst, src1, src2)
 	src1Words := src1.Uint32s()
 	src2Words := src2.Uint32s()
 	dstBytes := dst.Bytes()
@@ -146,6 +201,7 @@ func VPBLENDD(dst, src1, src2 *sse.XMM, imm uint) {
 }
 
 func VPALIGNR(dst, src1, src2 *sse.XMM, imm8 byte) {
+	counter.Op("VPALIGNR", dst, src1, src2)
 	tmp := &sse.XMM{}
 	src1Bytes := src1.Bytes()
 	src2Bytes := src2.Bytes()
@@ -157,10 +213,11 @@ func VPALIGNR(dst, src1, src2 *sse.XMM, imm8 byte) {
 	for i := 0; i < int(imm8); i++ {
 		tmpBytes[16-int(imm8)+i] = src1Bytes[i]
 	}
-	VMOVDQU(dst, tmp)
+	vmovdqu(dst, tmp)
 }
 
 func VPSRLDQ(dst, src *sse.XMM, imm8 byte) {
+	counter.Op("VPSRLDQ", dst, src)
 	tmp := &sse.XMM{}
 	srcBytes := src.Bytes()
 	tmpBytes := tmp.Bytes()
@@ -170,5 +227,19 @@ func VPSRLDQ(dst, src *sse.XMM, imm8 byte) {
 	for i := 0; i < 16-int(imm8); i++ {
 		tmpBytes[i] = srcBytes[i+int(imm8)]
 	}
-	VMOVDQU(dst, tmp)
+	vmovdqu(dst, tmp)
+}
+
+func VPSLLDQ(dst, src *sse.XMM, imm8 byte) {
+	counter.Op("VPSLLDQ", dst, src)
+	tmp := &sse.XMM{}
+	srcBytes := src.Bytes()
+	tmpBytes := tmp.Bytes()
+	if imm8 > 16 {
+		imm8 = 16
+	}
+	for i := int(imm8); i < 16; i++ {
+		tmpBytes[i] = srcBytes[i-int(imm8)]
+	}
+	vmovdqu(dst, tmp)
 }
@@ -0,0 +1,191 @@
+package avx
+
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/sm3"
+	"github.com/emmansun/simd/amd64/sse"
+)
+
+// SM3 without the SM3 extensions, with the vector message expansion of sm3.Round as the production
+// assembly does: each step computes four words with VPALIGNR/VPSHUFB and shifts, the dword lane 0
+// is W[i]. SM3BlockSSSE3 is the same sequence with the destructive two operands forms, which need
+// MOVOU copies to keep their sources.
+
+// vprold rotates each dword of src left by n bits, t is a temporary register.
+func vprold(dst, src, t *sse.XMM, n uint) {
+	VPSLLD(t, src, n)
+	VPSRLD(dst, src, 32-n)
+	VPXOR(dst, dst, t)
+}
+
+// messageSchedule computes out = W[i+16..i+19] of w0 = W[i..i+3], ..., w3 = W[i+12..i+15], see sm3.Round.
+// VPSLLDQ moves W[i+16] alone into lane 3 for the fix of W[i+19].
+func messageSchedule(out, w0, w1, w2, w3, t0, t1, t2 *sse.XMM) {
+	VPALIGNR(t0, w2, w1, 12) // t0 = W[-9] = W10 W9 W8 W7
+	VPXOR(t0, t0, w0)        // t0 = W[-9] ^ W[-16]
+	VPSRLDQ(t1, w3, 4)       // t1 = W[-3] = 0 W15 W14 W13
+	vprold(t1, t1, t2, 15)
+	VPXOR(t0, t0, t1) // t0 = W[-16] ^ W[-9] ^ (W[-3] <<< 15), lane 3 lacks W16 <<< 15
+
+	vprold(t1, t0, t2, 15)
+	vprold(out, t0, t2, 23)
+	VPXOR(out, out, t1)
+	VPXOR(out, out, t0) // out = P1(t0)
+
+	VPALIGNR(t0, w1, w0, 12) // t0 = W[-13] = W6 W5 W4 W3
+	vprold(t0, t0, t2, 7)
+	VPXOR(out, out, t0)
+	VPALIGNR(t0, w3, w2, 8) // t0 = W[-6] = W13 W12 W11 W10
+	VPXOR(out, out, t0)     // out = W19' W18 W17 W16
+
+	VPSLLDQ(t0, out, 12) // t0 = W16 0 0 0
+	vprold(t1, t0, t2, 15)
+	VPXOR(out, out, t1)
+	vprold(t1, t0, t2, 30)
+	VPXOR(out, out, t1)
+	vprold(t1, t0, t2, 6)
+	VPXOR(out, out, t1) // out = W19 W18 W17 W16
+}
+
+// SM3BlockAVX is the SM3 block function with the AVX message expansion and scalar rounds.
+func SM3BlockAVX(state *[8]uint32, p []byte) {
+	var (
+		X0        = &sse.XMM{}
+		X1        = &sse.XMM{}
+		X2        = &sse.XMM{}
+		X3        = &sse.XMM{}
+		X4        = &sse.XMM{}
+		XWORD     = &sse.XMM{}
+		XTMP0     = &sse.XMM{}
+		XTMP1     = &sse.XMM{}
+		XTMP2     = &sse.XMM{}
+		flip_mask = &sse.XMM{}
+	)
+	var wt, wt1 [4]uint32 // W[i..i+3] and W'[i..i+3] of the step
+	VMOVDQU_L16B(flip_mask, []byte{0x03, 0x02, 0x01, 0x00, 0x07, 0x06, 0x05, 0x04, 0x0b, 0x0a, 0x09, 0x08, 0x0f, 0x0e, 0x0d, 0x0c})
+
+	for len(p) >= 64 {
+		s := *state
+		VMOVDQU_L16B(X0, p)
+		VMOVDQU_L16B(X1, p[16:])
+		VMOVDQU_L16B(X2, p[32:])
+		VMOVDQU_L16B(X3, p[48:])
+		VPSHUFB(X0, X0, flip_mask)
+		VPSHUFB(X1, X1, flip_mask)
+		VPSHUFB(X2, X2, flip_mask)
+		VPSHUFB(X3, X3, flip_mask)
+
+		w := [5]*sse.XMM{X0, X1, X2, X3, X4}
+		for i := 0; i < 64; i += 4 {
+			// W[i+16..i+19] is needed up to W[67]
+			if i < 52 {
+				messageSchedule(w[4], w[0], w[1], w[2], w[3], XTMP0, XTMP1, XTMP2)
+			}
+			VPXOR(XWORD, w[0], w[1]) // W'[i..i+3]
+			VMOVDQU_S4S(wt[:], w[0])
+			VMOVDQU_S4S(wt1[:], XWORD)
+			for j := 0; j < 4; j++ {
+				sm3.Round(i+j, &s, wt[j], wt1[j])
+			}
+			w = [5]*sse.XMM{w[1], w[2], w[3], w[4], w[0]}
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[64:]
+	}
+}
+
+// prold rotates each dword of x left by n bits in place, t is a temporary register.
+func prold(x, t *sse.XMM, n uint) {
+	sse.MOVOU(t, x)
+	sse.PSLLD(t, n)
+	sse.PSRLD(x, 32-n)
+	sse.PXOR(x, t)
+}
+
+// messageScheduleSSSE3 is messageSchedule with the two operands instructions, w0, ..., w3 are kept.
+func messageScheduleSSSE3(out, w0, w1, w2, w3, t0, t1, t2 *sse.XMM) {
+	sse.MOVOU(t0, w2)
+	sse.PALIGNR(t0, w1, 12) // t0 = W[-9] = W10 W9 W8 W7
+	sse.PXOR(t0, w0)        // t0 = W[-9] ^ W[-16]
+	sse.MOVOU(t1, w3)
+	sse.PSRLDQ(t1, 4) // t1 = W[-3] = 0 W15 W14 W13
+	prold(t1, t2, 15)
+	sse.PXOR(t0, t1) // t0 = W[-16] ^ W[-9] ^ (W[-3] <<< 15), lane 3 lacks W16 <<< 15
+
+	sse.MOVOU(out, t0)
+	sse.MOVOU(t1, t0)
+	prold(t1, t2, 15)
+	sse.PXOR(out, t1)
+	prold(t1, t2, 8)  // t1 = t0 <<< 23
+	sse.PXOR(out, t1) // out = P1(t0)
+
+	sse.MOVOU(t0, w1)
+	sse.PALIGNR(t0, w0, 12) // t0 = W[-13] = W6 W5 W4 W3
+	prold(t0, t2, 7)
+	sse.PXOR(out, t0)
+	sse.MOVOU(t0, w3)
+	sse.PALIGNR(t0, w2, 8) // t0 = W[-6] = W13 W12 W11 W10
+	sse.PXOR(out, t0)      // out = W19' W18 W17 W16
+
+	sse.MOVOU(t0, out)
+	sse.PSLLDQ(t0, 12) // t0 = W16 0 0 0
+	prold(t0, t2, 15)
+	sse.PXOR(out, t0)
+	prold(t0, t2, 15) // t0 = W16 <<< 30
+	sse.PXOR(out, t0)
+	prold(t0, t2, 8)  // t0 = W16 <<< 6
+	sse.PXOR(out, t0) // out = W19 W18 W17 W16
+}
+
+// SM3BlockSSSE3 is the SM3 block function with the SSSE3 message expansion and scalar rounds.
+func SM3BlockSSSE3(state *[8]uint32, p []byte) {
+	var (
+		X0        = &sse.XMM{}
+		X1        = &sse.XMM{}
+		X2        = &sse.XMM{}
+		X3        = &sse.XMM{}
+		X4        = &sse.XMM{}
+		XWORD     = &sse.XMM{}
+		XTMP0     = &sse.XMM{}
+		XTMP1     = &sse.XMM{}
+		XTMP2     = &sse.XMM{}
+		flip_mask = &sse.XMM{}
+	)
+	var wt, wt1 [16]byte // W[i..i+3] and W'[i..i+3] of the step
+	sse.SetBytes(flip_mask, []byte{0x03, 0x02, 0x01, 0x00, 0x07, 0x06, 0x05, 0x04, 0x0b, 0x0a, 0x09, 0x08, 0x0f, 0x0e, 0x0d, 0x0c})
+
+	for len(p) >= 64 {
+		s := *state
+		sse.SetBytes(X0, p)
+		sse.SetBytes(X1, p[16:])
+		sse.SetBytes(X2, p[32:])
+		sse.SetBytes(X3, p[48:])
+		sse.PSHUFB(X0, flip_mask)
+		sse.PSHUFB(X1, flip_mask)
+		sse.PSHUFB(X2, flip_mask)
+		sse.PSHUFB(X3, flip_mask)
+
+		w := [5]*sse.XMM{X0, X1, X2, X3, X4}
+		for i := 0; i < 64; i += 4 {
+			// W[i+16..i+19] is needed up to W[67]
+			if i < 52 {
+				messageScheduleSSSE3(w[4], w[0], w[1], w[2], w[3], XTMP0, XTMP1, XTMP2)
+			}
+			sse.MOVOU(XWORD, w[0])
+			sse.PXOR(XWORD, w[1]) // W'[i..i+3]
+			sse.StoreBytes(wt[:], w[0])
+			sse.StoreBytes(wt1[:], XWORD)
+			for j := 0; j < 4; j++ {
+				sm3.Round(i+j, &s, binary.LittleEndian.Uint32(wt[4*j:]), binary.LittleEndian.Uint32(wt1[4*j:]))
+			}
+			w = [5]*sse.XMM{w[1], w[2], w[3], w[4], w[0]}
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[64:]
+	}
+}
@@ -0,0 +1,57 @@
+package avx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm3"
+	"github.com/emmansun/simd/amd64/sse"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
+	"github.com/emmansun/simd/internal/sm3test"
+)
+
+var sm3Kernels = []sm3test.Kernel{
+	{Name: "sm3ni", Func: sm3block},
+	{Name: "avx", Func: SM3BlockAVX, ScalarRounds: 64},
+	{Name: "ssse3", Func: SM3BlockSSSE3, ScalarRounds: 64},
+}
+
+func TestSM3BlockAVX(t *testing.T) {
+	sm3test.TestBlockFunc(t, SM3BlockAVX)
+}
+
+func TestSM3BlockSSSE3(t *testing.T) {
+	sm3test.TestBlockFunc(t, SM3BlockSSSE3)
+}
+
+func TestSM3BlockCounts(t *testing.T) {
+	sm3test.TestCounts(t, sm3Kernels, SetCounter, sse.SetCounter)
+}
+
+func BenchmarkSM3Block(b *testing.B) {
+	sm3test.BenchmarkBlockFuncs(b, sm3Kernels, SetCounter, sse.SetCounter)
+}
+
+func TestLookupTracer(t *testing.T) {
+	sm3Block := func(f sm3.BlockFunc) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			state := sm3.IV
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(&state, bytes.Repeat(secret, 4))
+		}
+	}
+	lookup := func(tr *ctcheck.Tracer, secret []byte) {
+		x := &sse.XMM{}
+		VMOVDQU_L16B(x, secret)
+		SetTracer(tr)
+		defer SetTracer(nil)
+		VPSHUFB(x, &sse.XMM{}, x)
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "sm3ni", Run: sm3Block(sm3block)},
+		{Name: "avx", Run: sm3Block(SM3BlockAVX)},
+		{Name: "nibble lookup", Site: "VPSHUFB", Run: lookup},
+	})
+}
@@ -12,6 +12,7 @@ import (
 // The VSM3MSG1 instruction is one of the two SM3 message scheduling instructions.
 // The instruction performs an initial calculation for the next four SM3 message words.
 func VSM3MSG1(srcdst, src1, src2 *sse.XMM) {
+	counter.Op("VSM3MSG1", srcdst, srcdst, src1, src2)
 	w0_3 := src2.Uint32s()
 	w7_10 := srcdst.Uint32s()
 	w13_15 := src1.Uint32s()
@@ -21,13 +22,14 @@ func VSM3MSG1(srcdst, src1, src2 *sse.XMM) {
 	tmp2 := w7_10[2] ^ w0_3[2] ^ bits.RotateLeft32(w13_15[2], 15)
 	tmp3 := w7_10[3] ^ w0_3[3]
 
-	VMOVDQU_L4S(srcdst, []uint32{sm3.P1(tmp0), sm3.P1(tmp1), sm3.P1(tmp2), sm3.P1(tmp3)})
+	vmovdqu_l4s(srcdst, []uint32{sm3.P1(tmp0), sm3.P1(tmp1), sm3.P1(tmp2), sm3.P1(tmp3)})
 }
 
 // Perform Final Calculation for the Next Four SM3 Message Words
 // The VSM3MSG2 instruction is one of the two SM3 message scheduling instructions.
 // The instruction performs the final calculation for the next four SM3 message words
 func VSM3MSG2(srcdst, src1, src2 *sse.XMM) {
+	counter.Op("VSM3MSG2", srcdst, srcdst, src1, src2)
 	wtmp := srcdst.Uint32s()
 	w3_6 := src1.Uint32s()
 	w10_13 := src2.Uint32s()
@@ -39,7 +41,7 @@ func VSM3MSG2(srcdst, src1, src2 *sse.XMM) {
 
 	w19 ^= bits.RotateLeft32(w16, 6) ^ bits.RotateLeft32(w16, 15) ^ bits.RotateLeft32(w16, 30)
 
-	VMOVDQU_L4S(srcdst, []uint32{w16, w17, w18, w19})
+	vmovdqu_l4s(srcdst, []uint32{w16, w17, w18, w19})
 }
 
 func SM3MSG(out, w0, w1, w2, w3, t0, t1 *sse.XMM) {
@@ -62,6 +64,7 @@ func SM3MSG(out, w0, w1, w2, w3, t0, t1 *sse.XMM) {
 // computation masks the imm8 value by AND’ing it with 0x3E so that only even round numbers from 0 through 62
 // are used for this operation.
 func VSM3RNDS2(srcdst, src1, src2 *sse.XMM, imm8 byte) {
+	counter.Op("VSM3RNDS2", srcdst, srcdst, src1, src2)
 	var A, B, C, D, E, F, G, H [3]uint32
 	var W [6]uint32
 	FEBA := src1.Uint32s()
@@ -109,7 +112,7 @@ func VSM3RNDS2(srcdst, src1, src2 *sse.XMM, imm8 byte) {
 		E[i+1] = sm3.P0(T2)
 		CONST = bits.RotateLeft32(CONST, 1)
 	}
-	VMOVDQU_L4S(srcdst, []uint32{F[2], E[2], B[2], A[2]})
+	vmovdqu_l4s(srcdst, []uint32{F[2], E[2], B[2], A[2]})
 }
 
 func SM3RNDS4(stateABEF, stateCDGH, w0, w4, t *sse.XMM, imm8 byte) {
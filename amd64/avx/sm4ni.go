@@ -12,10 +12,11 @@ import (
 // The VSM4KEY4 instruction performs four rounds of SM4 key expansion.
 // The instruction operates on independent 128-bit lanes.
 func VSM4KEY4(dst, src1, src2 *sse.XMM) {
+	counter.Op("VSM4KEY4", dst, src1, src2)
 	keyBytes := src2.Bytes()
 	roundresult := &sse.XMM{}
 	roundresultBytes := roundresult.Bytes()
-	VMOVDQU(roundresult, src1)
+	vmovdqu(roundresult, src1)
 	for i := 0; i < 16; i += 4 {
 		ck := binary.LittleEndian.Uint32(keyBytes[i:])
 		b0 := binary.LittleEndian.Uint32(roundresultBytes[:])
@@ -28,17 +29,18 @@ func VSM4KEY4(dst, src1, src2 *sse.XMM) {
 		binary.LittleEndian.PutUint32(roundresultBytes[8:], b3)
 		binary.LittleEndian.PutUint32(roundresultBytes[12:], intval)
 	}
-	VMOVDQU(dst, roundresult)
+	vmovdqu(dst, roundresult)
 }
 
 // Performs Four Rounds of SM4 Encryption
 // The SM4RNDS4 instruction performs four rounds of SM4 encryption.
 // The instruction operates on independent 128-bit lanes.
 func VSM4RNDS4(dst, src1, src2 *sse.XMM) {
+	counter.Op("VSM4RNDS4", dst, src1, src2)
 	ckBytes := src2.Bytes()
 	roundresult := &sse.XMM{}
 	roundresultBytes := roundresult.Bytes()
-	VMOVDQU(roundresult, src1)
+	vmovdqu(roundresult, src1)
 	for i := 0; i < 16; i += 4 {
 		rk := binary.LittleEndian.Uint32(ckBytes[i:])
 		b0 := binary.LittleEndian.Uint32(roundresultBytes[:])
@@ -51,7 +53,7 @@ func VSM4RNDS4(dst, src1, src2 *sse.XMM) {
 		binary.LittleEndian.PutUint32(roundresultBytes[8:], b3)
 		binary.LittleEndian.PutUint32(roundresultBytes[12:], intval)
 	}
-	VMOVDQU(dst, roundresult)
+	vmovdqu(dst, roundresult)
 }
 
 func ExpandKey(out []uint32, key []byte) {
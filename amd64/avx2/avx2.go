@@ -4,8 +4,28 @@ import (
 	"encoding/binary"
 
 	"github.com/emmansun/simd/amd64/sse"
+	"github.com/emmansun/simd/internal/ctcheck"
 )
 
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes VPSHUFB and VPERMD report the indices of their table lookups, the shuffle mask
+// and the dword indices, to t, nil stops the reporting. The reporting is not safe for concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
 type YMM struct {
 	bytes [32]byte
 }
@@ -202,12 +222,14 @@ func VPERMD(dst, src, idx *YMM) {
 	result := &YMM{}
 	for i := 0; i < 8; i++ {
 		j := binary.LittleEndian.Uint32(idx.Bytes()[i*4:]) & 0x07
+		tracer.Lookup("VPERMD", int(j))
 		copy(result.Bytes()[i*4:], src.Bytes()[j*4:])
 	}
 	copy(dst.Bytes(), result.Bytes())
 }
 
 func VPSHUFB(dst, src1, src2 *YMM) {
+	lookup("VPSHUFB", src2.Bytes())
 	tmp := YMM{}
 	tmpBytes := tmp.Bytes()
 	src1Bytes := src1.Bytes()
@@ -599,3 +621,45 @@ func VPUNPCKHQDQ(dst, src1, src2 *YMM) {
 	copy(result.Bytes()[24:], src2.Bytes()[24:32])
 	copy(dst.Bytes(), result.Bytes())
 }
+
+// _mm256_xor_si256
+func VPXOR(dst, src1, src2 *YMM) {
+	for i := 0; i < 32; i++ {
+		dst.bytes[i] = src1.bytes[i] ^ src2.bytes[i]
+	}
+}
+
+// _mm256_or_si256
+func VPOR(dst, src1, src2 *YMM) {
+	for i := 0; i < 32; i++ {
+		dst.bytes[i] = src1.bytes[i] | src2.bytes[i]
+	}
+}
+
+// _mm256_slli_epi32
+// Shift packed 32-bit integers in a left by imm8 while shifting in zeros, and store the results in dst.
+func VPSLLD(dst, src *YMM, imm8 byte) {
+	for i := 0; i < 8; i++ {
+		v := binary.LittleEndian.Uint32(src.bytes[i*4:])
+		if imm8 > 31 {
+			v = 0
+		} else {
+			v <<= imm8
+		}
+		binary.LittleEndian.PutUint32(dst.bytes[i*4:], v)
+	}
+}
+
+// _mm256_srli_epi32
+// Shift packed 32-bit integers in a right by imm8 while shifting in zeros, and store the results in dst.
+func VPSRLD(dst, src *YMM, imm8 byte) {
+	for i := 0; i < 8; i++ {
+		v := binary.LittleEndian.Uint32(src.bytes[i*4:])
+		if imm8 > 31 {
+			v = 0
+		} else {
+			v >>= imm8
+		}
+		binary.LittleEndian.PutUint32(dst.bytes[i*4:], v)
+	}
+}
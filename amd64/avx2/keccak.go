@@ -0,0 +1,234 @@
+package avx2
+
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/keccak"
+)
+
+// 4-way interleaved Keccak-f[1600]: qword k of register i holds lane i of state k, so the
+// permutation runs the scalar data flow on the four states at once. AVX2 has no 64-bit rotation,
+// ρ uses VPSLLQ, VPSRLQ and VPOR, χ uses VPANDN. This is the layout of the SHAKE128/256 x4 code
+// which samples the ML-KEM matrix and the ML-DSA polynomials.
+
+// keccakX4 is four interleaved Keccak states.
+type keccakX4 [25]YMM
+
+// vprolq rotates each qword of src left by n bits, t is a temporary register.
+func vprolq(dst, src, t *YMM, n byte) {
+	VPSLLQ(t, src, n)
+	VPSRLQ(dst, src, 64-n)
+	VPOR(dst, dst, t)
+}
+
+// keccakF1600x4 applies Keccak-f[1600] to the four states.
+func keccakF1600x4(s *keccakX4) {
+	for _, k := range keccak.RC {
+		keccakRoundX4(s, k)
+	}
+}
+
+// keccakRoundX4 applies the round of the round constant k to the four states.
+func keccakRoundX4(s *keccakX4, k uint64) {
+	var (
+		b    [25]YMM
+		c, d [5]YMM
+		t, u YMM
+		rc   YMM
+	)
+	// θ
+	for x := 0; x < 5; x++ {
+		VPXOR(&c[x], &s[x], &s[x+5])
+		VPXOR(&c[x], &c[x], &s[x+10])
+		VPXOR(&c[x], &c[x], &s[x+15])
+		VPXOR(&c[x], &c[x], &s[x+20])
+	}
+	for x := 0; x < 5; x++ {
+		vprolq(&d[x], &c[(x+1)%5], &t, 1)
+		VPXOR(&d[x], &d[x], &c[(x+4)%5])
+	}
+	// ρ and π
+	for i := range s {
+		VPXOR(&t, &s[i], &d[i%5])
+		vprolq(&b[keccak.Pi(i)], &t, &u, byte(keccak.Rotc[i]))
+	}
+	// χ
+	for y := 0; y < 25; y += 5 {
+		for x := 0; x < 5; x++ {
+			VPANDN(&t, &b[y+(x+1)%5], &b[y+(x+2)%5])
+			VPXOR(&s[y+x], &b[y+x], &t)
+		}
+	}
+	// ι
+	SetOneInt64(&rc, int64(k))
+	VPXOR(&s[0], &s[0], &rc)
+}
+
+// xorLanes xors the first n lanes of the four blocks p into the states.
+func (s *keccakX4) xorLanes(p *[4][]byte, n int) {
+	var t YMM
+	var q [4]uint64
+	for i := 0; i < n; i++ {
+		for k := range q {
+			q[k] = binary.LittleEndian.Uint64(p[k][8*i:])
+		}
+		VMOVDQU_Luint64(&t, q[:])
+		VPXOR(&s[i], &s[i], &t)
+	}
+}
+
+// absorb resets the states and absorbs the four messages of the same length with the padding of
+// the domain separation byte ds. The last padded block is not permuted, squeeze permutes it.
+func (s *keccakX4) absorb(rate int, ds byte, in *[4][]byte) {
+	n := len(in[0])
+	for k := range in {
+		if len(in[k]) != n {
+			panic("avx2: keccakX4 messages of different lengths")
+		}
+	}
+	*s = keccakX4{}
+	p := *in
+	for ; n >= rate; n -= rate {
+		s.xorLanes(&p, rate/8)
+		keccakF1600x4(s)
+		for k := range p {
+			p[k] = p[k][rate:]
+		}
+	}
+	last := pad(rate, ds, &p)
+	s.xorLanes(&last, rate/8)
+}
+
+// pad returns the last blocks of the four remainders p of the same length, shorter than rate,
+// padded with the domain separation byte ds.
+func pad(rate int, ds byte, p *[4][]byte) (last [4][]byte) {
+	for k := range last {
+		last[k] = make([]byte, rate)
+		copy(last[k], p[k])
+		last[k][len(p[k])] ^= ds
+		last[k][rate-1] ^= 0x80
+	}
+	return
+}
+
+// store writes the first rate bytes of state k to block[k*rate:].
+func (s *keccakX4) store(rate int, block []byte) {
+	var q [4]uint64
+	for i := 0; i < rate/8; i++ {
+		VMOVEDQU_Suint64(q[:], &s[i])
+		for k := range q {
+			binary.LittleEndian.PutUint64(block[k*rate+8*i:], q[k])
+		}
+	}
+}
+
+// squeeze fills the four outputs of the same length, the states are permuted before each block.
+func (s *keccakX4) squeeze(rate int, out *[4][]byte) {
+	block := make([]byte, 4*rate)
+	for done := 0; done < len(out[0]); done += rate {
+		keccakF1600x4(s)
+		s.store(rate, block)
+		for k := range out {
+			copy(out[k][done:], block[k*rate:(k+1)*rate])
+		}
+	}
+}
+
+// shake128x4 returns n bytes of SHAKE128 of each of the four messages of the same length.
+func shake128x4(in *[4][]byte, n int) (out [4][]byte) {
+	var s keccakX4
+	for k := range out {
+		out[k] = make([]byte, n)
+	}
+	s.absorb(keccak.Rate128, keccak.DomainShake, in)
+	s.squeeze(keccak.Rate128, &out)
+	return
+}
+
+// shake256x4 returns n bytes of SHAKE256 of each of the four messages of the same length.
+func shake256x4(in *[4][]byte, n int) (out [4][]byte) {
+	var s keccakX4
+	for k := range out {
+		out[k] = make([]byte, n)
+	}
+	s.absorb(keccak.Rate256, keccak.DomainShake, in)
+	s.squeeze(keccak.Rate256, &out)
+	return
+}
+
+// 8-way Keccak-f[1600]: two sets of four interleaved states. keccakF1600x8 alternates the rounds
+// of the two sets, their dependency chains are independent so the out-of-order core overlaps them.
+// Lane k of the messages and outputs is state k%4 of set k/4.
+
+// keccakX8 is two sets of four interleaved Keccak states.
+type keccakX8 [2]keccakX4
+
+// keccakF1600x8 applies Keccak-f[1600] to the eight states.
+func keccakF1600x8(s *keccakX8) {
+	for _, k := range keccak.RC {
+		keccakRoundX4(&s[0], k)
+		keccakRoundX4(&s[1], k)
+	}
+}
+
+// absorb resets the states and absorbs the eight messages of the same length, see keccakX4.absorb.
+func (s *keccakX8) absorb(rate int, ds byte, in *[8][]byte) {
+	n := len(in[0])
+	for k := range in {
+		if len(in[k]) != n {
+			panic("avx2: keccakX8 messages of different lengths")
+		}
+	}
+	*s = keccakX8{}
+	p := [2][4][]byte{[4][]byte(in[:4]), [4][]byte(in[4:])}
+	for ; n >= rate; n -= rate {
+		for j := range s {
+			s[j].xorLanes(&p[j], rate/8)
+		}
+		keccakF1600x8(s)
+		for j := range p {
+			for k := range p[j] {
+				p[j][k] = p[j][k][rate:]
+			}
+		}
+	}
+	for j := range s {
+		last := pad(rate, ds, &p[j])
+		s[j].xorLanes(&last, rate/8)
+	}
+}
+
+// squeeze fills the eight outputs of the same length, the states are permuted before each block.
+func (s *keccakX8) squeeze(rate int, out *[8][]byte) {
+	block := make([]byte, 8*rate)
+	for done := 0; done < len(out[0]); done += rate {
+		keccakF1600x8(s)
+		s[0].store(rate, block)
+		s[1].store(rate, block[4*rate:])
+		for k := range out {
+			copy(out[k][done:], block[k*rate:(k+1)*rate])
+		}
+	}
+}
+
+// shake128x8 returns n bytes of SHAKE128 of each of the eight messages of the same length.
+func shake128x8(in *[8][]byte, n int) (out [8][]byte) {
+	var s keccakX8
+	for k := range out {
+		out[k] = make([]byte, n)
+	}
+	s.absorb(keccak.Rate128, keccak.DomainShake, in)
+	s.squeeze(keccak.Rate128, &out)
+	return
+}
+
+// shake256x8 returns n bytes of SHAKE256 of each of the eight messages of the same length.
+func shake256x8(in *[8][]byte, n int) (out [8][]byte) {
+	var s keccakX8
+	for k := range out {
+		out[k] = make([]byte, n)
+	}
+	s.absorb(keccak.Rate256, keccak.DomainShake, in)
+	s.squeeze(keccak.Rate256, &out)
+	return
+}
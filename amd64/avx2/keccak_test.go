@@ -0,0 +1,119 @@
+package avx2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emmansun/simd/alg/keccak"
+)
+
+func TestKeccakF1600x4(t *testing.T) {
+	var s keccakX4
+	var want [4][25]uint64
+	for k := range want {
+		for i := range want[k] {
+			want[k][i] = uint64(k*25+i) * 0x9e3779b97f4a7c15
+		}
+	}
+	for i := range s {
+		VMOVDQU_Luint64(&s[i], []uint64{want[0][i], want[1][i], want[2][i], want[3][i]})
+	}
+	keccakF1600x4(&s)
+	var q [4]uint64
+	for k := range want {
+		keccak.F1600(&want[k])
+		for i := range s {
+			VMOVEDQU_Suint64(q[:], &s[i])
+			if q[k] != want[k][i] {
+				t.Fatalf("keccakF1600x4 state %d lane %d = %016x, want %016x", k, i, q[k], want[k][i])
+			}
+		}
+	}
+}
+
+func TestShakeX4(t *testing.T) {
+	// the ML-KEM matrix sampling input of 34 bytes, a long message and an empty one
+	for _, msgLen := range []int{0, 34, 135, 136, 167, 168, 300} {
+		var in [4][]byte
+		for k := range in {
+			in[k] = make([]byte, msgLen)
+			for i := range in[k] {
+				in[k][i] = byte(k*31 + i)
+			}
+		}
+		for _, n := range []int{32, 168, 504, 600} {
+			out := shake128x4(&in, n)
+			for k := range out {
+				if want := keccak.Shake128(in[k], n); !bytes.Equal(out[k], want) {
+					t.Errorf("shake128x4(%d bytes, %d) lane %d = %x, want %x", msgLen, n, k, out[k], want)
+				}
+			}
+			out = shake256x4(&in, n)
+			for k := range out {
+				if want := keccak.Shake256(in[k], n); !bytes.Equal(out[k], want) {
+					t.Errorf("shake256x4(%d bytes, %d) lane %d = %x, want %x", msgLen, n, k, out[k], want)
+				}
+			}
+		}
+	}
+}
+
+func TestKeccakF1600x8(t *testing.T) {
+	var s keccakX8
+	var want [8][25]uint64
+	for k := range want {
+		for i := range want[k] {
+			want[k][i] = uint64(k*25+i) * 0x9e3779b97f4a7c15
+		}
+	}
+	for j := range s {
+		for i := range s[j] {
+			VMOVDQU_Luint64(&s[j][i], []uint64{want[4*j][i], want[4*j+1][i], want[4*j+2][i], want[4*j+3][i]})
+		}
+	}
+	keccakF1600x8(&s)
+	var q [4]uint64
+	for k := range want {
+		keccak.F1600(&want[k])
+		for i := range s[k/4] {
+			VMOVEDQU_Suint64(q[:], &s[k/4][i])
+			if q[k%4] != want[k][i] {
+				t.Fatalf("keccakF1600x8 state %d lane %d = %016x, want %016x", k, i, q[k%4], want[k][i])
+			}
+		}
+	}
+}
+
+func TestShakeX8(t *testing.T) {
+	for _, msgLen := range []int{0, 34, 136, 168, 300} {
+		var in [8][]byte
+		for k := range in {
+			in[k] = make([]byte, msgLen)
+			for i := range in[k] {
+				in[k][i] = byte(k*31 + i)
+			}
+		}
+		for _, n := range []int{32, 168, 600} {
+			out := shake128x8(&in, n)
+			for k := range out {
+				if want := keccak.Shake128(in[k], n); !bytes.Equal(out[k], want) {
+					t.Errorf("shake128x8(%d bytes, %d) lane %d = %x, want %x", msgLen, n, k, out[k], want)
+				}
+			}
+			out = shake256x8(&in, n)
+			for k := range out {
+				if want := keccak.Shake256(in[k], n); !bytes.Equal(out[k], want) {
+					t.Errorf("shake256x8(%d bytes, %d) lane %d = %x, want %x", msgLen, n, k, out[k], want)
+				}
+			}
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("messages of different lengths do not panic")
+		}
+	}()
+	var in [8][]byte
+	in[7] = make([]byte, 1)
+	shake128x8(&in, 32)
+}
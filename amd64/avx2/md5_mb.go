@@ -0,0 +1,74 @@
+package avx2
+
+import "github.com/emmansun/simd/alg/md5"
+
+// md5block8 compresses the blocks of eight messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func md5block8(state *[8][4]uint32, p *[8][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("avx2: md5block8 messages of different lengths")
+		}
+	}
+	var (
+		s, save [4]YMM
+		x       [16]YMM
+		ones    = &YMM{}
+		F       = &YMM{}
+		T       = &YMM{}
+		X0      = &YMM{}
+		lanes   [8]uint32
+	)
+	SetOneInt32(ones, -1)
+	for i := range s {
+		for k := range lanes {
+			lanes[k] = state[k][i]
+		}
+		VMOVDQU_Luint32(&s[i], lanes[:])
+	}
+
+	for n := 0; n+md5.BlockSize <= len(p[0]); n += md5.BlockSize {
+		save = s
+		loadBlocks8(x[:], p, n, nil)
+		a, b, c, d := &s[0], &s[1], &s[2], &s[3]
+		for i := 0; i < 64; i++ {
+			switch {
+			case i < 16:
+				// d ^ (b & (c ^ d))
+				VPXOR(F, c, d)
+				VPAND(F, F, b)
+				VPXOR(F, F, d)
+			case i < 32:
+				// c ^ (d & (b ^ c))
+				VPXOR(F, b, c)
+				VPAND(F, F, d)
+				VPXOR(F, F, c)
+			case i < 48:
+				VPXOR(F, b, c)
+				VPXOR(F, F, d)
+			default:
+				// c ^ (b | ^d)
+				VPXOR(F, d, ones)
+				VPOR(F, F, b)
+				VPXOR(F, F, c)
+			}
+			SetOneInt32(T, int32(md5.T[i]))
+			VPADDD(X0, a, F)
+			VPADDD(X0, X0, T)
+			VPADDD(X0, X0, &x[md5.X(i)])
+			vprold(a, X0, T, byte(md5.S[i]))
+			VPADDD(a, a, b) // the new b
+			a, b, c, d = d, a, b, c
+		}
+		for i := range s {
+			VPADDD(&s[i], &s[i], &save[i])
+		}
+	}
+
+	for i := range s {
+		VMOVEDQU_Suint32(lanes[:], &s[i])
+		for k, v := range lanes {
+			state[k][i] = v
+		}
+	}
+}
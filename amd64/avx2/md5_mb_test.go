@@ -0,0 +1,19 @@
+package avx2
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/mbtest"
+)
+
+func md5block8Slice(state [][4]uint32, p [][]byte) {
+	md5block8((*[8][4]uint32)(state), (*[8][]byte)(p))
+}
+
+func TestMD5Block8(t *testing.T) {
+	mbtest.TestBlock(t, mbtest.MD5, 8, md5block8Slice)
+}
+
+func BenchmarkMD5Block8(b *testing.B) {
+	mbtest.BenchmarkBlock(b, mbtest.MD5, 8, md5block8Slice)
+}
@@ -0,0 +1,95 @@
+package avx2
+
+import "github.com/emmansun/simd/alg/sha1"
+
+// loadBlocks8 loads the 64 bytes blocks at offset n of the eight messages into w[0..15] vertically,
+// the words are byte swapped with flip if it is not nil.
+func loadBlocks8(w []YMM, p *[8][]byte, n int, flip *YMM) {
+	for half := 0; half < 2; half++ {
+		r := [8]*YMM{}
+		for k := range r {
+			r[k] = &w[8*half+k]
+			VMOVDQU_Luint8(r[k], p[k][n+32*half:n+32*half+32])
+			if flip != nil {
+				VPSHUFB(r[k], r[k], flip)
+			}
+		}
+		transpose8x8(&r)
+	}
+}
+
+// sha1block8 compresses the blocks of eight messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func sha1block8(state *[8][5]uint32, p *[8][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("avx2: sha1block8 messages of different lengths")
+		}
+	}
+	var (
+		s, save   [5]YMM
+		w         [80]YMM // the message schedule on the stack
+		flip_mask = &YMM{}
+		F         = &YMM{}
+		T         = &YMM{}
+		X0        = &YMM{}
+		K         = &YMM{}
+		lanes     [8]uint32
+	)
+	flip := []byte{0x03, 0x02, 0x01, 0x00, 0x07, 0x06, 0x05, 0x04, 0x0b, 0x0a, 0x09, 0x08, 0x0f, 0x0e, 0x0d, 0x0c}
+	VMOVDQU_Luint8(flip_mask, append(flip, flip...))
+	for i := range s {
+		for k := range lanes {
+			lanes[k] = state[k][i]
+		}
+		VMOVDQU_Luint32(&s[i], lanes[:])
+	}
+
+	for n := 0; n+sha1.BlockSize <= len(p[0]); n += sha1.BlockSize {
+		save = s
+		loadBlocks8(w[:], p, n, flip_mask)
+		for j := 16; j < 80; j++ {
+			VPXOR(X0, &w[j-3], &w[j-8])
+			VPXOR(X0, X0, &w[j-14])
+			VPXOR(X0, X0, &w[j-16])
+			vprold(&w[j], X0, T, 1)
+		}
+		a, b, c, d, e := &s[0], &s[1], &s[2], &s[3], &s[4]
+		for i := 0; i < 80; i++ {
+			switch {
+			case i < 20:
+				// d ^ (b & (c ^ d))
+				VPXOR(F, c, d)
+				VPAND(F, F, b)
+				VPXOR(F, F, d)
+			case i < 40, i >= 60:
+				VPXOR(F, b, c)
+				VPXOR(F, F, d)
+			default:
+				// (b & c) | (d & (b | c))
+				VPOR(F, b, c)
+				VPAND(F, F, d)
+				VPAND(T, b, c)
+				VPOR(F, F, T)
+			}
+			SetOneInt32(K, int32(sha1.K[i/20]))
+			VPADDD(e, e, F)
+			VPADDD(e, e, K)
+			VPADDD(e, e, &w[i])
+			vprold(F, a, T, 5)
+			VPADDD(e, e, F) // e = (a <<< 5) + F + e + W + K, the new a
+			vprold(b, b, T, 30)
+			a, b, c, d, e = e, a, b, c, d
+		}
+		for i := range s {
+			VPADDD(&s[i], &s[i], &save[i])
+		}
+	}
+
+	for i := range s {
+		VMOVEDQU_Suint32(lanes[:], &s[i])
+		for k, v := range lanes {
+			state[k][i] = v
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package avx2
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/mbtest"
+)
+
+func sha1block8Slice(state [][5]uint32, p [][]byte) {
+	sha1block8((*[8][5]uint32)(state), (*[8][]byte)(p))
+}
+
+func TestSHA1Block8(t *testing.T) {
+	mbtest.TestBlock(t, mbtest.SHA1, 8, sha1block8Slice)
+}
+
+func BenchmarkSHA1Block8(b *testing.B) {
+	mbtest.BenchmarkBlock(b, mbtest.SHA1, 8, sha1block8Slice)
+}
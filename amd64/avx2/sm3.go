@@ -0,0 +1,166 @@
+package avx2
+
+import (
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/sm3"
+)
+
+// Multi-buffer SM3: lane k of each register holds message k, the eight messages are compressed
+// with the scalar SM3 data flow on vertical registers, so the message expansion and the rounds
+// have no cross lane dependency. The message blocks and the states are transposed on input and output.
+
+// transpose8x8 transposes the 8x8 dwords matrix of the rows r[0..7].
+func transpose8x8(r *[8]*YMM) {
+	var t, tt [8]YMM
+	for i := 0; i < 8; i += 4 {
+		VPUNPCKLDQ(&t[i], r[i], r[i+1])
+		VPUNPCKHDQ(&t[i+1], r[i], r[i+1])
+		VPUNPCKLDQ(&t[i+2], r[i+2], r[i+3])
+		VPUNPCKHDQ(&t[i+3], r[i+2], r[i+3])
+		VPUNPCKLQDQ(&tt[i], &t[i], &t[i+2])
+		VPUNPCKHQDQ(&tt[i+1], &t[i], &t[i+2])
+		VPUNPCKLQDQ(&tt[i+2], &t[i+1], &t[i+3])
+		VPUNPCKHQDQ(&tt[i+3], &t[i+1], &t[i+3])
+	}
+	for i := 0; i < 4; i++ {
+		VPERM2I128(r[i], &tt[i], &tt[i+4], 0x20)
+		VPERM2I128(r[i+4], &tt[i], &tt[i+4], 0x31)
+	}
+}
+
+// vprold rotates each dword of src left by n bits, t is a temporary register.
+func vprold(dst, src, t *YMM, n byte) {
+	VPSLLD(t, src, n)
+	VPSRLD(dst, src, 32-n)
+	VPOR(dst, dst, t)
+}
+
+// p0 computes x ^ (x <<< 9) ^ (x <<< 17).
+func p0(dst, x, t0, t1 *YMM) {
+	vprold(t0, x, t1, 9)
+	VPXOR(t0, t0, x)
+	vprold(dst, x, t1, 17)
+	VPXOR(dst, dst, t0)
+}
+
+// p1 computes x ^ (x <<< 15) ^ (x <<< 23).
+func p1(dst, x, t0, t1 *YMM) {
+	vprold(t0, x, t1, 15)
+	VPXOR(t0, t0, x)
+	vprold(dst, x, t1, 23)
+	VPXOR(dst, dst, t0)
+}
+
+// SM3MultiBlock8 is sm3block8 as a sm3.MultiBlockFunc, it panics unless len(state) and len(p) are 8.
+func SM3MultiBlock8(state [][8]uint32, p [][]byte) {
+	if len(state) != 8 || len(p) != 8 {
+		panic("avx2: SM3MultiBlock8 needs 8 lanes")
+	}
+	sm3block8((*[8][8]uint32)(state), (*[8][]byte)(p))
+}
+
+// sm3block8 compresses the blocks of eight messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func sm3block8(state *[8][8]uint32, p *[8][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("avx2: sm3block8 messages of different lengths")
+		}
+	}
+	var (
+		s, save   [8]YMM
+		w         [68]YMM // the message schedule on the stack
+		flip_mask = &YMM{}
+		T         = &YMM{}
+		SS1       = &YMM{}
+		SS2       = &YMM{}
+		TT1       = &YMM{}
+		TT2       = &YMM{}
+		X0        = &YMM{}
+		X1        = &YMM{}
+		X2        = &YMM{}
+	)
+	flip := []byte{0x03, 0x02, 0x01, 0x00, 0x07, 0x06, 0x05, 0x04, 0x0b, 0x0a, 0x09, 0x08, 0x0f, 0x0e, 0x0d, 0x0c}
+	VMOVDQU_Luint8(flip_mask, append(flip, flip...))
+
+	rows := [8]*YMM{&s[0], &s[1], &s[2], &s[3], &s[4], &s[5], &s[6], &s[7]}
+	for k := range rows {
+		VMOVDQU_Luint32(rows[k], state[k][:])
+	}
+	transpose8x8(&rows) // s[i] = word i of the eight states
+
+	for n := 0; n+sm3.BlockSize <= len(p[0]); n += sm3.BlockSize {
+		save = s
+		for half := 0; half < 2; half++ {
+			wr := [8]*YMM{}
+			for k := range wr {
+				wr[k] = &w[8*half+k]
+				VMOVDQU_Luint8(wr[k], p[k][n+32*half:])
+				VPSHUFB(wr[k], wr[k], flip_mask)
+			}
+			transpose8x8(&wr) // w[j] = word j of the eight blocks
+		}
+		for j := 16; j < 68; j++ {
+			VPXOR(X0, &w[j-16], &w[j-9])
+			vprold(X1, &w[j-3], X2, 15)
+			VPXOR(X0, X0, X1)
+			p1(X0, X0, X1, X2)
+			vprold(X1, &w[j-13], X2, 7)
+			VPXOR(X0, X0, X1)
+			VPXOR(&w[j], X0, &w[j-6])
+		}
+		for i := 0; i < 64; i++ {
+			a, b, c, d, e, f, g, h := &s[0], &s[1], &s[2], &s[3], &s[4], &s[5], &s[6], &s[7]
+			t := uint32(sm3.CONST0)
+			if i >= 16 {
+				t = sm3.CONST1
+			}
+			SetOneInt32(T, int32(bits.RotateLeft32(t, i)))
+			vprold(SS2, a, X0, 12) // SS2 = a <<< 12
+			VPADDD(SS1, SS2, e)
+			VPADDD(SS1, SS1, T)
+			vprold(SS1, SS1, X0, 7) // SS1 = ((a <<< 12) + e + T) <<< 7
+			VPXOR(SS2, SS2, SS1)    // SS2 = SS1 ^ (a <<< 12)
+			if i < 16 {
+				VPXOR(TT1, a, b)
+				VPXOR(TT1, TT1, c)
+				VPXOR(TT2, e, f)
+				VPXOR(TT2, TT2, g)
+			} else {
+				// FF = (a & b) | (c & (a | b)), GG = g ^ (e & (f ^ g))
+				VPAND(X0, a, b)
+				VPOR(X1, a, b)
+				VPAND(X1, X1, c)
+				VPOR(TT1, X0, X1)
+				VPXOR(X0, f, g)
+				VPAND(X0, X0, e)
+				VPXOR(TT2, X0, g)
+			}
+			VPADDD(TT1, TT1, d)
+			VPADDD(TT1, TT1, SS2)
+			VPXOR(X0, &w[i], &w[i+4])
+			VPADDD(TT1, TT1, X0) // TT1 = FF + d + SS2 + W'
+			VPADDD(TT2, TT2, h)
+			VPADDD(TT2, TT2, SS1)
+			VPADDD(TT2, TT2, &w[i]) // TT2 = GG + h + SS1 + W
+			// d = c, c = b <<< 9, b = a, a = TT1, h = g, g = f <<< 19, f = e, e = P0(TT2)
+			*d = *c
+			vprold(c, b, X0, 9)
+			*b = *a
+			*a = *TT1
+			*h = *g
+			vprold(g, f, X0, 19)
+			*f = *e
+			p0(e, TT2, X0, X1)
+		}
+		for i := range s {
+			VPXOR(&s[i], &s[i], &save[i])
+		}
+	}
+
+	transpose8x8(&rows)
+	for k := range rows {
+		VMOVEDQU_Suint32(state[k][:], rows[k])
+	}
+}
@@ -0,0 +1,135 @@
+package avx2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm3"
+	"github.com/emmansun/simd/amd64/avx"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
+)
+
+func TestSM3Block8(t *testing.T) {
+	var state, want [8][8]uint32
+	var p [8][]byte
+	for k := range p {
+		// different states and messages per lane
+		p[k] = make([]byte, 3*sm3.BlockSize)
+		for i := range p[k] {
+			p[k][i] = byte(i*13 + k*71 + 1)
+		}
+		state[k] = sm3.IV
+		state[k][k] ^= uint32(k) << 8
+		want[k] = state[k]
+		sm3.Block(&want[k], p[k])
+	}
+	simd := state
+	for k := range simd {
+		avx.SM3BlockAVX(&simd[k], p[k])
+	}
+	sm3block8(&state, &p)
+	for k := range state {
+		if state[k] != want[k] {
+			t.Errorf("lane %d: got %08x; want %08x", k, state[k], want[k])
+		}
+		if state[k] != simd[k] {
+			t.Errorf("lane %d: got %08x; SM3BlockAVX %08x", k, state[k], simd[k])
+		}
+	}
+}
+
+func TestSM3Block8Lengths(t *testing.T) {
+	var state [8][8]uint32
+	var p [8][]byte
+	for k := range p {
+		p[k] = make([]byte, sm3.BlockSize)
+	}
+	p[5] = p[5][:0]
+	defer func() {
+		if recover() == nil {
+			t.Errorf("messages of different lengths do not panic")
+		}
+	}()
+	sm3block8(&state, &p)
+}
+
+func TestSM3Block8Vectors(t *testing.T) {
+	// lane k hashes the padded "abc" of GB/T 32905
+	block := make([]byte, sm3.BlockSize)
+	copy(block, "abc\x80")
+	block[63] = 0x18
+	var state [8][8]uint32
+	var p [8][]byte
+	for k := range p {
+		state[k] = sm3.IV
+		p[k] = block
+	}
+	sm3block8(&state, &p)
+	want := [8]uint32{0x66c7f0f4, 0x62eeedd9, 0xd1f2d46b, 0xdc10e4e2, 0x4167c487, 0x5cf2f7a2, 0x297da02b, 0x8f4ba8e0}
+	for k := range state {
+		if state[k] != want {
+			t.Errorf("lane %d: got %08x; want %08x", k, state[k], want)
+		}
+	}
+}
+
+func TestTranspose8x8(t *testing.T) {
+	var m [8]YMM
+	r := [8]*YMM{}
+	for i := range r {
+		r[i] = &m[i]
+		VMOVDQU_Luint32(r[i], []uint32{uint32(i*8 + 0), uint32(i*8 + 1), uint32(i*8 + 2), uint32(i*8 + 3), uint32(i*8 + 4), uint32(i*8 + 5), uint32(i*8 + 6), uint32(i*8 + 7)})
+	}
+	transpose8x8(&r)
+	for i := range r {
+		got := make([]uint32, 8)
+		VMOVEDQU_Suint32(got, r[i])
+		for j, v := range got {
+			if v != uint32(j*8+i) {
+				t.Fatalf("row %d = %v", i, got)
+			}
+		}
+	}
+}
+
+func TestKDFMultiBuffer8(t *testing.T) {
+	z := make([]byte, 150)
+	for i := range z {
+		z[i] = byte(i*7 + 3)
+	}
+	for _, zl := range []int{0, 20, 55, 64, 100, 150} {
+		want := sm3.KDF(nil, z[:zl], 300)
+		if got := sm3.KDFMultiBuffer(SM3MultiBlock8, 8, z[:zl], 300); !bytes.Equal(got, want) {
+			t.Errorf("z length %d: got %x; want %x", zl, got, want)
+		}
+	}
+}
+
+func TestLookupTracer(t *testing.T) {
+	sm3Block8 := func(tr *ctcheck.Tracer, secret []byte) {
+		var state [8][8]uint32
+		var p [8][]byte
+		for k := range p {
+			state[k] = sm3.IV
+			p[k] = bytes.Repeat(secret, 4)
+		}
+		SetTracer(tr)
+		defer SetTracer(nil)
+		sm3block8(&state, &p)
+	}
+	run := func(f func(x *YMM)) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			x := &YMM{}
+			VMOVDQU_Luint8(x, bytes.Repeat(secret, 2))
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(x)
+		}
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "sm3block8", Run: sm3Block8},
+		{Name: "nibble lookup", Site: "VPSHUFB", Run: run(func(x *YMM) { VPSHUFB(&YMM{}, &YMM{}, x) })},
+		{Name: "dword permute", Site: "VPERMD", Run: run(func(x *YMM) { VPERMD(&YMM{}, &YMM{}, x) })},
+	})
+}
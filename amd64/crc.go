@@ -0,0 +1,104 @@
+// The CRC kernel folds 4 or 8 blocks in parallel, combines the accumulators into one block, folds
+// it over the CRC width and reduces it with Barrett, see alg/crc for the constants.
+
+package amd64
+
+import (
+	"github.com/emmansun/simd/alg/crc"
+	"github.com/emmansun/simd/amd64/sse"
+)
+
+type clmulAMD64CRC struct {
+	c     *crc.FoldConstants
+	lanes int
+}
+
+// NewClmulAMD64CRC returns the folding kernel of the reflected CRC of width 32 or 64 with the
+// reflected polynomial poly, e.g. crc.IEEE, crc.Castagnoli, crc.ISO or crc.ECMA, which folds
+// lanes (4 or 8) blocks per iteration.
+func NewClmulAMD64CRC(width int, poly uint64, lanes int) *clmulAMD64CRC {
+	if lanes != 4 && lanes != 8 {
+		panic("amd64: unsupported CRC fold lanes")
+	}
+	return &clmulAMD64CRC{c: crc.NewFoldConstants(width, poly), lanes: lanes}
+}
+
+// Update returns the CRC of p updated from v, as hash/crc32.Update and hash/crc64.Update.
+func (k *clmulAMD64CRC) Update(v uint64, p []byte) uint64 {
+	c := k.c
+	if len(p) < 16*k.lanes {
+		return crc.Update(c.Width, c.Poly, v, p)
+	}
+	mask := ^uint64(0) >> (64 - c.Width)
+	var (
+		X     = make([]sse.XMM, k.lanes)
+		B     = sse.XMM{}
+		T     = sse.XMM{}
+		Q     = sse.XMM{}
+		KN    sse.XMM
+		K1    = sse.Set64(c.Fold1[1], c.Fold1[0])
+		KW    = sse.Set64(c.FoldWidth[1], c.FoldWidth[0])
+		MU    = sse.Set64(0, c.Mu)
+		POLYX = sse.Set64(0, c.PolyX)
+		INIT  = sse.Set64(0, ^v&mask)
+	)
+	if k.lanes == 4 {
+		KN = sse.Set64(c.Fold4[1], c.Fold4[0])
+	} else {
+		KN = sse.Set64(c.Fold8[1], c.Fold8[0])
+	}
+
+	for i := range X {
+		sse.SetBytes(&X[i], p[16*i:])
+	}
+	sse.PXOR(&X[0], &INIT)
+	p = p[16*k.lanes:]
+
+	for len(p) >= 16*k.lanes {
+		for i := range X {
+			sse.SetBytes(&B, p[16*i:])
+			foldBlock(&X[i], &B, &KN, &T)
+		}
+		p = p[16*k.lanes:]
+	}
+
+	// combine the accumulators
+	for i := 1; i < k.lanes; i++ {
+		foldBlock(&X[0], &X[i], &K1, &T)
+	}
+	for len(p) >= 16 {
+		sse.SetBytes(&B, p)
+		foldBlock(&X[0], &B, &K1, &T)
+		p = p[16:]
+	}
+
+	// fold over the width, X = R128(Ah·x^w + Al)
+	sse.MOVOU(&T, &X[0])
+	sse.PCLMULQDQ(&X[0], &KW, 0x00)
+	sse.PCLMULQDQ(&T, &KW, 0x11)
+	sse.PXOR(&X[0], &T)
+
+	// Barrett reduction, q = Ah·Mu / x^63
+	sse.MOVOU(&Q, &X[0])
+	if c.Width == 32 {
+		sse.PSRLDQ(&Q, 4)
+	}
+	sse.PCLMULQDQ(&Q, &MU, 0x00)
+	sse.MOVOU(&T, &Q)
+	sse.PCLMULQDQ(&T, &POLYX, 0x00)
+	sse.PXOR(&X[0], &T)
+	sse.PSLLDQ(&Q, 8)
+	sse.PXOR(&X[0], &Q)
+
+	v = X[0].Uint64s()[1]>>(64-c.Width) ^ mask
+	return crc.Update(c.Width, c.Poly, v, p)
+}
+
+// foldBlock folds X over the distance of K and adds B, T is clobbered.
+func foldBlock(X, B, K, T *sse.XMM) {
+	sse.MOVOU(T, X)
+	sse.PCLMULQDQ(X, K, 0x00)
+	sse.PCLMULQDQ(T, K, 0x11)
+	sse.PXOR(X, T)
+	sse.PXOR(X, B)
+}
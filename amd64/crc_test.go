@@ -0,0 +1,19 @@
+package amd64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/crctest"
+)
+
+func newClmulCRC(width int, poly uint64, lanes int) crctest.Kernel {
+	return NewClmulAMD64CRC(width, poly, lanes)
+}
+
+func TestClmulCRC(t *testing.T) {
+	crctest.TestKernel(t, newClmulCRC)
+}
+
+func BenchmarkClmulCRC32(b *testing.B) {
+	crctest.BenchmarkCRC32(b, newClmulCRC)
+}
@@ -0,0 +1,92 @@
+package amd64
+
+import "github.com/emmansun/simd/amd64/sse"
+
+// GF128MulXLE multiplies B by x in the little endian convention (IEEE 1619 XTS).
+// T is a temporary register.
+func GF128MulXLE(B, T *sse.XMM) {
+	POLY := sse.SetEpi32(0x87, 0, 1, 0)
+	sse.PSHUFD(T, B, 0x13) // T = [B.d3, B.d0, B.d1, B.d0]
+	sse.PSRAD(T, 31)       // broadcast the carry bits of B.lo and B.hi
+	sse.PAND(T, &POLY)     // T.lo = 0x87 if B.hi carries, T.hi = 1 if B.lo carries
+	sse.PSLLQ(B, 1)
+	sse.PXOR(B, T)
+}
+
+// GF128MulXBE multiplies B by x in the big endian convention (CMAC, OCB).
+// T is a temporary register.
+func GF128MulXBE(B, T *sse.XMM) {
+	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
+	sse.PSHUFB(B, &BSWAP)
+	GF128MulXLE(B, T)
+	sse.PSHUFB(B, &BSWAP)
+}
+
+// GF128MulXGB multiplies B by x in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value with the reduction 0xe1 << 120.
+// T is a temporary register.
+func GF128MulXGB(B, T *sse.XMM) {
+	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
+	POLY := sse.Set64(0xe100000000000000, 0x8000000000000000)
+	sse.PSHUFB(B, &BSWAP)
+	sse.MOVOU(T, B)
+	sse.PSLLQ(T, 63)       // the least significant bits of B.lo and B.hi
+	sse.PSHUFD(T, T, 0x5f) // T = [T.d3, T.d3, T.d1, T.d1]
+	sse.PSRAD(T, 31)       // broadcast them
+	sse.PAND(T, &POLY)     // T.lo = 1 << 63 if B.hi carries, T.hi = 0xe1 << 56 if B.lo carries
+	sse.PSRLQ(B, 1)
+	sse.PXOR(B, T)
+	sse.PSHUFB(B, &BSWAP)
+}
+
+// checkMulXPow panics unless 1 <= k <= 8, the k bits shifted out of the block fit in a byte.
+func checkMulXPow(k int) {
+	if k < 1 || k > 8 {
+		panic("amd64: GF128MulXPow k out of range")
+	}
+}
+
+// GF128MulXPowLE multiplies B by x^k, 1 <= k <= 8, in the little endian convention (IEEE 1619 XTS).
+// T0, T1 are temporary registers.
+func GF128MulXPowLE(B, T0, T1 *sse.XMM, k int) {
+	checkMulXPow(k)
+	POLY := sse.Set64(0, 0x87)
+	sse.MOVOU(T0, B)
+	sse.PSRLQ(T0, uint(64-k)) // T0.lo = the carry of B.lo, T0.hi = the bits shifted out
+	sse.MOVOU(T1, T0)
+	sse.PCLMULQDQ(T1, &POLY, 0x01) // T1 = T0.hi·0x87
+	sse.PSLLDQ(T0, 8)
+	sse.PSLLQ(B, uint(k))
+	sse.PXOR(B, T0)
+	sse.PXOR(B, T1)
+}
+
+// GF128MulXPowBE multiplies B by x^k, 1 <= k <= 8, in the big endian convention (CMAC, OCB).
+// T0, T1 are temporary registers.
+func GF128MulXPowBE(B, T0, T1 *sse.XMM, k int) {
+	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
+	sse.PSHUFB(B, &BSWAP)
+	GF128MulXPowLE(B, T0, T1, k)
+	sse.PSHUFB(B, &BSWAP)
+}
+
+// GF128MulXPowGB multiplies B by x^k, 1 <= k <= 8, in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value by k bits, the bits shifted out are reduced with 0xe1 << (121 - k).
+// T0, T1 are temporary registers.
+func GF128MulXPowGB(B, T0, T1 *sse.XMM, k int) {
+	checkMulXPow(k)
+	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
+	POLY := sse.Set64(0, 0xe1<<(57-k))
+	sse.PSHUFB(B, &BSWAP)
+	sse.MOVOU(T0, B)
+	sse.PSLLQ(T0, uint(64-k)) // T0.lo = the bits shifted out, T0.hi = the carry of B.hi, at the top
+	sse.MOVOU(T1, T0)
+	sse.PSRLDQ(T1, 8)
+	sse.PSRLQ(B, uint(k))
+	sse.PXOR(B, T1)
+	sse.PSRLQ(T0, uint(64-k))
+	sse.PCLMULQDQ(T0, &POLY, 0x00) // T0.lo = the bits shifted out·0xe1 << (57 - k)
+	sse.PSLLDQ(T0, 8)
+	sse.PXOR(B, T0)
+	sse.PSHUFB(B, &BSWAP)
+}
@@ -1,15 +1,48 @@
-// The theoretical proof behind it is still not very clear.
+// The kernel computes r(X)·(r(H)·z)·z^-128 mod the bit reflected polynomial, see internal/gf2 for
+// the derivation and ghash_proof_test.go which proves the reduction for all inputs.
+
 package amd64
 
 import "github.com/emmansun/simd/amd64/sse"
 
 type clmulAMD64Ghash struct {
-	bytesProductTable [256]byte
+	aggregation       int
+	bytesProductTable []byte // H^aggregation, ..., H^1 for Karatsuba, 2 * 16 bytes each
+	polyval           bool
+}
+
+// defaultAggregation is the number of blocks processed by a reduction.
+const defaultAggregation = 8
+
+// checkAggregation panics if n is not a supported aggregation depth.
+func checkAggregation(n int) {
+	switch n {
+	case 1, 2, 4, 8, 16:
+	default:
+		panic("amd64: unsupported GHASH aggregation depth")
+	}
 }
 
 func NewClmulAMD64Ghash(h []byte) *clmulAMD64Ghash {
-	g := &clmulAMD64Ghash{}
-	// H * 2
+	return newClmulAMD64Ghash(h, defaultAggregation, false)
+}
+
+// NewClmulAMD64GhashWithAggregation returns the kernel which aggregates n (1, 2, 4, 8 or 16) blocks
+// per reduction, the precomputed table holds n powers of H.
+func NewClmulAMD64GhashWithAggregation(h []byte, n int) *clmulAMD64Ghash {
+	checkAggregation(n)
+	return newClmulAMD64Ghash(h, n, false)
+}
+
+// NewClmulAMD64Polyval returns the POLYVAL (RFC 8452) variant of the kernel.
+// The kernel computes in the byte swapped GHASH representation which is POLYVAL already,
+// so POLYVAL just skips the byte swaps and the key doubling.
+func NewClmulAMD64Polyval(h []byte) *clmulAMD64Ghash {
+	return newClmulAMD64Ghash(h, defaultAggregation, true)
+}
+
+func newClmulAMD64Ghash(h []byte, n int, polyval bool) *clmulAMD64Ghash {
+	g := &clmulAMD64Ghash{aggregation: n, bytesProductTable: make([]byte, n*2*16), polyval: polyval}
 	var (
 		B0 = sse.XMM{}
 		B1 = sse.XMM{}
@@ -23,26 +56,29 @@ func NewClmulAMD64Ghash(h []byte) *clmulAMD64Ghash {
 	POLY := sse.Set64(0xc200000000000000, 0x0000000000000001)
 	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
 	sse.SetBytes(&B0, h)
-	sse.PSHUFB(&B0, &BSWAP)
-	sse.PSHUFD(&T0, &B0, 0xff)
-	sse.MOVOU(&T1, &B0)
-	sse.PSRAW(&T0, 31)
-	sse.PAND(&T0, &POLY)
-	sse.PSRLW(&T1, 31)
-	sse.PSLLDQ(&T1, 4)
-	sse.PSLLW(&B0, 1)
-	sse.PXOR(&B0, &T0)
-	sse.PXOR(&B0, &T1)
+	if !polyval {
+		// H * 2
+		sse.PSHUFB(&B0, &BSWAP)
+		sse.PSHUFD(&T0, &B0, 0xff)
+		sse.MOVOU(&T1, &B0)
+		sse.PSRAD(&T0, 31)
+		sse.PAND(&T0, &POLY)
+		sse.PSRLD(&T1, 31)
+		sse.PSLLDQ(&T1, 4)
+		sse.PSLLD(&B0, 1)
+		sse.PXOR(&B0, &T0)
+		sse.PXOR(&B0, &T1)
+	}
 
 	// Karatsuba pre-computations
-	copy(g.bytesProductTable[14*16:], B0.Bytes())
+	copy(g.bytesProductTable[(n-1)*2*16:], B0.Bytes())
 	sse.PSHUFD(&B1, &B0, 78)
 	sse.PXOR(&B1, &B0)
-	copy(g.bytesProductTable[15*16:], B1.Bytes())
+	copy(g.bytesProductTable[((n-1)*2+1)*16:], B1.Bytes())
 	sse.MOVOU(&B2, &B0)
 	sse.MOVOU(&B3, &B1)
 
-	for i := 6; i >= 0; i-- {
+	for i := n - 2; i >= 0; i-- {
 		// Karatsuba multiplication
 		sse.MOVOU(&T0, &B2)
 		sse.MOVOU(&T1, &B2)
@@ -71,71 +107,49 @@ func (g *clmulAMD64Ghash) Hash(T *[16]byte, data []byte) {
 		T1   = sse.XMM{}
 		T2   = sse.XMM{}
 		X0   = sse.XMM{}
-		X1   = sse.XMM{}
-		X2   = sse.XMM{}
-		X3   = sse.XMM{}
-		X4   = sse.XMM{}
-		X5   = sse.XMM{}
-		X6   = sse.XMM{}
-		X7   = sse.XMM{}
+		X    = make([]sse.XMM, g.aggregation)
 	)
 	POLY := sse.Set64(0xc200000000000000, 0x0000000000000001)
 	BSWAP := sse.Set64(0x0001020304050607, 0x08090a0b0c0d0e0f)
 	sse.PXOR(&ACC0, &ACC0)
 
-	// handle 8 blocks at a time
-	for len(data) >= 128 {
-		// load 8 blocks
-		sse.SetBytes(&X0, data)
-		sse.SetBytes(&X1, data[16:])
-		sse.SetBytes(&X2, data[32:])
-		sse.SetBytes(&X3, data[48:])
-		sse.SetBytes(&X4, data[64:])
-		sse.SetBytes(&X5, data[80:])
-		sse.SetBytes(&X6, data[96:])
-		sse.SetBytes(&X7, data[112:])
-		sse.PSHUFB(&X0, &BSWAP)
-		sse.PSHUFB(&X1, &BSWAP)
-		sse.PSHUFB(&X2, &BSWAP)
-		sse.PSHUFB(&X3, &BSWAP)
-		sse.PSHUFB(&X4, &BSWAP)
-		sse.PSHUFB(&X5, &BSWAP)
-		sse.PSHUFB(&X6, &BSWAP)
-		sse.PSHUFB(&X7, &BSWAP)
+	// handle aggregation blocks at a time
+	for len(data) >= 16*g.aggregation {
+		// load blocks
+		for i := range X {
+			sse.SetBytes(&X[i], data[16*i:])
+			g.byteSwap(&X[i], &BSWAP)
+		}
 		// add previous result
-		sse.PXOR(&X0, &ACC0)
+		sse.PXOR(&X[0], &ACC0)
 
 		sse.SetBytes(&ACC0, g.bytesProductTable[16*0:])
 		sse.SetBytes(&ACCM, g.bytesProductTable[16*1:])
 		sse.MOVOU(&ACC1, &ACC0)
 
 		// Karatsuba multiplication
-		sse.PSHUFD(&T1, &X0, 78)
-		sse.PXOR(&T1, &X0)
-		sse.PCLMULQDQ(&ACC0, &X0, 0x00)
-		sse.PCLMULQDQ(&ACC1, &X0, 0x11)
+		sse.PSHUFD(&T1, &X[0], 78)
+		sse.PXOR(&T1, &X[0])
+		sse.PCLMULQDQ(&ACC0, &X[0], 0x00)
+		sse.PCLMULQDQ(&ACC1, &X[0], 0x11)
 		sse.PCLMULQDQ(&ACCM, &T1, 0x00)
 
-		g.mulRoundAAD(&X1, &T1, &T2, &ACC0, &ACC1, &ACCM, 1)
-		g.mulRoundAAD(&X2, &T1, &T2, &ACC0, &ACC1, &ACCM, 2)
-		g.mulRoundAAD(&X3, &T1, &T2, &ACC0, &ACC1, &ACCM, 3)
-		g.mulRoundAAD(&X4, &T1, &T2, &ACC0, &ACC1, &ACCM, 4)
-		g.mulRoundAAD(&X5, &T1, &T2, &ACC0, &ACC1, &ACCM, 5)
-		g.mulRoundAAD(&X6, &T1, &T2, &ACC0, &ACC1, &ACCM, 6)
-		g.mulRoundAAD(&X7, &T1, &T2, &ACC0, &ACC1, &ACCM, 7)
+		for i := 1; i < g.aggregation; i++ {
+			g.mulRoundAAD(&X[i], &T1, &T2, &ACC0, &ACC1, &ACCM, i)
+		}
 
 		g.processClmulResult(&ACC0, &ACCM, &ACC1, &T0)
 		// postponed reduction
 		g.fastReduction(&ACC1, &ACC0, &T0, &POLY)
-		data = data[128:]
+		data = data[16*g.aggregation:]
 	}
-	sse.SetBytes(&T1, g.bytesProductTable[16*14:])
-	sse.SetBytes(&T2, g.bytesProductTable[16*15:])
+	sse.SetBytes(&T1, g.bytesProductTable[16*(g.aggregation-1)*2:])
+	sse.SetBytes(&T2, g.bytesProductTable[16*((g.aggregation-1)*2+1):])
 	// handle one block at a time
 	for len(data) >= 16 {
 		// load 1 block
 		sse.SetBytes(&X0, data)
-		sse.PSHUFB(&X0, &BSWAP)
+		g.byteSwap(&X0, &BSWAP)
 		g.mulOneBlock(&X0, &ACC0, &ACCM, &ACC1, &T0, &T1, &T2, &POLY)
 		data = data[16:]
 	}
@@ -143,13 +157,21 @@ func (g *clmulAMD64Ghash) Hash(T *[16]byte, data []byte) {
 		var partialBlock [16]byte
 		copy(partialBlock[:], data)
 		sse.SetBytes(&X0, partialBlock[:])
-		sse.PSHUFB(&X0, &BSWAP)
+		g.byteSwap(&X0, &BSWAP)
 		g.mulOneBlock(&X0, &ACC0, &ACCM, &ACC1, &T0, &T1, &T2, &POLY)
 	}
-	sse.PSHUFB(&ACC0, &BSWAP)
+	g.byteSwap(&ACC0, &BSWAP)
 	copy(T[:], ACC0.Bytes())
 }
 
+// byteSwap converts a GHASH block to the kernel representation and back,
+// POLYVAL blocks are in the kernel representation already.
+func (g *clmulAMD64Ghash) byteSwap(X, BSWAP *sse.XMM) {
+	if !g.polyval {
+		sse.PSHUFB(X, BSWAP)
+	}
+}
+
 // Multiply X by 2H and accumulate the result in ACC0
 // ACC0 = X * 2H + ACC0
 // X0 is the input block
@@ -185,9 +207,9 @@ func (g *clmulAMD64Ghash) processClmulResult(ACC0, ACCM, ACC1, T *sse.XMM) {
 	sse.PXOR(ACC0, T)
 }
 
-// Multiply X by 2H^(8-i) and accumulate the result in ACC0, ACC1, ACCM
+// Multiply X by 2H^(n-i) and accumulate the result in ACC0, ACC1, ACCM, n is the aggregation depth
 // Y = [ACC0, ACCM, ACC1]
-// Y = X * (2H)^(8-i) + Y
+// Y = X * (2H)^(n-i) + Y
 // T1, T2 are temporary registers
 func (g *clmulAMD64Ghash) mulRoundAAD(X, T1, T2, ACC0, ACC1, ACCM *sse.XMM, i int) {
 	sse.SetBytes(T1, g.bytesProductTable[16*(i*2):])
@@ -0,0 +1,68 @@
+package amd64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/amd64/sse"
+	"github.com/emmansun/simd/internal/gf2"
+)
+
+// The registers are little-endian, the bit vector of a register is its bytes.
+
+// TestGHashProcessClmulResultProof proves processClmulResult combines the Karatsuba terms for all inputs.
+func TestGHashProcessClmulResultProof(t *testing.T) {
+	g := NewClmulAMD64Ghash(make([]byte, 16))
+	f := func(x []byte) ([]byte, gf2.Vec) {
+		var ACC0, ACCM, ACC1, T sse.XMM
+		sse.SetSymbolic(&ACC0, gf2.Var(0, 128), x[:16])
+		sse.SetSymbolic(&ACCM, gf2.Var(128, 128), x[16:32])
+		sse.SetSymbolic(&ACC1, gf2.Var(256, 128), x[32:])
+		g.processClmulResult(&ACC0, &ACCM, &ACC1, &T)
+		return append(append([]byte{}, ACC0.Bytes()...), ACC1.Bytes()...), gf2.Concat(ACC0.Symbolic(), ACC1.Symbolic())
+	}
+	if err := gf2.CompareSymbolic(384, f, gf2.Karatsuba); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashFastReductionProof proves fastReduction is the Montgomery reduction for all inputs.
+func TestGHashFastReductionProof(t *testing.T) {
+	g := NewClmulAMD64Ghash(make([]byte, 16))
+	f := func(x []byte) ([]byte, gf2.Vec) {
+		var X0, X1, T sse.XMM
+		POLY := sse.Set64(0xc200000000000000, 0x0000000000000001)
+		sse.SetSymbolic(&X0, gf2.Var(0, 128), x[:16])
+		sse.SetSymbolic(&X1, gf2.Var(128, 128), x[16:])
+		g.fastReduction(&X1, &X0, &T, &POLY)
+		return X0.Bytes(), X0.Symbolic()
+	}
+	if err := gf2.CompareSymbolic(256, f, gf2.MontgomeryReduce); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashTwistProof checks the precomputed key is the twisted key 2H on the basis vectors and random inputs.
+func TestGHashTwistProof(t *testing.T) {
+	f := func(h []byte) []byte {
+		g := NewClmulAMD64GhashWithAggregation(h, 1)
+		return g.bytesProductTable[:16]
+	}
+	if err := gf2.CompareLinear(128, 128, f, gf2.Twist); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashMulProof checks the single block multiplication is the GHASH multiplication on the basis vectors and random inputs.
+func TestGHashMulProof(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	f := func(x, h []byte) []byte {
+		var T [16]byte
+		NewClmulAMD64GhashWithAggregation(h, 1).Hash(&T, x)
+		return T[:]
+	}
+	if err := gf2.CompareBilinear(128, 128, 128, f, gf2.GHASHMul); err != nil {
+		t.Fatal(err)
+	}
+}
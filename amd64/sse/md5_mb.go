@@ -0,0 +1,71 @@
+package sse
+
+import "github.com/emmansun/simd/alg/md5"
+
+// md5block4 compresses the blocks of four messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func md5block4(state *[4][4]uint32, p *[4][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("sse: md5block4 messages of different lengths")
+		}
+	}
+	var (
+		s, save [4]XMM
+		x       [16]XMM
+		ones    = Set64(0xffffffffffffffff, 0xffffffffffffffff)
+		F       = &XMM{}
+		T       = &XMM{}
+	)
+	for i := range s {
+		s[i] = SetEpi32(state[0][i], state[1][i], state[2][i], state[3][i])
+	}
+
+	for n := 0; n+md5.BlockSize <= len(p[0]); n += md5.BlockSize {
+		save = s
+		loadBlocks4(x[:], p, n, nil)
+		a, b, c, d := &s[0], &s[1], &s[2], &s[3]
+		for i := 0; i < 64; i++ {
+			switch {
+			case i < 16:
+				// d ^ (b & (c ^ d))
+				MOVOU(F, c)
+				PXOR(F, d)
+				PAND(F, b)
+				PXOR(F, d)
+			case i < 32:
+				// c ^ (d & (b ^ c))
+				MOVOU(F, b)
+				PXOR(F, c)
+				PAND(F, d)
+				PXOR(F, c)
+			case i < 48:
+				MOVOU(F, b)
+				PXOR(F, c)
+				PXOR(F, d)
+			default:
+				// c ^ (b | ^d)
+				MOVOU(F, d)
+				PXOR(F, &ones)
+				POR(F, b)
+				PXOR(F, c)
+			}
+			*T = SetEpi32(md5.T[i], md5.T[i], md5.T[i], md5.T[i])
+			PADDD(a, F)
+			PADDD(a, T)
+			PADDD(a, &x[md5.X(i)])
+			prold(a, T, uint(md5.S[i]))
+			PADDD(a, b) // the new b
+			a, b, c, d = d, a, b, c
+		}
+		for i := range s {
+			PADDD(&s[i], &save[i])
+		}
+	}
+
+	for i := range s {
+		for k, v := range s[i].Uint32s() {
+			state[k][i] = v
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package sse
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/mbtest"
+)
+
+func md5block4Slice(state [][4]uint32, p [][]byte) {
+	md5block4((*[4][4]uint32)(state), (*[4][]byte)(p))
+}
+
+func TestMD5Block4(t *testing.T) {
+	mbtest.TestBlock(t, mbtest.MD5, 4, md5block4Slice)
+}
+
+func BenchmarkMD5Block4(b *testing.B) {
+	mbtest.BenchmarkBlock(b, mbtest.MD5, 4, md5block4Slice)
+}
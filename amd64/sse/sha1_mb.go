@@ -0,0 +1,126 @@
+package sse
+
+import "github.com/emmansun/simd/alg/sha1"
+
+// Multi-buffer SHA-1 and MD5: dword k of each register holds message k, the four messages are
+// compressed with the scalar data flow on vertical registers. The message blocks are transposed on
+// input, the states are gathered into and scattered from the vertical registers once per call.
+
+// transpose4x4 transposes the 4x4 dwords matrix of the rows r[0..3].
+func transpose4x4(r *[4]*XMM) {
+	var t [4]XMM
+	MOVOU(&t[0], r[0])
+	PUNPCKLDQ(&t[0], r[1]) // r00 r10 r01 r11
+	MOVOU(&t[1], r[0])
+	PUNPCKHDQ(&t[1], r[1]) // r02 r12 r03 r13
+	MOVOU(&t[2], r[2])
+	PUNPCKLDQ(&t[2], r[3]) // r20 r30 r21 r31
+	MOVOU(&t[3], r[2])
+	PUNPCKHDQ(&t[3], r[3]) // r22 r32 r23 r33
+	MOVOU(r[0], &t[0])
+	PUNPCKLQDQ(r[0], &t[2])
+	MOVOU(r[1], &t[0])
+	PUNPCKHQDQ(r[1], &t[2])
+	MOVOU(r[2], &t[1])
+	PUNPCKLQDQ(r[2], &t[3])
+	MOVOU(r[3], &t[1])
+	PUNPCKHQDQ(r[3], &t[3])
+}
+
+// prold rotates each dword of x left by n bits, t is a temporary register.
+func prold(x, t *XMM, n uint) {
+	MOVOU(t, x)
+	PSLLD(x, n)
+	PSRLD(t, 32-n)
+	POR(x, t)
+}
+
+// loadBlocks4 loads the 64 bytes blocks at offset n of the four messages into w[0..15] vertically,
+// the words are byte swapped with flip if it is not nil.
+func loadBlocks4(w []XMM, p *[4][]byte, n int, flip *XMM) {
+	for i := 0; i < 16; i += 4 {
+		r := [4]*XMM{&w[i], &w[i+1], &w[i+2], &w[i+3]}
+		for k := range r {
+			SetBytes(r[k], p[k][n+4*i:n+4*i+16])
+			if flip != nil {
+				PSHUFB(r[k], flip)
+			}
+		}
+		transpose4x4(&r)
+	}
+}
+
+// sha1block4 compresses the blocks of four messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func sha1block4(state *[4][5]uint32, p *[4][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("sse: sha1block4 messages of different lengths")
+		}
+	}
+	var (
+		s, save   [5]XMM
+		w         [80]XMM // the message schedule on the stack
+		flip_mask = Set64(0x0c0d0e0f08090a0b, 0x0405060700010203)
+		F         = &XMM{}
+		T         = &XMM{}
+		K         = &XMM{}
+	)
+	for i := range s {
+		s[i] = SetEpi32(state[0][i], state[1][i], state[2][i], state[3][i])
+	}
+
+	for n := 0; n+sha1.BlockSize <= len(p[0]); n += sha1.BlockSize {
+		save = s
+		loadBlocks4(w[:], p, n, &flip_mask)
+		for j := 16; j < 80; j++ {
+			MOVOU(&w[j], &w[j-3])
+			PXOR(&w[j], &w[j-8])
+			PXOR(&w[j], &w[j-14])
+			PXOR(&w[j], &w[j-16])
+			prold(&w[j], T, 1)
+		}
+		a, b, c, d, e := &s[0], &s[1], &s[2], &s[3], &s[4]
+		for i := 0; i < 80; i++ {
+			switch {
+			case i < 20:
+				// d ^ (b & (c ^ d))
+				MOVOU(F, c)
+				PXOR(F, d)
+				PAND(F, b)
+				PXOR(F, d)
+			case i < 40, i >= 60:
+				MOVOU(F, b)
+				PXOR(F, c)
+				PXOR(F, d)
+			default:
+				// (b & c) | (d & (b | c))
+				MOVOU(F, b)
+				POR(F, c)
+				PAND(F, d)
+				MOVOU(T, b)
+				PAND(T, c)
+				POR(F, T)
+			}
+			k := sha1.K[i/20]
+			*K = SetEpi32(k, k, k, k)
+			PADDD(e, F)
+			PADDD(e, K)
+			PADDD(e, &w[i])
+			MOVOU(F, a)
+			prold(F, T, 5)
+			PADDD(e, F) // e = (a <<< 5) + F + e + W + K, the new a
+			prold(b, T, 30)
+			a, b, c, d, e = e, a, b, c, d
+		}
+		for i := range s {
+			PADDD(&s[i], &save[i])
+		}
+	}
+
+	for i := range s {
+		for k, v := range s[i].Uint32s() {
+			state[k][i] = v
+		}
+	}
+}
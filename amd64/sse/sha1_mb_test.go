@@ -0,0 +1,36 @@
+package sse
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/mbtest"
+)
+
+func TestTranspose4x4(t *testing.T) {
+	var m [4]XMM
+	r := [4]*XMM{}
+	for i := range r {
+		r[i] = &m[i]
+		*r[i] = SetEpi32(uint32(i*4), uint32(i*4+1), uint32(i*4+2), uint32(i*4+3))
+	}
+	transpose4x4(&r)
+	for i := range r {
+		for j, v := range r[i].Uint32s() {
+			if v != uint32(j*4+i) {
+				t.Fatalf("row %d = %v", i, r[i].Uint32s())
+			}
+		}
+	}
+}
+
+func sha1block4Slice(state [][5]uint32, p [][]byte) {
+	sha1block4((*[4][5]uint32)(state), (*[4][]byte)(p))
+}
+
+func TestSHA1Block4(t *testing.T) {
+	mbtest.TestBlock(t, mbtest.SHA1, 4, sha1block4Slice)
+}
+
+func BenchmarkSHA1Block4(b *testing.B) {
+	mbtest.BenchmarkBlock(b, mbtest.SHA1, 4, sha1block4Slice)
+}
@@ -3,10 +3,15 @@ package sse
 import (
 	"encoding/binary"
 	"math"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/gf2"
+	"github.com/emmansun/simd/internal/opcount"
 )
 
 type XMM struct {
 	bytes [16]byte
+	sym   *gf2.Vec // the symbolic bits of the register, nil if it is not symbolic, a pointer keeps XMM comparable
 }
 
 func (m *XMM) Bytes() []byte {
@@ -25,17 +30,104 @@ func (m *XMM) Uint16s() []uint16 {
 	return []uint16{binary.LittleEndian.Uint16(m.bytes[:]), binary.LittleEndian.Uint16(m.bytes[2:]), binary.LittleEndian.Uint16(m.bytes[4:]), binary.LittleEndian.Uint16(m.bytes[6:]), binary.LittleEndian.Uint16(m.bytes[8:]), binary.LittleEndian.Uint16(m.bytes[10:]), binary.LittleEndian.Uint16(m.bytes[12:]), binary.LittleEndian.Uint16(m.bytes[14:])}
 }
 
+// SetSymbolic makes dst symbolic with the bits v of the little-endian bytes, and sets the bytes to x.
+// PXOR, MOVOU, PSRLDQ, PSLLDQ, PSHUFD and PCLMULQDQ with a constant operand carry the symbolic bits,
+// so a GF(2) linear kernel run on symbolic registers computes its linear map, see internal/gf2.
+func SetSymbolic(dst *XMM, v gf2.Vec, x []byte) {
+	copy(dst.bytes[:], x)
+	dst.sym = &v
+}
+
+// Symbolic returns the symbolic bits of m, nil if m is not symbolic.
+func (m *XMM) Symbolic() gf2.Vec {
+	if m.sym == nil {
+		return nil
+	}
+	return *m.sym
+}
+
+// symbolic returns the symbolic bits of the operands, the constant ones must be zero, nil if none is symbolic.
+func symbolic(ms ...*XMM) []gf2.Vec {
+	for _, m := range ms {
+		if m.sym != nil {
+			v := make([]gf2.Vec, len(ms))
+			for i, m := range ms {
+				v[i] = m.Symbolic()
+				if v[i] == nil {
+					v[i] = gf2.Const(m.bytes[:])
+				}
+			}
+			return v
+		}
+	}
+	return nil
+}
+
+// counter records the executed instructions, see SetCounter.
+var counter *opcount.Counter
+
+// SetCounter makes the instructions of the GHASH and SM3 kernels (MOVOU, SetBytes and StoreBytes,
+// PXOR, PAND, PSHUFB, PSHUFD, PSLLD, PSRLD, PSRAD, PSLLDQ, PSRLDQ, PALIGNR and PCLMULQDQ) record
+// themselves into c, nil stops the recording. The recording is not safe for concurrent use.
+func SetCounter(c *opcount.Counter) {
+	counter = c
+}
+
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes PSHUFB and the AES instructions report the indices of their table lookups, the
+// shuffle mask and the S-box inputs, to t, nil stops the reporting. The reporting is not safe for
+// concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
+// setSymbolic sets the symbolic bits of m to v, m is not symbolic if v is nil.
+func (m *XMM) setSymbolic(v gf2.Vec) {
+	m.sym = nil
+	if v != nil {
+		m.sym = &v
+	}
+}
+
 func MOVOU_U64(dst *XMM, hi, lo uint64) {
 	binary.LittleEndian.PutUint64(dst.bytes[:], lo)
 	binary.LittleEndian.PutUint64(dst.bytes[8:], hi)
 }
 
 func MOVOU(dst, src *XMM) {
+	counter.Op("MOVOU", dst, src)
+	movou(dst, src)
+}
+
+// movou is MOVOU without the recording, for the instructions which compute into a temporary register.
+func movou(dst, src *XMM) {
 	copy(dst.bytes[:], src.bytes[:])
+	dst.sym = src.sym
 }
 
+// SetBytes loads the 16 bytes b into dst, it records a MOVOU.
 func SetBytes(dst *XMM, b []byte) {
+	counter.Op("MOVOU", dst)
 	copy(dst.bytes[:], b)
+	dst.sym = nil
+}
+
+// StoreBytes stores the 16 bytes of src into b, it records a MOVOU.
+func StoreBytes(b []byte, src *XMM) {
+	counter.Op("MOVOU", nil, src)
+	copy(b, src.bytes[:])
 }
 
 func Set64(hi, lo uint64) (m XMM) {
@@ -59,6 +151,7 @@ func mm_and_si128(dst, src *XMM) {
 }
 
 func PAND(dst, src *XMM) {
+	counter.Op("PAND", dst, dst, src)
 	mm_and_si128(dst, src)
 }
 
@@ -79,7 +172,12 @@ func mm_xor_si128(dst, src *XMM) {
 }
 
 func PXOR(dst, src *XMM) {
+	counter.Op("PXOR", dst, dst, src)
+	v := symbolic(dst, src)
 	mm_xor_si128(dst, src)
+	if v != nil {
+		dst.setSymbolic(v[0].Add(v[1]))
+	}
 }
 
 func mm_andnot_si128(dst, src *XMM) {
@@ -102,10 +200,12 @@ func mm_shuffle_epi8(dst, src *XMM) {
 			tmp.bytes[i] = dst.bytes[idx]
 		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PSHUFB(dst, src *XMM) {
+	counter.Op("PSHUFB", dst, dst, src)
+	lookup("PSHUFB", src.bytes[:])
 	mm_shuffle_epi8(dst, src)
 }
 
@@ -131,8 +231,20 @@ func mm_srli_epi32(dst *XMM, imm uint) {
 	binary.LittleEndian.PutUint32(dst.bytes[12:], e3)
 }
 
+func mm_srli_epi16(dst *XMM, imm uint) {
+	for i := 0; i < 16; i += 2 {
+		w := binary.LittleEndian.Uint16(dst.bytes[i:])
+		if imm > 15 {
+			w = 0
+		} else {
+			w >>= imm
+		}
+		binary.LittleEndian.PutUint16(dst.bytes[i:], w)
+	}
+}
+
 func PSRLW(dst *XMM, imm uint) {
-	mm_srli_epi32(dst, imm)
+	mm_srli_epi16(dst, imm)
 }
 
 func mm_slli_epi32(dst *XMM, imm uint) {
@@ -157,8 +269,20 @@ func mm_slli_epi32(dst *XMM, imm uint) {
 	binary.LittleEndian.PutUint32(dst.bytes[12:], e3)
 }
 
+func mm_slli_epi16(dst *XMM, imm uint) {
+	for i := 0; i < 16; i += 2 {
+		w := binary.LittleEndian.Uint16(dst.bytes[i:])
+		if imm > 15 {
+			w = 0
+		} else {
+			w <<= imm
+		}
+		binary.LittleEndian.PutUint16(dst.bytes[i:], w)
+	}
+}
+
 func PSLLW(dst *XMM, imm uint) {
-	mm_slli_epi32(dst, imm)
+	mm_slli_epi16(dst, imm)
 }
 
 func mm_srli_epi64(dst *XMM, imm uint) {
@@ -176,7 +300,8 @@ func mm_srli_epi64(dst *XMM, imm uint) {
 }
 
 func PSRLD(dst *XMM, imm uint) {
-	mm_srli_epi64(dst, imm)
+	counter.Op("PSRLD", dst, dst)
+	mm_srli_epi32(dst, imm)
 }
 
 func PSRLQ(dst *XMM, imm uint) {
@@ -198,7 +323,8 @@ func mm_slli_epi64(dst *XMM, imm uint) {
 }
 
 func PSLLD(dst *XMM, imm uint) {
-	mm_slli_epi64(dst, imm)
+	counter.Op("PSLLD", dst, dst)
+	mm_slli_epi32(dst, imm)
 }
 
 func PSLLQ(dst *XMM, imm uint) {
@@ -206,12 +332,16 @@ func PSLLQ(dst *XMM, imm uint) {
 }
 
 func PSHUFD(dst, src *XMM, imm uint) {
+	counter.Op("PSHUFD", dst, src)
 	tmp := XMM{}
 	for i := 0; i < 4; i++ {
 		idx := (imm >> (i * 2)) & 0x03
 		copy(tmp.bytes[i*4:], src.bytes[idx*4:])
+		if src.sym != nil {
+			tmp.setSymbolic(gf2.Concat(tmp.Symbolic(), src.Symbolic()[idx*32:][:32]))
+		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func clmul(a, b uint64) (hi, lo uint64) {
@@ -234,6 +364,7 @@ func clmul(a, b uint64) (hi, lo uint64) {
 }
 
 func PCLMULQDQ(dst, src *XMM, imm uint) {
+	counter.Op("PCLMULQDQ", dst, dst, src)
 	var tmp1, tmp2 uint64
 	if imm&1 == 0 {
 		tmp1 = binary.LittleEndian.Uint64(dst.bytes[:])
@@ -246,12 +377,24 @@ func PCLMULQDQ(dst, src *XMM, imm uint) {
 		tmp2 = binary.LittleEndian.Uint64(src.bytes[8:])
 	}
 
+	var sym gf2.Vec
+	switch {
+	case dst.sym != nil && src.sym != nil:
+		panic("sse: PCLMULQDQ of two symbolic operands is not linear")
+	case dst.sym != nil:
+		sym = dst.Symbolic()[64*(imm&1):][:64].Clmul(tmp2)
+	case src.sym != nil:
+		sym = src.Symbolic()[64*(imm>>4&1):][:64].Clmul(tmp1)
+	}
+
 	hi, lo := clmul(tmp1, tmp2)
 	binary.LittleEndian.PutUint64(dst.bytes[:], lo)
 	binary.LittleEndian.PutUint64(dst.bytes[8:], hi)
+	dst.setSymbolic(sym)
 }
 
 func PSRLDQ(dst *XMM, imm uint) {
+	counter.Op("PSRLDQ", dst, dst)
 	tmp := XMM{}
 	if imm > 15 {
 		for i := 0; i < 16; i++ {
@@ -265,20 +408,36 @@ func PSRLDQ(dst *XMM, imm uint) {
 			tmp.bytes[i] = 0
 		}
 	}
-	MOVOU(dst, &tmp)
+	if v := symbolic(dst); v != nil {
+		imm = min(imm, 16)
+		tmp.setSymbolic(gf2.Concat(v[0][8*imm:], gf2.Const(make([]byte, imm))))
+	}
+	movou(dst, &tmp)
 }
 
 func PSRAW(dst *XMM, imm byte) {
-	for i := 0; i < 4; i++ {
-		w := int32(binary.LittleEndian.Uint32(dst.bytes[i*4:]))
-		if imm > 31 {
-			imm = 31
-		}
-		binary.LittleEndian.PutUint32(dst.bytes[i*4:], uint32(w>>imm))
+	if imm > 15 {
+		imm = 15
+	}
+	for i := 0; i < 16; i += 2 {
+		w := int16(binary.LittleEndian.Uint16(dst.bytes[i:]))
+		binary.LittleEndian.PutUint16(dst.bytes[i:], uint16(w>>imm))
+	}
+}
+
+func PSRAD(dst *XMM, imm byte) {
+	counter.Op("PSRAD", dst, dst)
+	if imm > 31 {
+		imm = 31
+	}
+	for i := 0; i < 16; i += 4 {
+		w := int32(binary.LittleEndian.Uint32(dst.bytes[i:]))
+		binary.LittleEndian.PutUint32(dst.bytes[i:], uint32(w>>imm))
 	}
 }
 
 func PSLLDQ(dst *XMM, imm byte) {
+	counter.Op("PSLLDQ", dst, dst)
 	tmp := XMM{}
 	if imm > 15 {
 		for i := 0; i < 16; i++ {
@@ -292,7 +451,11 @@ func PSLLDQ(dst *XMM, imm byte) {
 			tmp.bytes[i] = 0
 		}
 	}
-	MOVOU(dst, &tmp)
+	if v := symbolic(dst); v != nil {
+		imm = min(imm, 16)
+		tmp.setSymbolic(gf2.Concat(gf2.Const(make([]byte, imm)), v[0][:128-8*int(imm)]))
+	}
+	movou(dst, &tmp)
 }
 
 func PMULHUW(dst, src *XMM) {
@@ -302,7 +465,7 @@ func PMULHUW(dst, src *XMM) {
 		e1 := binary.LittleEndian.Uint16(src.bytes[i*2:])
 		binary.LittleEndian.PutUint16(tmp.bytes[i*2:], uint16((uint32(e0)*uint32(e1))>>16))
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PMULLW(dst, src *XMM) {
@@ -312,7 +475,7 @@ func PMULLW(dst, src *XMM) {
 		e1 := int16(binary.LittleEndian.Uint16(src.bytes[i*2:]))
 		binary.LittleEndian.PutUint16(tmp.bytes[i*2:], uint16(e0*e1))
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PSUBUSB(dst, src *XMM) {
@@ -344,6 +507,51 @@ func PADDB(dst, src *XMM) {
 	}
 }
 
+func PADDD(dst, src *XMM) {
+	for i := 0; i < 16; i += 4 {
+		a := binary.LittleEndian.Uint32(dst.bytes[i:])
+		b := binary.LittleEndian.Uint32(src.bytes[i:])
+		binary.LittleEndian.PutUint32(dst.bytes[i:], a+b)
+	}
+}
+
+// PALIGNR concatenates dst (high) and src (low), shifts the 32 bytes right by imm bytes and stores the low 16 bytes to dst.
+func PALIGNR(dst, src *XMM, imm byte) {
+	counter.Op("PALIGNR", dst, dst, src)
+	var t [32]byte
+	copy(t[:], src.bytes[:])
+	copy(t[16:], dst.bytes[:])
+	tmp := XMM{}
+	if imm < 32 {
+		copy(tmp.bytes[:], t[imm:])
+	}
+	movou(dst, &tmp)
+}
+
+// PUNPCKLDQ interleaves the low dwords of dst and src, dst = [dst0, src0, dst1, src1].
+func PUNPCKLDQ(dst, src *XMM) {
+	d, s := dst.Uint32s(), src.Uint32s()
+	*dst = SetEpi32(d[0], s[0], d[1], s[1])
+}
+
+// PUNPCKHDQ interleaves the high dwords of dst and src, dst = [dst2, src2, dst3, src3].
+func PUNPCKHDQ(dst, src *XMM) {
+	d, s := dst.Uint32s(), src.Uint32s()
+	*dst = SetEpi32(d[2], s[2], d[3], s[3])
+}
+
+// PUNPCKLQDQ interleaves the low qwords of dst and src, dst = [dst0, src0].
+func PUNPCKLQDQ(dst, src *XMM) {
+	d, s := dst.Uint64s(), src.Uint64s()
+	*dst = Set64(s[0], d[0])
+}
+
+// PUNPCKHQDQ interleaves the high qwords of dst and src, dst = [dst1, src1].
+func PUNPCKHQDQ(dst, src *XMM) {
+	d, s := dst.Uint64s(), src.Uint64s()
+	*dst = Set64(s[1], d[1])
+}
+
 func PCMPGTB(dst, src *XMM) {
 	tmp := XMM{}
 	for i := 0; i < 16; i++ {
@@ -353,7 +561,7 @@ func PCMPGTB(dst, src *XMM) {
 			tmp.bytes[i] = 0
 		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PCMPEQB(dst, src *XMM) {
@@ -365,7 +573,7 @@ func PCMPEQB(dst, src *XMM) {
 			tmp.bytes[i] = 0
 		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PMOVMSKB(src *XMM) uint64 {
@@ -393,7 +601,7 @@ func PMADDUBSW(dst, src *XMM) {
 		ret := SaturateAdd16(int16(dst.bytes[i*2])*int16(int8(src.bytes[i*2])), int16(dst.bytes[i*2+1])*int16(int8(src.bytes[i*2+1])))
 		binary.LittleEndian.PutUint16(tmp.bytes[i*2:], uint16(ret))
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 func PMADDWD(dst, src *XMM) {
@@ -403,7 +611,7 @@ func PMADDWD(dst, src *XMM) {
 		ret += int32(int16(binary.LittleEndian.Uint16(dst.bytes[i*4+2:]))) * int32(int16(binary.LittleEndian.Uint16(src.bytes[i*4+2:])))
 		binary.LittleEndian.PutUint32(tmp.bytes[i*4:], uint32(ret))
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 // _mm_blend_epi16
@@ -417,7 +625,7 @@ func PBLENDW(dst, a, b *XMM, imm byte) {
 			copy(tmp.bytes[i*2:], a.bytes[i*2:])
 		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
 
 // _mm_blendv_epi8
@@ -431,5 +639,5 @@ func PBLENDVB(dst, a, b, mask *XMM) {
 			tmp.bytes[i] = a.bytes[i]
 		}
 	}
-	MOVOU(dst, &tmp)
+	movou(dst, &tmp)
 }
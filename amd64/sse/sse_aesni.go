@@ -1,6 +1,13 @@
 package sse
 
-import "github.com/emmansun/simd/alg/aes"
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/aes"
+)
+
+//go:generate go run ../../cmd/sboxgen -pkg sse -gfni -o sbox_params.go
 
 var shift_row = Set64(0x0B06010C07020D08, 0x030E09040F0A0500)
 var shift_row_inv = Set64(0x0306090C0F020508, 0x0B0E0104070A0D00)
@@ -16,13 +23,92 @@ func mm_aesenclast_si128(state, rk *XMM) {
 	}
 	// State XOR RoundKey
 	mm_xor_si128(&tmp, rk)
-	MOVOU(state, &tmp)
+	movou(state, &tmp)
 }
 
 func AESENCLAST(state, rk *XMM) {
+	lookup("AESENCLAST", state.bytes[:])
 	mm_aesenclast_si128(state, rk)
 }
 
+func mm_aesenc_si128(state, rk *XMM) {
+	// ShiftRows
+	mm_shuffle_epi8(state, &shift_row)
+	// SubBytes
+	aes.SubBytes(&state.bytes)
+	// MixColumns
+	aes.MixColumns(&state.bytes)
+	// State XOR RoundKey
+	mm_xor_si128(state, rk)
+}
+
+// AESENC performs one round of the AES encryption.
+func AESENC(state, rk *XMM) {
+	lookup("AESENC", state.bytes[:])
+	mm_aesenc_si128(state, rk)
+}
+
+func mm_aesdec_si128(state, rk *XMM) {
+	// InvShiftRows
+	mm_shuffle_epi8(state, &shift_row_inv)
+	// InvSubBytes
+	aes.InvSubBytes(&state.bytes)
+	// InvMixColumns
+	aes.InvMixColumns(&state.bytes)
+	// State XOR RoundKey
+	mm_xor_si128(state, rk)
+}
+
+// AESDEC performs one round of the AES equivalent inverse cipher,
+// the round key must be transformed by AESIMC.
+func AESDEC(state, rk *XMM) {
+	lookup("AESDEC", state.bytes[:])
+	mm_aesdec_si128(state, rk)
+}
+
+func mm_aesdeclast_si128(state, rk *XMM) {
+	// InvShiftRows
+	mm_shuffle_epi8(state, &shift_row_inv)
+	// InvSubBytes
+	aes.InvSubBytes(&state.bytes)
+	// State XOR RoundKey
+	mm_xor_si128(state, rk)
+}
+
+// AESDECLAST performs the last round of the AES decryption.
+func AESDECLAST(state, rk *XMM) {
+	lookup("AESDECLAST", state.bytes[:])
+	mm_aesdeclast_si128(state, rk)
+}
+
+func mm_aesimc_si128(dst, src *XMM) {
+	MOVOU(dst, src)
+	aes.InvMixColumns(&dst.bytes)
+}
+
+// AESIMC applies InvMixColumns to the round key src, for the decryption round keys of AESDEC.
+func AESIMC(dst, src *XMM) {
+	mm_aesimc_si128(dst, src)
+}
+
+func mm_aeskeygenassist_si128(dst, src *XMM, imm byte) {
+	x1 := binary.LittleEndian.Uint32(src.bytes[4:])
+	x3 := binary.LittleEndian.Uint32(src.bytes[12:])
+	x1, x3 = aes.SubWord(x1), aes.SubWord(x3)
+	binary.LittleEndian.PutUint32(dst.bytes[:], x1)
+	binary.LittleEndian.PutUint32(dst.bytes[4:], bits.RotateLeft32(x1, -8)^uint32(imm))
+	binary.LittleEndian.PutUint32(dst.bytes[8:], x3)
+	binary.LittleEndian.PutUint32(dst.bytes[12:], bits.RotateLeft32(x3, -8)^uint32(imm))
+}
+
+// AESKEYGENASSIST computes [SubWord(X1), RotWord(SubWord(X1)) ^ imm, SubWord(X3), RotWord(SubWord(X3)) ^ imm]
+// of the dwords X1 and X3 of src, RotWord is a right rotation by 8 bits of the little-endian dword.
+func AESKEYGENASSIST(dst, src *XMM, imm byte) {
+	lookup("AESKEYGENASSIST", src.bytes[4:8])
+	lookup("AESKEYGENASSIST", src.bytes[12:])
+	mm_aeskeygenassist_si128(dst, src, imm)
+}
+
 func SboxWithAESNI(x, m1l, m1h, m2l, m2h *XMM) {
 	y := &XMM{}
 	z := &XMM{}
@@ -3,8 +3,12 @@ package sse
 import (
 	"testing"
 
+	"github.com/emmansun/simd/alg/camellia"
+	"github.com/emmansun/simd/alg/sbox"
 	"github.com/emmansun/simd/alg/sm4"
 	"github.com/emmansun/simd/alg/zuc"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
 )
 
 func testSboxWithAESNI(t *testing.T, idx int, m1l, m1h, m2l, m2h *XMM, sbox *[256]byte) {
@@ -156,3 +160,109 @@ func TestZUCSBOXWithAESNI(t *testing.T) {
 		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
 	}
 }
+
+func TestGeneratedSBOXWithAESNIParams(t *testing.T) {
+	for i, c := range SM4SboxAESNIParams {
+		m1l := &XMM{}
+		m1h := &XMM{}
+		m2l := &XMM{}
+		m2h := &XMM{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &sm4.SBOX)
+	}
+	for i, c := range ZUCSboxAESNIParams {
+		m1l := &XMM{}
+		m1h := &XMM{}
+		m2l := &XMM{}
+		m2h := &XMM{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
+	}
+}
+
+// The examples of the Intel AES-NI white paper, the values are 128 bits integers.
+func TestAESInstructions(t *testing.T) {
+	state := Set64(0x7b5b546573745665, 0x63746f725d53475d)
+	rk := Set64(0x4869285368617929, 0x5b477565726f6e5d)
+	cases := []struct {
+		name   string
+		f      func(dst *XMM)
+		hi, lo uint64
+	}{
+		{"AESENC", func(dst *XMM) { AESENC(dst, &rk) }, 0xa8311c2f9fdba3c5, 0x8b104b58ded7e595},
+		{"AESENCLAST", func(dst *XMM) { AESENCLAST(dst, &rk) }, 0xc7fb881e938c5964, 0x177ec42553fdc611},
+		{"AESDEC", func(dst *XMM) { AESDEC(dst, &rk) }, 0x138ac342faea2787, 0xb58eb95eb730392a},
+		{"AESDECLAST", func(dst *XMM) { AESDECLAST(dst, &rk) }, 0xc5a391ef6b317f95, 0xd410637b72a593d0},
+		{"AESIMC", func(dst *XMM) { AESIMC(dst, dst) }, 0x627a6f6644b109c8, 0x2b18330a81c3b3e5},
+	}
+	for _, c := range cases {
+		dst := state
+		c.f(&dst)
+		if want := Set64(c.hi, c.lo); dst != want {
+			t.Errorf("%s got %x, want %x", c.name, dst.Uint64s(), want.Uint64s())
+		}
+	}
+	src := Set64(0x3c4fcf098815f7ab, 0xa6d2ae2816157e2b)
+	dst := &XMM{}
+	// SubWord(0xa6d2ae28) = 0x24b5e434, SubWord(0x3c4fcf09) = 0xeb848a01
+	AESKEYGENASSIST(dst, &src, 1)
+	if want := Set64(0x01eb848beb848a01, 0x3424b5e524b5e434); *dst != want {
+		t.Errorf("AESKEYGENASSIST got %x, want %x", dst.Uint64s(), want.Uint64s())
+	}
+}
+
+// compiledSboxes are the S-boxes which alg/sbox compiles to the AESNI and GFNI constants.
+var compiledSboxes = []struct {
+	name string
+	sbox *[256]byte
+}{
+	{"SM4", &sm4.SBOX},
+	{"ZUC S1", &zuc.SBOX},
+	{"Camellia s1", &camellia.SBOX1},
+}
+
+func TestCompiledSBOXWithAESNI(t *testing.T) {
+	for i, c := range compiledSboxes {
+		tables, err := sbox.Compile(c.sbox)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		m1l, m1h, m2l, m2h := &XMM{}, &XMM{}, &XMM{}, &XMM{}
+		SetBytes(m1l, tables.M1L[:])
+		SetBytes(m1h, tables.M1H[:])
+		SetBytes(m2l, tables.M2L[:])
+		SetBytes(m2h, tables.M2H[:])
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, c.sbox)
+		// the tables are the ones GenLookupTable builds
+		l, h := &XMM{}, &XMM{}
+		GenLookupTable(tables.AESNI.M1, tables.AESNI.C1, l, h)
+		if *l != *m1l || *h != *m1h {
+			t.Errorf("%s: M1L, M1H = %x, %x; GenLookupTable %x, %x", c.name, m1l.Bytes(), m1h.Bytes(), l.Bytes(), h.Bytes())
+		}
+		GenLookupTable(tables.AESNI.M2, tables.AESNI.C2, l, h)
+		if *l != *m2l || *h != *m2h {
+			t.Errorf("%s: M2L, M2H = %x, %x; GenLookupTable %x, %x", c.name, m2l.Bytes(), m2h.Bytes(), l.Bytes(), h.Bytes())
+		}
+	}
+}
+
+func TestLookupTracer(t *testing.T) {
+	run := func(f func(x *XMM)) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			x := &XMM{}
+			SetBytes(x, secret)
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(x)
+		}
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "byte shuffle", Run: run(func(x *XMM) { PSHUFB(x, &shift_row) })},
+		{Name: "nibble lookup", Site: "PSHUFB", Run: run(func(x *XMM) { PSHUFB(&XMM{}, x) })},
+		{Name: "AESENC", Site: "AESENC", Run: run(func(x *XMM) { AESENC(x, &XMM{}) })},
+		{Name: "AESKEYGENASSIST", Site: "AESKEYGENASSIST", Run: run(func(x *XMM) { AESKEYGENASSIST(&XMM{}, x, 1) })},
+		{Name: "S-box", Site: "PSHUFB", Run: run(func(x *XMM) { SboxWithAESNI(x, &XMM{}, &XMM{}, &XMM{}, &XMM{}) })},
+	})
+}
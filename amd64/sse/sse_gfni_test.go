@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/emmansun/simd/alg/aes"
+	"github.com/emmansun/simd/alg/sbox"
 	"github.com/emmansun/simd/alg/sm4"
 	"github.com/emmansun/simd/alg/zuc"
 )
@@ -150,3 +151,29 @@ func TestZUCSBOXWithGFNI(t *testing.T) {
 		testSBOXWithGFNI(t, i+1, &m1, &m2, 0, 0x55, &zuc.SBOX)
 	}
 }
+
+func TestGeneratedSBOXWithGFNIParams(t *testing.T) {
+	for i, c := range SM4SboxGFNIParams {
+		m1 := Set64(c.M1, c.M1)
+		m2 := Set64(c.M2, c.M2)
+		testSBOXWithGFNI(t, i+1, &m1, &m2, c.C1, c.C2, &sm4.SBOX)
+	}
+	for i, c := range ZUCSboxGFNIParams {
+		m1 := Set64(c.M1, c.M1)
+		m2 := Set64(c.M2, c.M2)
+		testSBOXWithGFNI(t, i+1, &m1, &m2, c.C1, c.C2, &zuc.SBOX)
+	}
+}
+
+func TestCompiledSBOXWithGFNI(t *testing.T) {
+	for i, c := range compiledSboxes {
+		tables, err := sbox.Compile(c.sbox)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var m1, m2 XMM
+		SetBytes(&m1, tables.GFNIM1[:])
+		SetBytes(&m2, tables.GFNIM2[:])
+		testSBOXWithGFNI(t, i+1, &m1, &m2, tables.GFNI.C1, tables.GFNI.C2, c.sbox)
+	}
+}
@@ -0,0 +1,106 @@
+package sse
+
+import "github.com/emmansun/simd/alg/sha2"
+
+// The SHA extensions, https://www.intel.com/content/www/us/en/developer/articles/technical/intel-sha-extensions.html
+
+// SHA256MSG1 performs the intermediate calculation for the next four message dwords,
+// dst = [W0 + σ0(W1), W1 + σ0(W2), W2 + σ0(W3), W3 + σ0(W4)] of dst = [W0, W1, W2, W3] and src = [W4, ...].
+func SHA256MSG1(dst, src *XMM) {
+	w := dst.Uint32s()
+	w4 := src.Uint32s()[0]
+	*dst = SetEpi32(w[0]+sha2.SmallSigma0(w[1]), w[1]+sha2.SmallSigma0(w[2]), w[2]+sha2.SmallSigma0(w[3]), w[3]+sha2.SmallSigma0(w4))
+}
+
+// SHA256MSG2 performs the final calculation for the next four message dwords,
+// dst = [W16, W17, W18, W19] of the intermediate dst and src = [W12, W13, W14, W15], W18 and W19 depend on W16 and W17.
+func SHA256MSG2(dst, src *XMM) {
+	w := dst.Uint32s()
+	s := src.Uint32s()
+	w16 := w[0] + sha2.SmallSigma1(s[2])
+	w17 := w[1] + sha2.SmallSigma1(s[3])
+	w18 := w[2] + sha2.SmallSigma1(w16)
+	w19 := w[3] + sha2.SmallSigma1(w17)
+	*dst = SetEpi32(w16, w17, w18, w19)
+}
+
+// SHA256RNDS2 performs two rounds of SHA-256, dst is the state [H, G, D, C] (dword 0 to 3), src is the state
+// [F, E, B, A] and the low two dwords of wk are W[i] + K[i] and W[i+1] + K[i+1]. The new state [F, E, B, A] is stored
+// to dst, the new [H, G, D, C] is the old [F, E, B, A]. wk is the implicit XMM0 operand.
+func SHA256RNDS2(dst, src, wk *XMM) {
+	cdgh := dst.Uint32s()
+	abef := src.Uint32s()
+	k := wk.Uint32s()
+	a, b, c, d := abef[3], abef[2], cdgh[3], cdgh[2]
+	e, f, g, h := abef[1], abef[0], cdgh[1], cdgh[0]
+	for i := 0; i < 2; i++ {
+		t1 := h + sha2.Sigma1(e) + sha2.Ch(e, f, g) + k[i]
+		t2 := sha2.Sigma0(a) + sha2.Maj(a, b, c)
+		h, g, f, e = g, f, e, d+t1
+		d, c, b, a = c, b, a, t1+t2
+	}
+	*dst = SetEpi32(f, e, b, a)
+}
+
+// sha256block is the SHA-256 block function with the SHA extensions.
+func sha256block(state *[8]uint32, p []byte) {
+	var (
+		STATE0    = &XMM{}
+		STATE1    = &XMM{}
+		MSG       = &XMM{}
+		TMP       = &XMM{}
+		ABEF_SAVE = &XMM{}
+		CDGH_SAVE = &XMM{}
+		flip_mask = Set64(0x0c0d0e0f08090a0b, 0x0405060700010203)
+		K         = &XMM{}
+		w         [4]*XMM
+	)
+	for i := range w {
+		w[i] = &XMM{}
+	}
+	*STATE0 = SetEpi32(state[0], state[1], state[2], state[3]) // D C B A
+	*STATE1 = SetEpi32(state[4], state[5], state[6], state[7]) // H G F E
+	PSHUFD(STATE0, STATE0, 0xb1)                               // C D A B
+	PSHUFD(STATE1, STATE1, 0x1b)                               // E F G H
+	MOVOU(TMP, STATE0)
+	PALIGNR(STATE0, STATE1, 8)         // A B E F
+	PBLENDW(STATE1, STATE1, TMP, 0xf0) // C D G H
+
+	for len(p) >= 64 {
+		MOVOU(ABEF_SAVE, STATE0)
+		MOVOU(CDGH_SAVE, STATE1)
+		for i := 0; i < 4; i++ {
+			SetBytes(w[i], p[16*i:])
+			PSHUFB(w[i], &flip_mask)
+		}
+		for i := 0; i < 64; i += 4 {
+			// w[0] = W[i..i+3]
+			*K = SetEpi32(sha2.K256[i], sha2.K256[i+1], sha2.K256[i+2], sha2.K256[i+3])
+			MOVOU(MSG, w[0])
+			PADDD(MSG, K)
+			SHA256RNDS2(STATE1, STATE0, MSG)
+			PSHUFD(MSG, MSG, 0x0e)
+			SHA256RNDS2(STATE0, STATE1, MSG)
+			if i < 48 {
+				// W[i+16..i+19]
+				SHA256MSG1(w[0], w[1])
+				MOVOU(TMP, w[3])
+				PALIGNR(TMP, w[2], 4) // W[i+9..i+12]
+				PADDD(w[0], TMP)
+				SHA256MSG2(w[0], w[3])
+			}
+			w[0], w[1], w[2], w[3] = w[1], w[2], w[3], w[0]
+		}
+		PADDD(STATE0, ABEF_SAVE)
+		PADDD(STATE1, CDGH_SAVE)
+		p = p[64:]
+	}
+
+	PSHUFD(STATE0, STATE0, 0x1b) // F E B A
+	PSHUFD(STATE1, STATE1, 0xb1) // D C H G
+	MOVOU(TMP, STATE0)
+	PBLENDW(STATE0, STATE0, STATE1, 0xf0) // D C B A
+	PALIGNR(STATE1, TMP, 8)               // H G F E
+	copy(state[:4], STATE0.Uint32s())
+	copy(state[4:], STATE1.Uint32s())
+}
@@ -0,0 +1,64 @@
+package sse
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sha2"
+)
+
+func TestPALIGNR(t *testing.T) {
+	dst := Set64(0x1f1e1d1c1b1a1918, 0x1716151413121110)
+	src := Set64(0x0f0e0d0c0b0a0908, 0x0706050403020100)
+	PALIGNR(&dst, &src, 4)
+	want := Set64(0x131211100f0e0d0c, 0x0b0a090807060504)
+	if dst != want {
+		t.Errorf("PALIGNR = %x, want %x", dst.Bytes(), want.Bytes())
+	}
+}
+
+func TestSHA256RNDS2(t *testing.T) {
+	// two rounds of the first block of "abc" from the initial hash value
+	a, b, c, d, e, f, g, h := sha2.IV256[0], sha2.IV256[1], sha2.IV256[2], sha2.IV256[3], sha2.IV256[4], sha2.IV256[5], sha2.IV256[6], sha2.IV256[7]
+	cdgh := SetEpi32(h, g, d, c)
+	abef := SetEpi32(f, e, b, a)
+	wk := SetEpi32(0x61626380+sha2.K256[0], sha2.K256[1], 0, 0)
+	SHA256RNDS2(&cdgh, &abef, &wk)
+	// FIPS 180-2 Appendix B.1, the working variables of t = 1
+	want := SetEpi32(0xfa2a4622, 0x78ce7989, 0x5d6aebcd, 0x5a6ad9ad)
+	if cdgh != want {
+		t.Errorf("SHA256RNDS2 = %x, want %x", cdgh.Uint32s(), want.Uint32s())
+	}
+}
+
+func TestSHA256MSG(t *testing.T) {
+	var w [20]uint32
+	for i := range w[:16] {
+		w[i] = uint32(i)*0x9e3779b9 + 0x12345678
+	}
+	for i := 16; i < 20; i++ {
+		w[i] = w[i-16] + sha2.SmallSigma0(w[i-15]) + w[i-7] + sha2.SmallSigma1(w[i-2])
+	}
+	x := SetEpi32(w[0], w[1], w[2], w[3])
+	y := SetEpi32(w[4], w[5], w[6], w[7])
+	SHA256MSG1(&x, &y)
+	w9 := SetEpi32(w[9], w[10], w[11], w[12])
+	PADDD(&x, &w9)
+	w12 := SetEpi32(w[12], w[13], w[14], w[15])
+	SHA256MSG2(&x, &w12)
+	if want := SetEpi32(w[16], w[17], w[18], w[19]); x != want {
+		t.Errorf("SHA256MSG2 = %x, want %x", x.Uint32s(), want.Uint32s())
+	}
+}
+
+func TestSHA256Block(t *testing.T) {
+	msg := make([]byte, 300)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n++ {
+		if got, want := sha2.Sum256(sha256block, msg[:n]), sha256.Sum256(msg[:n]); got != want {
+			t.Fatalf("sha256block(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
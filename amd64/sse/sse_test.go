@@ -1,22 +1,61 @@
 package sse
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"testing"
+
+	"github.com/emmansun/simd/internal/gf2"
 )
 
 func TestPSRAW(t *testing.T) {
 	tmp1 := Set64(0xf070b030d0509010, 0xe060a020c0408000)
 	tmp := XMM{}
 	MOVOU(&tmp, &tmp1)
-	PSRAW(&tmp1, 32)
+	PSRAW(&tmp1, 16)
 	if fmt.Sprintf("%x", tmp1.Bytes()) != "ffffffffffffffffffffffffffffffff" {
 		t.Errorf("PSRAW() = %v; want ffffffff...", fmt.Sprintf("%x", tmp1.Bytes()))
 	}
 	PSRAW(&tmp, 4)
+	if fmt.Sprintf("%x", tmp.Bytes()) != "00f804fc02fa06fe01f905fd03fb07ff" {
+		t.Errorf("PSRAW() = %v; want 00f804fc02fa06fe01f905fd03fb07ff", fmt.Sprintf("%x", tmp.Bytes()))
+	}
+}
+
+func TestPSRAD(t *testing.T) {
+	tmp1 := Set64(0xf070b030d0509010, 0xe060a020c0408000)
+	tmp := XMM{}
+	MOVOU(&tmp, &tmp1)
+	PSRAD(&tmp1, 32)
+	if fmt.Sprintf("%x", tmp1.Bytes()) != "ffffffffffffffffffffffffffffffff" {
+		t.Errorf("PSRAD() = %v; want ffffffff...", fmt.Sprintf("%x", tmp1.Bytes()))
+	}
+	PSRAD(&tmp, 4)
 	if fmt.Sprintf("%x", tmp.Bytes()) != "000804fc020a06fe010905fd030b07ff" {
-		t.Errorf("PSRAW() = %v; want 000804fc020a06fe010905fd030b07ff", fmt.Sprintf("%x", tmp.Bytes()))
+		t.Errorf("PSRAD() = %v; want 000804fc020a06fe010905fd030b07ff", fmt.Sprintf("%x", tmp.Bytes()))
+	}
+}
+
+func TestShifts(t *testing.T) {
+	cases := []struct {
+		name string
+		f    func(dst *XMM, imm uint)
+		want XMM
+	}{
+		{"PSRLW", PSRLW, Set64(0x0800080008000800, 0x00120456089a0cde)},
+		{"PSLLW", PSLLW, Set64(0x0010001000100010, 0x123056709ab0def0)},
+		{"PSRLD", PSRLD, Set64(0x0800180008001800, 0x00123456089abcde)},
+		{"PSLLD", PSLLD, Set64(0x0018001000180010, 0x123456709abcdef0)},
+		{"PSRLQ", PSRLQ, Set64(0x0800180018001800, 0x00123456789abcde)},
+		{"PSLLQ", PSLLQ, Set64(0x0018001800180010, 0x123456789abcdef0)},
+	}
+	for _, c := range cases {
+		x := Set64(0x8001800180018001, 0x0123456789abcdef)
+		c.f(&x, 4)
+		if x != c.want {
+			t.Errorf("%s() = %x; want %x", c.name, x.Bytes(), c.want.Bytes())
+		}
 	}
 }
 
@@ -181,3 +220,34 @@ func TestPMULHUW(t *testing.T) {
 		}
 	}
 }
+
+// TestSymbolic checks the symbolic bits of the instructions which carry them give their bytes.
+func TestSymbolic(t *testing.T) {
+	in, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0ff0e1d2c3b4a5968778695a4b3c2d1e0f")
+	POLY := Set64(0xc200000000000000, 0x0000000000000001)
+	cases := []struct {
+		name string
+		op   func(x, y *XMM)
+	}{
+		{"PXOR", func(x, y *XMM) { PXOR(x, y) }},
+		{"MOVOU", func(x, y *XMM) { MOVOU(x, y) }},
+		{"PSRLDQ", func(x, y *XMM) { PSRLDQ(x, 5) }},
+		{"PSLLDQ", func(x, y *XMM) { PSLLDQ(x, 5) }},
+		{"PSHUFD", func(x, y *XMM) { PSHUFD(x, y, 0x1b) }},
+		{"PCLMULQDQ dst", func(x, y *XMM) { PCLMULQDQ(x, &POLY, 0x01) }},
+		{"PCLMULQDQ src", func(x, y *XMM) {
+			T := POLY
+			PCLMULQDQ(&T, y, 0x11)
+			MOVOU(x, &T)
+		}},
+	}
+	for _, c := range cases {
+		var x, y XMM
+		SetSymbolic(&x, gf2.Var(0, 128), in[:16])
+		SetSymbolic(&y, gf2.Var(128, 128), in[16:])
+		c.op(&x, &y)
+		if got := x.Symbolic().Eval(in); !bytes.Equal(got, x.Bytes()) {
+			t.Errorf("%s: the symbolic bits give %x, want %x", c.name, got, x.Bytes())
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package arm64
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/aes"
+)
+
+type aesARM64Cipher struct {
+	enc []Vector128
+	dec []Vector128 // round keys of the equivalent inverse cipher
+}
+
+// NewAESCipher returns the AES-128/192/256 cipher with the ARMv8 AES instructions.
+func NewAESCipher(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, aes.KeySizeError(len(key))
+	}
+	c := &aesARM64Cipher{}
+	c.expandKey(key)
+	return c, nil
+}
+
+// subWord applies the S-box to the word w with AESE, ShiftRows does nothing on the duplicated word.
+func subWord(w uint32, ZERO, T *Vector128) uint32 {
+	VDUP_S(w, T)
+	AESE(ZERO, T)
+	return T.Uint32s()[0]
+}
+
+func (c *aesARM64Cipher) expandKey(key []byte) {
+	nk := len(key) / 4
+	nr := nk + 6
+	w := make([]uint32, 4*(nr+1))
+	for i := 0; i < nk; i++ {
+		w[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	ZERO, T := &Vector128{}, &Vector128{}
+	for i := nk; i < len(w); i++ {
+		t := w[i-1]
+		switch {
+		case i%nk == 0:
+			t = bits.RotateLeft32(subWord(t, ZERO, T), -8) ^ uint32(aes.Rcon[i/nk-1])
+		case nk > 6 && i%nk == 4:
+			t = subWord(t, ZERO, T)
+		}
+		w[i] = w[i-nk] ^ t
+	}
+	c.enc = make([]Vector128, nr+1)
+	c.dec = make([]Vector128, nr+1)
+	for r := range c.enc {
+		VLD1_4S(w[4*r:], &c.enc[r])
+	}
+	VMOV(&c.enc[nr], &c.dec[0])
+	for r := 1; r < nr; r++ {
+		AESIMC(&c.enc[nr-r], &c.dec[r])
+	}
+	VMOV(&c.enc[0], &c.dec[nr])
+}
+
+func (c *aesARM64Cipher) BlockSize() int {
+	return 16
+}
+
+func (c *aesARM64Cipher) Encrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("arm64: input not full block")
+	}
+	nr := len(c.enc) - 1
+	V0 := &Vector128{}
+	VLD1_16B(src, V0)
+	for r := 0; r < nr-1; r++ {
+		AESE(&c.enc[r], V0)
+		AESMC(V0, V0)
+	}
+	AESE(&c.enc[nr-1], V0)
+	VEOR(&c.enc[nr], V0, V0)
+	VST1_16B(V0, dst)
+}
+
+func (c *aesARM64Cipher) Decrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("arm64: input not full block")
+	}
+	nr := len(c.dec) - 1
+	V0 := &Vector128{}
+	VLD1_16B(src, V0)
+	for r := 0; r < nr-1; r++ {
+		AESD(&c.dec[r], V0)
+		AESIMC(V0, V0)
+	}
+	AESD(&c.dec[nr-1], V0)
+	VEOR(&c.dec[nr], V0, V0)
+	VST1_16B(V0, dst)
+}
@@ -1,9 +1,16 @@
 package arm64
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/gf2"
+	"github.com/emmansun/simd/internal/opcount"
+)
 
 type Vector128 struct {
 	bytes [16]byte
+	sym   *gf2.Vec // the symbolic bits of the register, nil if it is not symbolic, a pointer keeps Vector128 comparable
 }
 
 func (m *Vector128) Bytes() []byte {
@@ -22,8 +29,81 @@ func (m *Vector128) Uint16s() []uint16 {
 	return []uint16{binary.LittleEndian.Uint16(m.bytes[:]), binary.LittleEndian.Uint16(m.bytes[2:]), binary.LittleEndian.Uint16(m.bytes[4:]), binary.LittleEndian.Uint16(m.bytes[6:]), binary.LittleEndian.Uint16(m.bytes[8:]), binary.LittleEndian.Uint16(m.bytes[10:]), binary.LittleEndian.Uint16(m.bytes[12:]), binary.LittleEndian.Uint16(m.bytes[14:])}
 }
 
+// SetSymbolic makes dst symbolic with the bits v of the little-endian bytes, and sets the bytes to x.
+// VEOR, VMOV, VEXT and VPMULL with a constant operand carry the symbolic bits, so a GF(2) linear
+// kernel run on symbolic registers computes its linear map, see internal/gf2.
+func SetSymbolic(dst *Vector128, v gf2.Vec, x []byte) {
+	copy(dst.bytes[:], x)
+	dst.setSymbolic(v)
+}
+
+// Symbolic returns the symbolic bits of m, nil if m is not symbolic.
+func (m *Vector128) Symbolic() gf2.Vec {
+	if m.sym == nil {
+		return nil
+	}
+	return *m.sym
+}
+
+// counter records the executed instructions, see SetCounter.
+var counter *opcount.Counter
+
+// SetCounter makes the instructions of the GHASH and SM3 kernels (VLD1, VST1, VMOV, VDUP, VEOR,
+// VAND, VEXT, VREV32, VREV64, VSHL, VSLI, VSRI, VUSHR, VPMULL, VPMULL2 and the SM3 instructions)
+// record themselves into c, nil stops the recording. The recording is not safe for concurrent use.
+func SetCounter(c *opcount.Counter) {
+	counter = c
+}
+
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes VTBL, VTBX and the AES instructions report the indices of their table lookups,
+// the index vector and the S-box inputs, to t, nil stops the reporting. The reporting is not safe
+// for concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
+// setSymbolic sets the symbolic bits of m to v, m is not symbolic if v is nil.
+func (m *Vector128) setSymbolic(v gf2.Vec) {
+	m.sym = nil
+	if v != nil {
+		m.sym = &v
+	}
+}
+
+// symbolic returns the symbolic bits of the operands, the constant ones must be zero, nil if none is symbolic.
+func symbolic(ms ...*Vector128) []gf2.Vec {
+	for _, m := range ms {
+		if m.sym != nil {
+			v := make([]gf2.Vec, len(ms))
+			for i, m := range ms {
+				v[i] = m.Symbolic()
+				if v[i] == nil {
+					v[i] = gf2.Const(m.bytes[:])
+				}
+			}
+			return v
+		}
+	}
+	return nil
+}
+
 func VMOV(src *Vector128, dst *Vector128) {
+	counter.Op("VMOV", dst, src)
 	copy(dst.bytes[:], src.bytes[:])
+	dst.sym = src.sym
 }
 
 func VMOV_S(src, dst *Vector128, from, to byte) {
@@ -33,7 +113,9 @@ func VMOV_S(src, dst *Vector128, from, to byte) {
 }
 
 func VLD1_16B(rawbytes []byte, dst *Vector128) {
+	counter.Op("VLD1", dst)
 	copy(dst.bytes[:], rawbytes)
+	dst.sym = nil
 }
 
 func VLD1_8H(v []uint16, dst *Vector128) {
@@ -48,6 +130,12 @@ func VLD1_8H(v []uint16, dst *Vector128) {
 }
 
 func VLD1_4S(v []uint32, dst *Vector128) {
+	counter.Op("VLD1", dst)
+	vld1_4s(v, dst)
+}
+
+// vld1_4s is VLD1_4S without the recording, for the instructions which compute their words first.
+func vld1_4s(v []uint32, dst *Vector128) {
 	binary.LittleEndian.PutUint32(dst.bytes[:], v[0])
 	binary.LittleEndian.PutUint32(dst.bytes[4:], v[1])
 	binary.LittleEndian.PutUint32(dst.bytes[8:], v[2])
@@ -55,8 +143,15 @@ func VLD1_4S(v []uint32, dst *Vector128) {
 }
 
 func VLD1_2D(v []uint64, dst *Vector128) {
+	counter.Op("VLD1", dst)
+	vld1_2d(v, dst)
+}
+
+// vld1_2d is VLD1_2D without the recording, for the instructions which compute their doublewords first.
+func vld1_2d(v []uint64, dst *Vector128) {
 	binary.LittleEndian.PutUint64(dst.bytes[:], v[0])
 	binary.LittleEndian.PutUint64(dst.bytes[8:], v[1])
+	dst.sym = nil
 }
 
 func VLD2_16B(rawbytes []byte, dst1, dst2 *Vector128) {
@@ -85,6 +180,7 @@ func VLD4_16B(rawbytes []byte, dst1, dst2, dst3, dst4 *Vector128) {
 }
 
 func VDUP_BYTE(src byte, dst *Vector128) {
+	counter.Op("VDUP", dst)
 	for i := 0; i < 16; i += 1 {
 		dst.bytes[i] = src
 	}
@@ -98,16 +194,23 @@ func VDUP_S(src uint32, dst *Vector128) {
 }
 
 func VST1_16B(src *Vector128, dst []byte) {
+	counter.Op("VST1", nil, src)
 	copy(dst, src.bytes[:])
 }
 
 func VST1_4S(src *Vector128, dst []uint32) {
+	counter.Op("VST1", nil, src)
 	dst[0] = binary.LittleEndian.Uint32(src.bytes[:])
 	dst[1] = binary.LittleEndian.Uint32(src.bytes[4:])
 	dst[2] = binary.LittleEndian.Uint32(src.bytes[8:])
 	dst[3] = binary.LittleEndian.Uint32(src.bytes[12:])
 }
 
+func VST1_2D(src *Vector128, dst []uint64) {
+	dst[0] = binary.LittleEndian.Uint64(src.bytes[:])
+	dst[1] = binary.LittleEndian.Uint64(src.bytes[8:])
+}
+
 func VST2_16B(src1, src2 *Vector128, dst []byte) {
 	for i := 0; i < 16; i += 1 {
 		dst[2*i] = src1.bytes[i]
@@ -142,6 +245,7 @@ func VREV16(src, dst *Vector128) {
 }
 
 func VREV32_B(src, dst *Vector128) {
+	counter.Op("VREV32", dst, src)
 	tmp := Vector128{}
 	for i := 0; i < 16; i += 4 {
 		tmp.bytes[i] = src.bytes[i+3]
@@ -153,6 +257,7 @@ func VREV32_B(src, dst *Vector128) {
 }
 
 func VREV64_B(src, dst *Vector128) {
+	counter.Op("VREV64", dst, src)
 	tmp := Vector128{}
 	for i := 0; i < 16; i += 8 {
 		tmp.bytes[i] = src.bytes[i+7]
@@ -168,6 +273,7 @@ func VREV64_B(src, dst *Vector128) {
 }
 
 func VREV64_S(src, dst *Vector128) {
+	counter.Op("VREV64", dst, src)
 	tmp := Vector128{}
 	for i := 0; i < 16; i += 8 {
 		w1 := binary.LittleEndian.Uint32(src.bytes[i:])
@@ -181,6 +287,7 @@ func VREV64_S(src, dst *Vector128) {
 // Extract vector from pair of vectors
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/EXT--Extract-vector-from-pair-of-vectors-?lang=en
 func VEXT(imm byte, Vm, Vn, Vd *Vector128) {
+	counter.Op("VEXT", Vd, Vm, Vn)
 	imm = imm & 0xf
 	start := 16 - int(imm)
 	tmp := Vector128{}
@@ -190,10 +297,15 @@ func VEXT(imm byte, Vm, Vn, Vd *Vector128) {
 	for i := int(imm); i < 16; i++ {
 		tmp.bytes[i-int(imm)] = Vn.bytes[i]
 	}
+	if v := symbolic(Vm, Vn); v != nil {
+		tmp.setSymbolic(gf2.Concat(v[1][8*imm:], v[0][:8*imm]))
+	}
 	copy(Vd.bytes[:], tmp.bytes[:])
+	Vd.sym = tmp.sym
 }
 
 func VAND(src1, src2, dst *Vector128) {
+	counter.Op("VAND", dst, src1, src2)
 	for i := 0; i < 16; i += 1 {
 		dst.bytes[i] = src1.bytes[i] & src2.bytes[i]
 	}
@@ -206,9 +318,20 @@ func VORR(src1, src2, dst *Vector128) {
 }
 
 func VEOR(src1, src2, dst *Vector128) {
+	counter.Op("VEOR", dst, src1, src2)
+	veor(src1, src2, dst)
+}
+
+// veor is VEOR without the recording, for the instructions which xor an intermediate value.
+func veor(src1, src2, dst *Vector128) {
+	v := symbolic(src1, src2)
 	for i := 0; i < 16; i += 1 {
 		dst.bytes[i] = src1.bytes[i] ^ src2.bytes[i]
 	}
+	dst.sym = nil
+	if v != nil {
+		dst.setSymbolic(v[0].Add(v[1]))
+	}
 }
 
 func VUSHR_B(imm byte, src, dst *Vector128) {
@@ -222,15 +345,16 @@ func VUSHR_S(imm byte, src, dst *Vector128) {
 		imm = 31
 	}
 	v := src.Uint32s()
-	VLD1_4S([]uint32{v[0] >> imm, v[1] >> imm, v[2] >> imm, v[3] >> imm}, dst)
+	vld1_4s([]uint32{v[0] >> imm, v[1] >> imm, v[2] >> imm, v[3] >> imm}, dst)
 }
 
 func VUSHR_D(imm byte, src, dst *Vector128) {
+	counter.Op("VUSHR", dst, src)
 	if imm > 63 {
 		imm = 63
 	}
 	v := src.Uint64s()
-	VLD1_2D([]uint64{v[0] >> imm, v[1] >> imm}, dst)
+	vld1_2d([]uint64{v[0] >> imm, v[1] >> imm}, dst)
 }
 
 // Vector Shift Left and Insert
@@ -247,6 +371,7 @@ func VSLI_B(imm byte, src, dst *Vector128) {
 
 // VSLI $imm, src.2D, dst.2D
 func VSLI_D(imm byte, src, dst *Vector128) {
+	counter.Op("VSLI", dst, src, dst)
 	if imm > 63 {
 		imm = 63
 	}
@@ -259,6 +384,7 @@ func VSLI_D(imm byte, src, dst *Vector128) {
 
 // VSRI $imm, src.4S, dst.4S
 func VSRI_S(imm byte, src, dst *Vector128) {
+	counter.Op("VSRI", dst, src, dst)
 	if imm > 31 {
 		imm = 31
 	}
@@ -281,6 +407,7 @@ func VSHL_B(imm byte, src, dst *Vector128) {
 
 // VSHL $imm, src.4S, dst.4S
 func VSHL_S(imm byte, src, dst *Vector128) {
+	counter.Op("VSHL", dst, src)
 	if imm > 31 {
 		imm = 31
 	}
@@ -289,15 +416,29 @@ func VSHL_S(imm byte, src, dst *Vector128) {
 	}
 }
 
+func VSHL_D(imm byte, src, dst *Vector128) {
+	counter.Op("VSHL", dst, src)
+	if imm > 63 {
+		imm = 63
+	}
+	v := src.Uint64s()
+	vld1_2d([]uint64{v[0] << imm, v[1] << imm}, dst)
+}
+
 // Table vector Lookup.
 // https://developer.arm.com/architectures/instruction-sets/intrinsics/#q=vqtbl4q_u8
 // Architectures: A64
 func VTBL_B(src *Vector128, table []*Vector128, dst *Vector128) {
+	lookup("VTBL", src.bytes[:])
+	vtbl_b(src, table, dst)
+}
+
+// vtbl_b is VTBL_B without the reporting, for the instructions which report their own lookups.
+func vtbl_b(src *Vector128, table []*Vector128, dst *Vector128) {
 	if len(table) > 4 || len(table) < 1 {
 		panic("invalid table")
 	}
 	tmp := Vector128{}
-	VDUP_BYTE(0, &tmp)
 
 	switch len(table) {
 	case 1:
@@ -345,6 +486,12 @@ func VTBL_B(src *Vector128, table []*Vector128, dst *Vector128) {
 // https://developer.arm.com/architectures/instruction-sets/intrinsics/#q=vqtbx4q_u8
 // Architectures: A64
 func VTBX_B(src *Vector128, table []*Vector128, dst *Vector128) {
+	lookup("VTBX", src.bytes[:])
+	vtbx_b(src, table, dst)
+}
+
+// vtbx_b is VTBX_B without the reporting, for the instructions which report their own lookups.
+func vtbx_b(src *Vector128, table []*Vector128, dst *Vector128) {
 	if len(table) > 4 || len(table) < 1 {
 		panic("invalid table")
 	}
@@ -524,7 +671,7 @@ func VZIP1_S(Vm, Vn, dst *Vector128) {
 func VZIP1_D(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint64s()
 	b := Vm.Uint64s()
-	VLD1_2D([]uint64{a[0], b[0]}, dst)
+	vld1_2d([]uint64{a[0], b[0]}, dst)
 }
 
 // VZIP2 Vm.4S, Vn.4S, Vd.4S
@@ -541,7 +688,7 @@ func VZIP2_S(Vm, Vn, dst *Vector128) {
 func VZIP2_D(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint64s()
 	b := Vm.Uint64s()
-	VLD1_2D([]uint64{a[1], b[1]}, dst)
+	vld1_2d([]uint64{a[1], b[1]}, dst)
 }
 
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/TRN1--Transpose-vectors--primary--?lang=en
@@ -556,14 +703,14 @@ func VTRN1_H(Vm, Vn, dst *Vector128) {
 func VTRN1_S(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint32s()
 	b := Vm.Uint32s()
-	VLD1_4S([]uint32{a[0], b[0], a[2], b[2]}, dst)
+	vld1_4s([]uint32{a[0], b[0], a[2], b[2]}, dst)
 }
 
 // VTRN1 Vm.2D, Vn.2D, Vd.2D
 func VTRN1_D(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint64s()
 	b := Vm.Uint64s()
-	VLD1_2D([]uint64{a[0], b[0]}, dst)
+	vld1_2d([]uint64{a[0], b[0]}, dst)
 }
 
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/TRN1--Transpose-vectors--primary--?lang=en
@@ -578,14 +725,14 @@ func VTRN2_H(Vm, Vn, dst *Vector128) {
 func VTRN2_S(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint32s()
 	b := Vm.Uint32s()
-	VLD1_4S([]uint32{a[1], b[1], a[3], b[3]}, dst)
+	vld1_4s([]uint32{a[1], b[1], a[3], b[3]}, dst)
 }
 
 // VTRN2 Vm.2D, Vn.2D, Vd.2D
 func VTRN2_D(Vm, Vn, dst *Vector128) {
 	a := Vn.Uint64s()
 	b := Vm.Uint64s()
-	VLD1_2D([]uint64{a[1], b[1]}, dst)
+	vld1_2d([]uint64{a[1], b[1]}, dst)
 }
 
 // input: from high to low
@@ -700,14 +847,26 @@ func clmul(a, b uint64) (hi, lo uint64) {
 // Polynomial multiply long
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/PMULL--PMULL2--Polynomial-multiply-long-?lang=en
 func VPMULL(Vm, Vn, Vd *Vector128) {
+	counter.Op("VPMULL", Vd, Vm, Vn)
 	tmp1 := binary.LittleEndian.Uint64(Vm.bytes[:])
 	tmp2 := binary.LittleEndian.Uint64(Vn.bytes[:])
+	var sym gf2.Vec
+	switch {
+	case Vm.sym != nil && Vn.sym != nil:
+		panic("arm64: VPMULL of two symbolic operands is not linear")
+	case Vm.sym != nil:
+		sym = Vm.Symbolic()[:64].Clmul(tmp2)
+	case Vn.sym != nil:
+		sym = Vn.Symbolic()[:64].Clmul(tmp1)
+	}
 	hi, lo := clmul(tmp1, tmp2)
 	binary.LittleEndian.PutUint64(Vd.bytes[:], lo)
 	binary.LittleEndian.PutUint64(Vd.bytes[8:], hi)
+	Vd.setSymbolic(sym)
 }
 
 func VPMULL2(Vm, Vn, Vd *Vector128) {
+	counter.Op("VPMULL2", Vd, Vm, Vn)
 	tmp1 := binary.LittleEndian.Uint64(Vm.bytes[8:])
 	tmp2 := binary.LittleEndian.Uint64(Vn.bytes[8:])
 	hi, lo := clmul(tmp1, tmp2)
@@ -846,5 +1005,5 @@ func VADDP_H(Vm, Vn, Vd *Vector128) {
 func VADDP_S(Vm, Vn, Vd *Vector128) {
 	a := Vn.Uint32s()
 	b := Vm.Uint32s()
-	VLD1_4S([]uint32{a[0] + a[1], a[2] + a[3], b[0] + b[1], b[2] + b[3]}, Vd)
+	vld1_4s([]uint32{a[0] + a[1], a[2] + a[3], b[0] + b[1], b[2] + b[3]}, Vd)
 }
@@ -2,6 +2,8 @@ package arm64
 
 import "github.com/emmansun/simd/alg/aes"
 
+//go:generate go run ../cmd/sboxgen -pkg arm64 -o sbox_params.go
+
 func AESE(rk, state *Vector128) {
 	var (
 		V0 = &Vector128{}
@@ -25,8 +27,9 @@ func AESE(rk, state *Vector128) {
 	VLD1_2D([]uint64{0x030E09040F0A0500, 0x0B06010C07020D08}, shiftRow)
 	// State XOR RoundKey
 	VEOR(rk, state, state)
+	lookup("AESE", state.bytes[:])
 	// ShiftRows
-	VTBL_B(shiftRow, []*Vector128{state}, state)
+	vtbl_b(shiftRow, []*Vector128{state}, state)
 	// SubBytes
 	tmp := &Vector128{}
 	r4 := &Vector128{}
@@ -47,16 +50,41 @@ func AESE(rk, state *Vector128) {
 	VLD1_16B(aes.SBOX[208:], V13)
 	VLD1_16B(aes.SBOX[224:], V14)
 	VLD1_16B(aes.SBOX[240:], V15)
-	VTBL_B(state, []*Vector128{V0, V1, V2, V3}, tmp)
+	vtbl_b(state, []*Vector128{V0, V1, V2, V3}, tmp)
 	VSUB_B(r4, state, state)
-	VTBX_B(state, []*Vector128{V4, V5, V6, V7}, tmp)
+	vtbx_b(state, []*Vector128{V4, V5, V6, V7}, tmp)
 	VSUB_B(r4, state, state)
-	VTBX_B(state, []*Vector128{V8, V9, V10, V11}, tmp)
+	vtbx_b(state, []*Vector128{V8, V9, V10, V11}, tmp)
 	VSUB_B(r4, state, state)
-	VTBX_B(state, []*Vector128{V12, V13, V14, V15}, tmp)
+	vtbx_b(state, []*Vector128{V12, V13, V14, V15}, tmp)
 	copy(state.bytes[:], tmp.bytes[:])
 }
 
+// AESD performs AddRoundKey, InvShiftRows and InvSubBytes.
+func AESD(rk, state *Vector128) {
+	inverseShiftRow := &Vector128{}
+	VLD1_2D([]uint64{0x0B0E0104070A0D00, 0x0306090C0F020508}, inverseShiftRow)
+	// State XOR RoundKey
+	VEOR(rk, state, state)
+	lookup("AESD", state.bytes[:])
+	// InvShiftRows
+	vtbl_b(inverseShiftRow, []*Vector128{state}, state)
+	// InvSubBytes
+	aes.InvSubBytes(&state.bytes)
+}
+
+// AESMC performs MixColumns.
+func AESMC(src, dst *Vector128) {
+	VMOV(src, dst)
+	aes.MixColumns(&dst.bytes)
+}
+
+// AESIMC performs InvMixColumns.
+func AESIMC(src, dst *Vector128) {
+	VMOV(src, dst)
+	aes.InvMixColumns(&dst.bytes)
+}
+
 func SboxWithAESNI(m1l, m1h, m2l, m2h, x *Vector128) {
 	var (
 		nibble_mask     = &Vector128{}
@@ -3,8 +3,12 @@ package arm64
 import (
 	"testing"
 
+	"github.com/emmansun/simd/alg/camellia"
+	"github.com/emmansun/simd/alg/sbox"
 	"github.com/emmansun/simd/alg/sm4"
 	"github.com/emmansun/simd/alg/zuc"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
 )
 
 func testSboxWithAESNI(t *testing.T, idx int, m1l, m1h, m2l, m2h *Vector128, sbox *[256]byte) {
@@ -156,3 +160,116 @@ func TestZUCSBOXWithAESNI(t *testing.T) {
 		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
 	}
 }
+
+func TestGeneratedSBOXWithAESNIParams(t *testing.T) {
+	for i, c := range SM4SboxAESNIParams {
+		m1l := &Vector128{}
+		m1h := &Vector128{}
+		m2l := &Vector128{}
+		m2h := &Vector128{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &sm4.SBOX)
+	}
+	for i, c := range ZUCSboxAESNIParams {
+		m1l := &Vector128{}
+		m1h := &Vector128{}
+		m2l := &Vector128{}
+		m2h := &Vector128{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
+	}
+}
+
+// The AES-NI examples of the Intel white paper, AESENC is AESE with a zero key, AESMC and the xor of the round key.
+func TestAESInstructions(t *testing.T) {
+	state, rk, zero := &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_2D([]uint64{0x63746f725d53475d, 0x7b5b546573745665}, state)
+	VLD1_2D([]uint64{0x5b477565726f6e5d, 0x4869285368617929}, rk)
+	cases := []struct {
+		name   string
+		f      func(v *Vector128)
+		lo, hi uint64
+	}{
+		{"AESENC", func(v *Vector128) { AESE(zero, v); AESMC(v, v); VEOR(rk, v, v) }, 0x8b104b58ded7e595, 0xa8311c2f9fdba3c5},
+		{"AESENCLAST", func(v *Vector128) { AESE(zero, v); VEOR(rk, v, v) }, 0x177ec42553fdc611, 0xc7fb881e938c5964},
+		{"AESDEC", func(v *Vector128) { AESD(zero, v); AESIMC(v, v); VEOR(rk, v, v) }, 0xb58eb95eb730392a, 0x138ac342faea2787},
+		{"AESDECLAST", func(v *Vector128) { AESD(zero, v); VEOR(rk, v, v) }, 0xd410637b72a593d0, 0xc5a391ef6b317f95},
+		{"AESIMC", func(v *Vector128) { AESIMC(v, v) }, 0x2b18330a81c3b3e5, 0x627a6f6644b109c8},
+	}
+	for _, c := range cases {
+		v := *state
+		c.f(&v)
+		if got := v.Uint64s(); got[0] != c.lo || got[1] != c.hi {
+			t.Errorf("%s got %x, want [%x %x]", c.name, got, c.lo, c.hi)
+		}
+	}
+	// AESMC is the inverse of AESIMC
+	v := &Vector128{}
+	AESMC(state, v)
+	AESIMC(v, v)
+	if *v != *state {
+		t.Errorf("AESIMC(AESMC(x)) = %x, want %x", v.Uint64s(), state.Uint64s())
+	}
+}
+
+// compiledSboxes are the S-boxes which alg/sbox compiles to the AESNI and GFNI constants.
+var compiledSboxes = []struct {
+	name string
+	sbox *[256]byte
+}{
+	{"SM4", &sm4.SBOX},
+	{"ZUC S1", &zuc.SBOX},
+	{"Camellia s1", &camellia.SBOX1},
+}
+
+func TestCompiledSBOXWithAESNI(t *testing.T) {
+	for i, c := range compiledSboxes {
+		tables, err := sbox.Compile(c.sbox)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		m1l, m1h, m2l, m2h := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+		VLD1_16B(tables.M1L[:], m1l)
+		VLD1_16B(tables.M1H[:], m1h)
+		VLD1_16B(tables.M2L[:], m2l)
+		VLD1_16B(tables.M2H[:], m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, c.sbox)
+		// the tables are the ones GenLookupTable builds
+		l, h := &Vector128{}, &Vector128{}
+		GenLookupTable(tables.AESNI.M1, tables.AESNI.C1, l, h)
+		if *l != *m1l || *h != *m1h {
+			t.Errorf("%s: M1L, M1H = %x, %x; GenLookupTable %x, %x", c.name, m1l.Bytes(), m1h.Bytes(), l.Bytes(), h.Bytes())
+		}
+		GenLookupTable(tables.AESNI.M2, tables.AESNI.C2, l, h)
+		if *l != *m2l || *h != *m2h {
+			t.Errorf("%s: M2L, M2H = %x, %x; GenLookupTable %x, %x", c.name, m2l.Bytes(), m2h.Bytes(), l.Bytes(), h.Bytes())
+		}
+	}
+}
+
+func TestLookupTracer(t *testing.T) {
+	run := func(f func(x *Vector128)) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			x := &Vector128{}
+			VLD1_16B(secret, x)
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(x)
+		}
+	}
+	shiftRows := func(x *Vector128) {
+		idx := &Vector128{}
+		VLD1_2D([]uint64{0x0B0E0104070A0D00, 0x0306090C0F020508}, idx)
+		VTBL_B(idx, []*Vector128{x}, x)
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "byte shuffle", Run: run(shiftRows)},
+		{Name: "nibble lookup", Site: "VTBL", Run: run(func(x *Vector128) { VTBL_B(x, []*Vector128{{}}, &Vector128{}) })},
+		{Name: "nibble lookup extension", Site: "VTBX", Run: run(func(x *Vector128) { VTBX_B(x, []*Vector128{{}}, &Vector128{}) })},
+		{Name: "AESE", Site: "AESE", Run: run(func(x *Vector128) { AESE(&Vector128{}, x) })},
+		{Name: "AESD", Site: "AESD", Run: run(func(x *Vector128) { AESD(&Vector128{}, x) })},
+		{Name: "S-box", Site: "VTBL", Run: run(func(x *Vector128) { SboxWithAESNI(&Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}, x) })},
+	})
+}
@@ -0,0 +1,236 @@
+package arm64
+
+import "github.com/emmansun/simd/alg/sha2"
+
+// sha256hash runs four rounds of SHA-256 on X = [a, b, c, d] and Y = [e, f, g, h] (lane 0 first) with the words of W.
+func sha256hash(X, Y, W [4]uint32) ([4]uint32, [4]uint32) {
+	for e := 0; e < 4; e++ {
+		t := Y[3] + sha2.Sigma1(Y[0]) + sha2.Ch(Y[0], Y[1], Y[2]) + W[e]
+		X[3] += t
+		Y[3] = t + sha2.Sigma0(X[0]) + sha2.Maj(X[0], X[1], X[2])
+		// ROL(Y:X, 32)
+		X, Y = [4]uint32{Y[3], X[0], X[1], X[2]}, [4]uint32{X[3], Y[0], Y[1], Y[2]}
+	}
+	return X, Y
+}
+
+func lanes(v *Vector128) (r [4]uint32) {
+	copy(r[:], v.Uint32s())
+	return
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA256H--SHA256-hash-update--part-1--
+// Vd: state [a, b, c, d], Vn: state [e, f, g, h], Vm: W[i..i+3] + K[i..i+3]
+// Vd is the new [a, b, c, d] after four rounds.
+func SHA256H(Vm, Vn, Vd *Vector128) {
+	X, _ := sha256hash(lanes(Vd), lanes(Vn), lanes(Vm))
+	vld1_4s(X[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA256H2--SHA256-hash-update--part-2--
+// Vd: state [e, f, g, h], Vn: state [a, b, c, d] before SHA256H, Vm: W[i..i+3] + K[i..i+3]
+// Vd is the new [e, f, g, h] after four rounds.
+func SHA256H2(Vm, Vn, Vd *Vector128) {
+	_, Y := sha256hash(lanes(Vn), lanes(Vd), lanes(Vm))
+	vld1_4s(Y[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA256SU0--SHA256-schedule-update-0-
+// Vd: W[i..i+3], Vn: W[i+4..i+7]
+// Vd[e] = W[i+e] + σ0(W[i+e+1])
+func SHA256SU0(Vn, Vd *Vector128) {
+	w := lanes(Vd)
+	t := [4]uint32{w[1], w[2], w[3], Vn.Uint32s()[0]}
+	for e := range w {
+		w[e] += sha2.SmallSigma0(t[e])
+	}
+	vld1_4s(w[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA256SU1--SHA256-schedule-update-1-
+// Vd: the result of SHA256SU0, Vn: W[i+8..i+11], Vm: W[i+12..i+15]
+// Vd = W[i+16..i+19], the high two words depend on the low two words.
+func SHA256SU1(Vm, Vn, Vd *Vector128) {
+	w, n, m := lanes(Vd), lanes(Vn), lanes(Vm)
+	t := [4]uint32{n[1], n[2], n[3], m[0]} // W[i+9..i+12]
+	w[0] += t[0] + sha2.SmallSigma1(m[2])
+	w[1] += t[1] + sha2.SmallSigma1(m[3])
+	w[2] += t[2] + sha2.SmallSigma1(w[0])
+	w[3] += t[3] + sha2.SmallSigma1(w[1])
+	vld1_4s(w[:], Vd)
+}
+
+// sha256block is the SHA-256 block function with the ARMv8 SHA2 instructions.
+func sha256block(state *[8]uint32, p []byte) {
+	var (
+		V0 = &Vector128{} // a, b, c, d
+		V1 = &Vector128{} // e, f, g, h
+		V2 = &Vector128{}
+		V3 = &Vector128{}
+		V4 = &Vector128{}
+		V5 = &Vector128{}
+		V6 = &Vector128{}
+		V7 = &Vector128{}
+		V8 = &Vector128{}
+		V9 = &Vector128{}
+		K  = &Vector128{}
+	)
+	VLD1_4S(state[:4], V0)
+	VLD1_4S(state[4:], V1)
+
+	for len(p) >= 64 {
+		VMOV(V0, V2)
+		VMOV(V1, V3)
+		VLD1_16B(p, V4)
+		VREV32_B(V4, V4)
+		VLD1_16B(p[16:], V5)
+		VREV32_B(V5, V5)
+		VLD1_16B(p[32:], V6)
+		VREV32_B(V6, V6)
+		VLD1_16B(p[48:], V7)
+		VREV32_B(V7, V7)
+
+		w := [4]*Vector128{V4, V5, V6, V7}
+		for i := 0; i < 64; i += 4 {
+			VLD1_4S(sha2.K256[i:], K)
+			VADD_S(w[0], K, V8)
+			VMOV(V0, V9)
+			SHA256H(V8, V1, V0)
+			SHA256H2(V8, V9, V1)
+			if i < 48 {
+				SHA256SU0(w[1], w[0])
+				SHA256SU1(w[3], w[2], w[0]) // W[i+16..i+19]
+			}
+			w = [4]*Vector128{w[1], w[2], w[3], w[0]}
+		}
+		VADD_S(V0, V2, V0)
+		VADD_S(V1, V3, V1)
+		p = p[64:]
+	}
+	VST1_4S(V0, state[:4])
+	VST1_4S(V1, state[4:])
+}
+
+func lanes2(v *Vector128) (r [2]uint64) {
+	copy(r[:], v.Uint64s())
+	return
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA512H--SHA512-Hash-update-part-1-
+// Vd: [g + W[i+1] + K[i+1], h + W[i] + K[i]], Vn: [f, g], Vm: [d, e] (lane 0 first)
+// Vd is [T1 of round i+1, T1 of round i], e of round i+1 is d + T1 of round i.
+func SHA512H(Vm, Vn, Vd *Vector128) {
+	x, y, w := lanes2(Vn), lanes2(Vm), lanes2(Vd)
+	var t [2]uint64
+	t[1] = (y[1]&x[0] ^ ^y[1]&x[1]) + sha2.Sigma1_512(y[1]) + w[1]
+	e := t[1] + y[0]
+	t[0] = (e&y[1] ^ ^e&x[0]) + sha2.Sigma1_512(e) + w[0]
+	VLD1_2D(t[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA512H2--SHA512-Hash-update-part-2-
+// Vd: the result of SHA512H, Vn: [c, d], Vm: [a, b] (lane 0 first)
+// Vd is the new [a, b] after two rounds.
+func SHA512H2(Vm, Vn, Vd *Vector128) {
+	x, y, w := lanes2(Vn), lanes2(Vm), lanes2(Vd)
+	var t [2]uint64
+	t[1] = (x[0]&y[1] ^ x[0]&y[0] ^ y[1]&y[0]) + sha2.Sigma0_512(y[0]) + w[1]
+	t[0] = (t[1]&y[0] ^ t[1]&y[1] ^ y[1]&y[0]) + sha2.Sigma0_512(t[1]) + w[0]
+	VLD1_2D(t[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA512SU0--SHA512-Schedule-Update-0-
+// Vd: W[i..i+1], Vn: W[i+2..i+3]
+// Vd[e] = W[i+e] + σ0(W[i+e+1])
+func SHA512SU0(Vn, Vd *Vector128) {
+	w := lanes2(Vd)
+	w[0] += sha2.SmallSigma0_512(w[1])
+	w[1] += sha2.SmallSigma0_512(Vn.Uint64s()[0])
+	VLD1_2D(w[:], Vd)
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SHA512SU1--SHA512-Schedule-Update-1-
+// Vd: the result of SHA512SU0, Vn: W[i+14..i+15], Vm: W[i+9..i+10]
+// Vd = W[i+16..i+17]
+func SHA512SU1(Vm, Vn, Vd *Vector128) {
+	w, x, y := lanes2(Vd), lanes2(Vn), lanes2(Vm)
+	w[0] += sha2.SmallSigma1_512(x[0]) + y[0]
+	w[1] += sha2.SmallSigma1_512(x[1]) + y[1]
+	VLD1_2D(w[:], Vd)
+}
+
+// sha512block is the SHA-512 block function with the ARMv8.2 SHA512 instructions, the state
+// is kept in the pairs [a, b], [c, d], [e, f] and [g, h], two rounds per step.
+func sha512block(state *[8]uint64, p []byte) {
+	var (
+		V0  = &Vector128{}
+		V1  = &Vector128{}
+		V2  = &Vector128{}
+		V3  = &Vector128{}
+		V4  = &Vector128{}
+		V16 = &Vector128{}
+		V17 = &Vector128{}
+		V18 = &Vector128{}
+		V19 = &Vector128{}
+		V20 = &Vector128{}
+		V21 = &Vector128{}
+		V22 = &Vector128{}
+		V23 = &Vector128{}
+		V5  = &Vector128{}
+		V6  = &Vector128{}
+		V7  = &Vector128{}
+		V8  = &Vector128{}
+		V9  = &Vector128{}
+		V10 = &Vector128{}
+		V11 = &Vector128{}
+		V12 = &Vector128{}
+	)
+	// W[i..i+15] in pairs
+	w := [8]*Vector128{V5, V6, V7, V8, V9, V10, V11, V12}
+	// ab, cd, ef, gh and a spare register for the new ef
+	s := [5]*Vector128{V0, V1, V2, V3, V4}
+	VLD1_2D(state[0:], s[0])
+	VLD1_2D(state[2:], s[1])
+	VLD1_2D(state[4:], s[2])
+	VLD1_2D(state[6:], s[3])
+
+	for len(p) >= 128 {
+		VMOV(s[0], V20)
+		VMOV(s[1], V21)
+		VMOV(s[2], V22)
+		VMOV(s[3], V23)
+		for i := range w {
+			VLD1_16B(p[16*i:], w[i])
+			VREV64_B(w[i], w[i])
+		}
+		for i := 0; i < 80; i += 2 {
+			ab, cd, ef, gh, ef1 := s[0], s[1], s[2], s[3], s[4]
+			VLD1_2D(sha2.K512[i:], V16)
+			VADD_D(w[0], V16, V16)
+			VEXT(8, V16, V16, V16) // W[i+1] + K[i+1], W[i] + K[i]
+			VEXT(8, ef, cd, V17)   // d, e
+			VEXT(8, gh, ef, V18)   // f, g
+			VADD_D(gh, V16, gh)
+			SHA512H(V17, V18, gh)
+			VADD_D(cd, gh, ef1) // the new e, f
+			SHA512H2(ab, cd, gh)
+			s = [5]*Vector128{gh, ab, ef1, ef, cd}
+
+			if i < 64 {
+				VEXT(8, w[5], w[4], V19) // W[i+9], W[i+10]
+				SHA512SU0(w[1], w[0])
+				SHA512SU1(V19, w[7], w[0]) // W[i+16], W[i+17]
+			}
+			w = [8]*Vector128{w[1], w[2], w[3], w[4], w[5], w[6], w[7], w[0]}
+		}
+		VADD_D(s[0], V20, s[0])
+		VADD_D(s[1], V21, s[1])
+		VADD_D(s[2], V22, s[2])
+		VADD_D(s[3], V23, s[3])
+		p = p[128:]
+	}
+	VST1_2D(s[0], state[0:])
+	VST1_2D(s[1], state[2:])
+	VST1_2D(s[2], state[4:])
+	VST1_2D(s[3], state[6:])
+}
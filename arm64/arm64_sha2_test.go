@@ -0,0 +1,132 @@
+package arm64
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sha2"
+)
+
+func TestSHA256H(t *testing.T) {
+	// four rounds of the first block of "abc" from the initial hash value, W[1..3] are zero
+	abcd, efgh, wk := &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_4S(sha2.IV256[:4], abcd)
+	VLD1_4S(sha2.IV256[4:], efgh)
+	VLD1_4S([]uint32{0x61626380 + sha2.K256[0], sha2.K256[1], sha2.K256[2], sha2.K256[3]}, wk)
+	save := &Vector128{}
+	VMOV(abcd, save)
+	SHA256H(wk, efgh, abcd)
+	SHA256H2(wk, save, efgh)
+	want := sha2.IV256
+	for i, w := range []uint32{0x61626380, 0, 0, 0} {
+		a, b, c, d, e, f, g, h := want[0], want[1], want[2], want[3], want[4], want[5], want[6], want[7]
+		t1 := h + sha2.Sigma1(e) + sha2.Ch(e, f, g) + sha2.K256[i] + w
+		t2 := sha2.Sigma0(a) + sha2.Maj(a, b, c)
+		want = [8]uint32{t1 + t2, a, b, c, d + t1, e, f, g}
+	}
+	var got [8]uint32
+	VST1_4S(abcd, got[:4])
+	VST1_4S(efgh, got[4:])
+	if got != want {
+		t.Errorf("SHA256H/SHA256H2 = %08x, want %08x", got, want)
+	}
+}
+
+func TestSHA256SU(t *testing.T) {
+	var w [20]uint32
+	for i := range w[:16] {
+		w[i] = uint32(i)*0x9e3779b9 + 0x12345678
+	}
+	for i := 16; i < 20; i++ {
+		w[i] = w[i-16] + sha2.SmallSigma0(w[i-15]) + w[i-7] + sha2.SmallSigma1(w[i-2])
+	}
+	v0, v1, v2, v3 := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_4S(w[0:], v0)
+	VLD1_4S(w[4:], v1)
+	VLD1_4S(w[8:], v2)
+	VLD1_4S(w[12:], v3)
+	SHA256SU0(v1, v0)
+	SHA256SU1(v3, v2, v0)
+	var got [4]uint32
+	VST1_4S(v0, got[:])
+	if got != [4]uint32(w[16:]) {
+		t.Errorf("SHA256SU1 = %08x, want %08x", got, w[16:])
+	}
+}
+
+func TestSHA256Block(t *testing.T) {
+	msg := make([]byte, 300)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n++ {
+		if got, want := sha2.Sum256(sha256block, msg[:n]), sha256.Sum256(msg[:n]); got != want {
+			t.Fatalf("sha256block(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestSHA512SU(t *testing.T) {
+	var w [18]uint64
+	for i := range w[:16] {
+		w[i] = uint64(i)*0x9e3779b97f4a7c15 + 0x0123456789abcdef
+	}
+	for i := 16; i < 18; i++ {
+		w[i] = w[i-16] + sha2.SmallSigma0_512(w[i-15]) + w[i-7] + sha2.SmallSigma1_512(w[i-2])
+	}
+	v0, v1, v7, v := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_2D(w[0:], v0)
+	VLD1_2D(w[2:], v1)
+	VLD1_2D(w[14:], v7)
+	VLD1_2D(w[9:], v)
+	SHA512SU0(v1, v0)
+	SHA512SU1(v, v7, v0)
+	if got := [2]uint64(v0.Uint64s()); got != [2]uint64(w[16:]) {
+		t.Errorf("SHA512SU1 = %016x, want %016x", got, w[16:])
+	}
+}
+
+func TestSHA512Block(t *testing.T) {
+	msg := make([]byte, 600)
+	for i := range msg {
+		msg[i] = byte(i*7 + 1)
+	}
+	for n := 0; n <= len(msg); n++ {
+		if got, want := sha2.Sum512(sha512block, msg[:n]), sha512.Sum512(msg[:n]); got != want {
+			t.Fatalf("sha512block(%d bytes) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestSHA512H(t *testing.T) {
+	// two rounds from the initial hash value with W[0] = 0x6162638000000000 and W[1] = 0
+	s := sha2.IV512
+	ab, cd, ef, gh := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_2D(s[0:], ab)
+	VLD1_2D(s[2:], cd)
+	VLD1_2D(s[4:], ef)
+	VLD1_2D(s[6:], gh)
+	wk, de, fg := &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_2D([]uint64{0x6162638000000000 + sha2.K512[0], sha2.K512[1]}, wk)
+	VEXT(8, wk, wk, wk)
+	VEXT(8, ef, cd, de)
+	VEXT(8, gh, ef, fg)
+	VADD_D(gh, wk, gh)
+	SHA512H(de, fg, gh)
+	VADD_D(cd, gh, ef)
+	SHA512H2(ab, cd, gh)
+
+	for i, w := range []uint64{0x6162638000000000, 0} {
+		a, b, c, d, e, f, g, h := s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7]
+		t1 := h + sha2.Sigma1_512(e) + (e&f ^ ^e&g) + sha2.K512[i] + w
+		t2 := sha2.Sigma0_512(a) + (a&b ^ a&c ^ b&c)
+		s = [8]uint64{t1 + t2, a, b, c, d + t1, e, f, g}
+	}
+	if got := [2]uint64(gh.Uint64s()); got != [2]uint64(s[0:2]) {
+		t.Errorf("SHA512H2 = %016x, want %016x", got, s[0:2])
+	}
+	if got := [2]uint64(ef.Uint64s()); got != [2]uint64(s[4:6]) {
+		t.Errorf("SHA512H = %016x, want %016x", got, s[4:6])
+	}
+}
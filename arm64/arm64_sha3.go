@@ -0,0 +1,42 @@
+package arm64
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/EOR3--Three-way-Exclusive-OR-
+// Vd = Vn ^ Vm ^ Va
+func EOR3(Va, Vm, Vn, Vd *Vector128) {
+	for i := 0; i < 16; i++ {
+		Vd.bytes[i] = Vn.bytes[i] ^ Vm.bytes[i] ^ Va.bytes[i]
+	}
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/RAX1--Rotate-and-Exclusive-OR-
+// Vd.2D = Vn.2D ^ (Vm.2D <<< 1)
+func RAX1(Vm, Vn, Vd *Vector128) {
+	for i := 0; i < 16; i += 8 {
+		m := binary.LittleEndian.Uint64(Vm.bytes[i:])
+		n := binary.LittleEndian.Uint64(Vn.bytes[i:])
+		binary.LittleEndian.PutUint64(Vd.bytes[i:], n^bits.RotateLeft64(m, 1))
+	}
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/XAR--Exclusive-OR-and-Rotate-
+// Vd.2D = (Vn.2D ^ Vm.2D) >>> imm
+func XAR(imm byte, Vm, Vn, Vd *Vector128) {
+	for i := 0; i < 16; i += 8 {
+		m := binary.LittleEndian.Uint64(Vm.bytes[i:])
+		n := binary.LittleEndian.Uint64(Vn.bytes[i:])
+		binary.LittleEndian.PutUint64(Vd.bytes[i:], bits.RotateLeft64(n^m, -int(imm&0x3f)))
+	}
+}
+
+// https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/BCAX--Bit-Clear-and-Exclusive-OR-
+// Vd = Vn ^ (Vm & ^Va)
+func BCAX(Va, Vm, Vn, Vd *Vector128) {
+	for i := 0; i < 16; i++ {
+		Vd.bytes[i] = Vn.bytes[i] ^ (Vm.bytes[i] &^ Va.bytes[i])
+	}
+}
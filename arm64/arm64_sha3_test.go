@@ -0,0 +1,26 @@
+package arm64
+
+import "testing"
+
+func TestSHA3Instructions(t *testing.T) {
+	a, m, n, d := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	VLD1_2D([]uint64{0xff00ff00ff00ff00, 0x0123456789abcdef}, a)
+	VLD1_2D([]uint64{0x0f0f0f0f0f0f0f0f, 0x8000000000000001}, m)
+	VLD1_2D([]uint64{0x3333333333333333, 0xfedcba9876543210}, n)
+	cases := []struct {
+		name   string
+		f      func()
+		hi, lo uint64
+	}{
+		{"EOR3", func() { EOR3(a, m, n, d) }, 0x7ffffffffffffffe, 0xc33cc33cc33cc33c},
+		{"RAX1", func() { RAX1(m, n, d) }, 0xfedcba9876543213, 0x2d2d2d2d2d2d2d2d},
+		{"XAR", func() { XAR(4, m, n, d) }, 0x17edcba987654321, 0xc3c3c3c3c3c3c3c3},
+		{"BCAX", func() { BCAX(a, m, n, d) }, 0x7edcba9876543210, 0x333c333c333c333c},
+	}
+	for _, c := range cases {
+		c.f()
+		if got := d.Uint64s(); got[1] != c.hi || got[0] != c.lo {
+			t.Errorf("%s = %016x %016x, want %016x %016x", c.name, got[1], got[0], c.hi, c.lo)
+		}
+	}
+}
@@ -9,15 +9,16 @@ import (
 
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SM3PARTW1--SM3PARTW1-?lang=en
 func SM3PARTW1(Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3PARTW1", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	tmp := &Vector128{}
-	VEOR(Vd, Vn, tmp)
+	veor(Vd, Vn, tmp)
 	for i := 4; i < 16; i += 4 {
 		v := binary.LittleEndian.Uint32(Vm.bytes[i:])
 		v = bits.RotateLeft32(v, 15)
 		binary.LittleEndian.PutUint32(result.bytes[i-4:], v)
 	}
-	VEOR(tmp, result, result)
+	veor(tmp, result, result)
 	for i := 0; i < 16; i += 4 {
 		if i == 12 {
 			v := binary.LittleEndian.Uint32(tmp.bytes[i:])
@@ -33,6 +34,7 @@ func SM3PARTW1(Vm, Vn, Vd *Vector128) {
 
 // https://developer.arm.com/documentation/ddi0602/2024-09/SIMD-FP-Instructions/SM3PARTW2--SM3PARTW2-?lang=en
 func SM3PARTW2(Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3PARTW2", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	tmp := &Vector128{}
 
@@ -41,8 +43,8 @@ func SM3PARTW2(Vm, Vn, Vd *Vector128) {
 		v = bits.RotateLeft32(v, 7)
 		binary.LittleEndian.PutUint32(tmp.bytes[i:], v)
 	}
-	VEOR(Vn, tmp, tmp)
-	VEOR(Vd, tmp, result)
+	veor(Vn, tmp, tmp)
+	veor(Vd, tmp, result)
 
 	tmp2 := bits.RotateLeft32(binary.LittleEndian.Uint32(tmp.bytes[:]), 15)
 	tmp2 = sm3.P1(tmp2)
@@ -56,6 +58,7 @@ func SM3PARTW2(Vm, Vn, Vd *Vector128) {
 // Vm.S[3]: sm3 state word E
 // Vn.S[3]: sm3 state word A
 func SM3SS1(Va, Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3SS1", Vd, Vn, Vm, Va)
 	result := &Vector128{}
 	for i := 0; i < 12; i++ {
 		result.bytes[i] = 0
@@ -74,6 +77,7 @@ func SM3SS1(Va, Vm, Vn, Vd *Vector128) {
 // Vn: SS1
 // Vd: state
 func SM3TT1A(imm byte, Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3TT1A", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	imm = imm & 0x3
 	WjPrime := binary.LittleEndian.Uint32(Vm.bytes[imm*4:])
@@ -97,6 +101,7 @@ func SM3TT1A(imm byte, Vm, Vn, Vd *Vector128) {
 // Vn: SS1
 // Vd: state
 func SM3TT1B(imm byte, Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3TT1B", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	imm = imm & 0x3
 	WjPrime := binary.LittleEndian.Uint32(Vm.bytes[imm*4:])
@@ -123,6 +128,7 @@ func SM3TT1B(imm byte, Vm, Vn, Vd *Vector128) {
 // Vn: SS1
 // Vd: state
 func SM3TT2A(imm byte, Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3TT2A", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	imm = imm & 0x3
 	Wj := binary.LittleEndian.Uint32(Vm.bytes[imm*4:])
@@ -142,6 +148,7 @@ func SM3TT2A(imm byte, Vm, Vn, Vd *Vector128) {
 // Vn: SS1
 // Vd: state
 func SM3TT2B(imm byte, Vm, Vn, Vd *Vector128) {
+	counter.Op("SM3TT2B", Vd, Vd, Vn, Vm)
 	result := &Vector128{}
 	imm = imm & 0x3
 	Wj := binary.LittleEndian.Uint32(Vm.bytes[imm*4:])
@@ -1,6 +1,9 @@
 package arm64
 
 import (
+	"bytes"
+	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/emmansun/simd/alg/sm3"
@@ -43,3 +46,34 @@ func TestSM3(t *testing.T) {
 		}
 	}
 }
+
+func TestSM3Hash(t *testing.T) {
+	cases := []struct {
+		in, out string
+	}{
+		{"", "1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b"},
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+	}
+	for _, c := range cases {
+		h := sm3.New(sm3block)
+		h.Write([]byte(c.in))
+		if got := hex.EncodeToString(h.Sum(nil)); got != c.out {
+			t.Errorf("SM3(%q) = %s; want %s", c.in, got, c.out)
+		}
+	}
+	// arbitrary lengths against the reference block function
+	msg := make([]byte, 300)
+	for i := range msg {
+		msg[i] = byte(i*13 + 5)
+	}
+	for n := 0; n <= len(msg); n++ {
+		h := sm3.New(sm3block)
+		h.Write(msg[:n/2])
+		h.Write(msg[n/2 : n])
+		want := sm3.Sum(msg[:n])
+		if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("length %d: got %x; want %x", n, got, want)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package arm64
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"testing"
+
+	"github.com/emmansun/simd/internal/gf2"
 )
 
 func TestPreTransposeMatrix(t *testing.T) {
@@ -190,3 +193,29 @@ func TestPMULHUW(t *testing.T) {
 		}
 	}
 }
+
+// TestSymbolic checks the symbolic bits of the instructions which carry them give their bytes.
+func TestSymbolic(t *testing.T) {
+	in, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0ff0e1d2c3b4a5968778695a4b3c2d1e0f")
+	var POLY Vector128
+	VLD1_2D([]uint64{0xc200000000000000, 0x0000000000000001}, &POLY)
+	cases := []struct {
+		name string
+		op   func(x, y *Vector128)
+	}{
+		{"VEOR", func(x, y *Vector128) { VEOR(x, y, x) }},
+		{"VMOV", func(x, y *Vector128) { VMOV(y, x) }},
+		{"VEXT", func(x, y *Vector128) { VEXT(5, x, y, x) }},
+		{"VPMULL Vm", func(x, y *Vector128) { VPMULL(x, &POLY, x) }},
+		{"VPMULL Vn", func(x, y *Vector128) { VPMULL(&POLY, y, x) }},
+	}
+	for _, c := range cases {
+		var x, y Vector128
+		SetSymbolic(&x, gf2.Var(0, 128), in[:16])
+		SetSymbolic(&y, gf2.Var(128, 128), in[16:])
+		c.op(&x, &y)
+		if got := x.Symbolic().Eval(in); !bytes.Equal(got, x.Bytes()) {
+			t.Errorf("%s: the symbolic bits give %x, want %x", c.name, got, x.Bytes())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// The CRC kernel folds 4 or 8 blocks in parallel, combines the accumulators into one block, folds
+// it over the CRC width and reduces it with Barrett, see alg/crc for the constants.
+
+package arm64
+
+import "github.com/emmansun/simd/alg/crc"
+
+type clmulARM64CRC struct {
+	c     *crc.FoldConstants
+	lanes int
+}
+
+// NewClmulARM64CRC returns the folding kernel of the reflected CRC of width 32 or 64 with the
+// reflected polynomial poly, e.g. crc.IEEE, crc.Castagnoli, crc.ISO or crc.ECMA, which folds
+// lanes (4 or 8) blocks per iteration.
+func NewClmulARM64CRC(width int, poly uint64, lanes int) *clmulARM64CRC {
+	if lanes != 4 && lanes != 8 {
+		panic("arm64: unsupported CRC fold lanes")
+	}
+	return &clmulARM64CRC{c: crc.NewFoldConstants(width, poly), lanes: lanes}
+}
+
+// Update returns the CRC of p updated from v, as hash/crc32.Update and hash/crc64.Update.
+func (k *clmulARM64CRC) Update(v uint64, p []byte) uint64 {
+	c := k.c
+	if len(p) < 16*k.lanes {
+		return crc.Update(c.Width, c.Poly, v, p)
+	}
+	mask := ^uint64(0) >> (64 - c.Width)
+	var (
+		X     = make([]Vector128, k.lanes)
+		B     = Vector128{}
+		T     = Vector128{}
+		Q     = Vector128{}
+		ZERO  = Vector128{}
+		KN    = Vector128{}
+		K1    = Vector128{}
+		KW    = Vector128{}
+		MU    = Vector128{}
+		POLYX = Vector128{}
+		INIT  = Vector128{}
+	)
+	if k.lanes == 4 {
+		VLD1_2D(c.Fold4[:], &KN)
+	} else {
+		VLD1_2D(c.Fold8[:], &KN)
+	}
+	VLD1_2D(c.Fold1[:], &K1)
+	VLD1_2D(c.FoldWidth[:], &KW)
+	VLD1_2D([]uint64{c.Mu, 0}, &MU)
+	VLD1_2D([]uint64{c.PolyX, 0}, &POLYX)
+	VLD1_2D([]uint64{^v & mask, 0}, &INIT)
+
+	for i := range X {
+		VLD1_16B(p[16*i:], &X[i])
+	}
+	VEOR(&X[0], &INIT, &X[0])
+	p = p[16*k.lanes:]
+
+	for len(p) >= 16*k.lanes {
+		for i := range X {
+			VLD1_16B(p[16*i:], &B)
+			foldBlock(&X[i], &B, &KN, &T)
+		}
+		p = p[16*k.lanes:]
+	}
+
+	// combine the accumulators
+	for i := 1; i < k.lanes; i++ {
+		foldBlock(&X[0], &X[i], &K1, &T)
+	}
+	for len(p) >= 16 {
+		VLD1_16B(p, &B)
+		foldBlock(&X[0], &B, &K1, &T)
+		p = p[16:]
+	}
+
+	// fold over the width, X = R128(Ah·x^w + Al)
+	VPMULL2(&X[0], &KW, &T)
+	VPMULL(&X[0], &KW, &X[0])
+	VEOR(&X[0], &T, &X[0])
+
+	// Barrett reduction, q = Ah·Mu / x^63
+	if c.Width == 32 {
+		VEXT(4, &ZERO, &X[0], &Q)
+	} else {
+		VMOV(&X[0], &Q)
+	}
+	VPMULL(&Q, &MU, &Q)
+	VPMULL(&Q, &POLYX, &T)
+	VEOR(&X[0], &T, &X[0])
+	VEXT(8, &Q, &ZERO, &Q)
+	VEOR(&X[0], &Q, &X[0])
+
+	v = X[0].Uint64s()[1]>>(64-c.Width) ^ mask
+	return crc.Update(c.Width, c.Poly, v, p)
+}
+
+// foldBlock folds X over the distance of K and adds B, T is clobbered.
+func foldBlock(X, B, K, T *Vector128) {
+	VPMULL2(X, K, T)
+	VPMULL(X, K, X)
+	VEOR(X, T, X)
+	VEOR(X, B, X)
+}
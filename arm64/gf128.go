@@ -0,0 +1,99 @@
+package arm64
+
+// GF128MulXLE multiplies B by x in the little endian convention (IEEE 1619 XTS).
+// There is no arithmetic shift right of the doublewords, the subtraction from zero broadcasts the carry bits.
+// T, ZERO are temporary registers.
+func GF128MulXLE(B, T, ZERO *Vector128) {
+	POLY := &Vector128{}
+	VLD1_2D([]uint64{0x87, 1}, POLY)
+	VEOR(ZERO, ZERO, ZERO)
+	VUSHR_D(63, B, T)  // carry bits of B.D[0] and B.D[1]
+	VEXT(8, T, T, T)   // swap them
+	VSUB_D(T, ZERO, T) // broadcast
+	VAND(POLY, T, T)   // T.D[0] = 0x87 if B.D[1] carries, T.D[1] = 1 if B.D[0] carries
+	VADD_D(B, B, B)    // B.D[i] <<= 1
+	VEOR(T, B, B)
+}
+
+// GF128MulXBE multiplies B by x in the big endian convention (CMAC, OCB).
+// T, ZERO are temporary registers.
+func GF128MulXBE(B, T, ZERO *Vector128) {
+	gf128ByteReverse(B)
+	GF128MulXLE(B, T, ZERO)
+	gf128ByteReverse(B)
+}
+
+// GF128MulXGB multiplies B by x in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value with the reduction 0xe1 << 120.
+// T, ZERO are temporary registers.
+func GF128MulXGB(B, T, ZERO *Vector128) {
+	POLY := &Vector128{}
+	ONE := &Vector128{}
+	VLD1_2D([]uint64{1 << 63, 0xe1 << 56}, POLY)
+	VLD1_2D([]uint64{1, 1}, ONE)
+	VEOR(ZERO, ZERO, ZERO)
+	gf128ByteReverse(B)
+	VAND(ONE, B, T)    // least significant bits of B.D[0] and B.D[1]
+	VEXT(8, T, T, T)   // swap them
+	VSUB_D(T, ZERO, T) // broadcast
+	VAND(POLY, T, T)   // T.D[0] = 1 << 63 if B.D[1] carries, T.D[1] = 0xe1 << 56 if B.D[0] carries
+	VUSHR_D(1, B, B)
+	VEOR(T, B, B)
+	gf128ByteReverse(B)
+}
+
+// checkMulXPow panics unless 1 <= k <= 8, the k bits shifted out of the block fit in a byte.
+func checkMulXPow(k int) {
+	if k < 1 || k > 8 {
+		panic("arm64: GF128MulXPow k out of range")
+	}
+}
+
+// GF128MulXPowLE multiplies B by x^k, 1 <= k <= 8, in the little endian convention (IEEE 1619 XTS).
+// T0, T1, ZERO are temporary registers.
+func GF128MulXPowLE(B, T0, T1, ZERO *Vector128, k int) {
+	checkMulXPow(k)
+	POLY := &Vector128{}
+	VLD1_2D([]uint64{0, 0x87}, POLY)
+	VEOR(ZERO, ZERO, ZERO)
+	VUSHR_D(byte(64-k), B, T0) // T0.D[0] = the carry of B.D[0], T0.D[1] = the bits shifted out
+	VSHL_D(byte(k), B, B)
+	VPMULL2(T0, POLY, T1) // T1 = T0.D[1]·0x87
+	VEOR(T1, B, B)
+	VEXT(8, T0, ZERO, T0) // T0 = [0, T0.D[0]]
+	VEOR(T0, B, B)
+}
+
+// GF128MulXPowBE multiplies B by x^k, 1 <= k <= 8, in the big endian convention (CMAC, OCB).
+// T0, T1, ZERO are temporary registers.
+func GF128MulXPowBE(B, T0, T1, ZERO *Vector128, k int) {
+	gf128ByteReverse(B)
+	GF128MulXPowLE(B, T0, T1, ZERO, k)
+	gf128ByteReverse(B)
+}
+
+// GF128MulXPowGB multiplies B by x^k, 1 <= k <= 8, in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value by k bits, the bits shifted out are reduced with 0xe1 << (121 - k).
+// T0, T1, ZERO are temporary registers.
+func GF128MulXPowGB(B, T0, T1, ZERO *Vector128, k int) {
+	checkMulXPow(k)
+	POLY := &Vector128{}
+	VLD1_2D([]uint64{0xe1 << (57 - k), 0}, POLY)
+	VEOR(ZERO, ZERO, ZERO)
+	gf128ByteReverse(B)
+	VSHL_D(byte(64-k), B, T0) // T0.D[0] = the bits shifted out, T0.D[1] = the carry of B.D[1], at the top
+	VUSHR_D(byte(k), B, B)
+	VEXT(8, ZERO, T0, T1) // T1 = [T0.D[1], 0]
+	VEOR(T1, B, B)
+	VUSHR_D(byte(64-k), T0, T0)
+	VPMULL(T0, POLY, T0)  // T0 = T0.D[0]·0xe1 << (57 - k)
+	VEXT(8, T0, ZERO, T0) // T0 = [0, T0.D[0]]
+	VEOR(T0, B, B)
+	gf128ByteReverse(B)
+}
+
+// gf128ByteReverse reverses the 16 bytes of B.
+func gf128ByteReverse(B *Vector128) {
+	VREV64_B(B, B)
+	VEXT(8, B, B, B)
+}
@@ -0,0 +1,41 @@
+package arm64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/gf128test"
+	"github.com/emmansun/simd/internal/modetest"
+)
+
+// mulX returns the simulated doubling f as a function of the block.
+func mulX(f func(B, T, ZERO *Vector128)) func(*[16]byte) {
+	return func(v *[16]byte) {
+		var B, T, ZERO Vector128
+		VLD1_16B(v[:], &B)
+		f(&B, &T, &ZERO)
+		VST1_16B(&B, v[:])
+	}
+}
+
+// mulXPow returns the simulated multiplication by x^k f as a function of the block.
+func mulXPow(f func(B, T0, T1, ZERO *Vector128, k int)) func(*[16]byte, int) {
+	return func(v *[16]byte, k int) {
+		var B, T0, T1, ZERO Vector128
+		VLD1_16B(v[:], &B)
+		f(&B, &T0, &T1, &ZERO, k)
+		VST1_16B(&B, v[:])
+	}
+}
+
+func TestGF128MulX(t *testing.T) {
+	gf128test.TestMulX(t, mulX(GF128MulXLE), mulX(GF128MulXBE), mulX(GF128MulXGB))
+}
+
+func TestGF128MulXPow(t *testing.T) {
+	gf128test.TestMulXPow(t, mulXPow(GF128MulXPowLE), mulXPow(GF128MulXPowBE), mulXPow(GF128MulXPowGB), 8)
+}
+
+// TestGF128MulXBEModes runs CMAC (RFC 4493) and OCB3 (RFC 7253) with the simulated AES and GF128MulXBE.
+func TestGF128MulXBEModes(t *testing.T) {
+	modetest.TestModes(t, NewAESCipher, mulX(GF128MulXBE))
+}
@@ -1,12 +1,45 @@
-// The theoretical proof behind it is still not very clear.
+// The kernel computes r(X)·(r(H)·z)·z^-128 mod the bit reflected polynomial, see internal/gf2 for
+// the derivation and ghash_proof_test.go which proves the reduction for all inputs.
+
 package arm64
 
 type clmulARM64Ghash struct {
-	bytesProductTable [256]byte
+	aggregation       int
+	bytesProductTable []byte // H^aggregation, ..., H^1 for Karatsuba, 2 * 16 bytes each
+	polyval           bool
+}
+
+// defaultAggregation is the number of blocks processed by a reduction.
+const defaultAggregation = 8
+
+// checkAggregation panics if n is not a supported aggregation depth.
+func checkAggregation(n int) {
+	switch n {
+	case 1, 2, 4, 8, 16:
+	default:
+		panic("arm64: unsupported GHASH aggregation depth")
+	}
 }
 
 func NewClmulARM64Ghash(h []byte) *clmulARM64Ghash {
-	g := &clmulARM64Ghash{}
+	return newClmulARM64Ghash(h, defaultAggregation, false)
+}
+
+// NewClmulARM64GhashWithAggregation returns the kernel which aggregates n (1, 2, 4, 8 or 16) blocks
+// per reduction, the precomputed table holds n powers of H.
+func NewClmulARM64GhashWithAggregation(h []byte, n int) *clmulARM64Ghash {
+	checkAggregation(n)
+	return newClmulARM64Ghash(h, n, false)
+}
+
+// NewClmulARM64Polyval returns the POLYVAL (RFC 8452) variant of the kernel,
+// it skips the byte swaps and the key doubling, see NewClmulAMD64Polyval.
+func NewClmulARM64Polyval(h []byte) *clmulARM64Ghash {
+	return newClmulARM64Ghash(h, defaultAggregation, true)
+}
+
+func newClmulARM64Ghash(h []byte, n int, polyval bool) *clmulARM64Ghash {
+	g := &clmulARM64Ghash{aggregation: n, bytesProductTable: make([]byte, n*2*16), polyval: polyval}
 	var (
 		B0   = &Vector128{}
 		B1   = &Vector128{}
@@ -20,29 +53,31 @@ func NewClmulARM64Ghash(h []byte) *clmulARM64Ghash {
 		ZERO = &Vector128{}
 	)
 	VLD1_16B(h, B0)
-	VREV64_B(B0, B0) // B0.D[0] = High part, B0.D[1] = Low part
+	g.byteSwap(B0) // B0.D[0] = High part, B0.D[1] = Low part
 	VEOR(ZERO, ZERO, ZERO)
 	VLD1_2D([]uint64{0xc200000000000000, 0x0000000000000001}, POLY)
 
-	// Multiply by 2 modulo P
-	I := int64(B0.Uint64s()[0])
-	I = I >> 63
-	VLD1_2D([]uint64{uint64(I), uint64(I)}, T1)
-	VAND(POLY, T1, T1)
-	VUSHR_D(63, B0, T2)
-	VEXT(8, ZERO, T2, T2)
-	VSLI_D(1, B0, T2)
-	VEOR(T1, T2, B0)
+	if !polyval {
+		// Multiply by 2 modulo P
+		I := int64(B0.Uint64s()[0])
+		I = I >> 63
+		VLD1_2D([]uint64{uint64(I), uint64(I)}, T1)
+		VAND(POLY, T1, T1)
+		VUSHR_D(63, B0, T2)
+		VEXT(8, ZERO, T2, T2)
+		VSLI_D(1, B0, T2)
+		VEOR(T1, T2, B0)
+	}
 
 	VEXT(8, B0, B0, B1) // B1.D[0] = B0.D[1], B1.D[1] = B0.D[0]
 	VEOR(B1, B0, B1)    // B1.D[0] = B0.D[1] ^ B0.D[0], B1.D[1] = B0.D[0] ^ B0.D[1]
-	VST1_16B(B0, g.bytesProductTable[14*16:])
-	VST1_16B(B1, g.bytesProductTable[15*16:])
+	VST1_16B(B0, g.bytesProductTable[(n-1)*2*16:])
+	VST1_16B(B1, g.bytesProductTable[((n-1)*2+1)*16:])
 
 	VMOV(B0, B2)
 	VMOV(B1, B3)
 
-	for i := 6; i >= 0; i-- {
+	for i := n - 2; i >= 0; i-- {
 		// Karatsuba multiplication
 		VPMULL(B0, B2, T1)  // T1 = ACC1 = B0.D[0] * B2.D[0]
 		VPMULL2(B0, B2, T0) // T0 = ACC0 = B0.D[1] * B2.D[1]
@@ -68,64 +103,49 @@ func (g *clmulARM64Ghash) Hash(T *[16]byte, data []byte) {
 		T2   = &Vector128{}
 		T3   = &Vector128{}
 		B0   = &Vector128{}
-		B1   = &Vector128{}
-		B2   = &Vector128{}
-		B3   = &Vector128{}
-		B4   = &Vector128{}
-		B5   = &Vector128{}
-		B6   = &Vector128{}
-		B7   = &Vector128{}
+		B    = make([]Vector128, g.aggregation)
 		POLY = &Vector128{}
 		ZERO = &Vector128{}
 	)
 	VEOR(ACC0, ACC0, ACC0)
 	VEOR(ZERO, ZERO, ZERO)
 	VLD1_2D([]uint64{0xc200000000000000, 0x0000000000000001}, POLY)
-	// handle 8 blocks at a time
-	for len(data) >= 128 {
+	// handle aggregation blocks at a time
+	for len(data) >= 16*g.aggregation {
 		// load precomputed values
 		VLD1_16B(g.bytesProductTable[16*0:], T1)
 		VLD1_16B(g.bytesProductTable[16*1:], T2)
-		// load 8 blocks
-		VLD1_16B(data, B0)
-		VLD1_16B(data[16:], B1)
-		VLD1_16B(data[32:], B2)
-		VLD1_16B(data[48:], B3)
-		VLD1_16B(data[64:], B4)
-		VLD1_16B(data[80:], B5)
-		VLD1_16B(data[96:], B6)
-		VLD1_16B(data[112:], B7)
+		// load blocks
+		for i := range B {
+			VLD1_16B(data[16*i:], &B[i])
+		}
 
 		// process first block
 		// prepare data for multiplication
-		VREV64_B(B0, B0)
-		VEOR(B0, ACC0, B0)
-		VEXT(8, B0, B0, T0)
-		VEOR(B0, T0, T0)
+		g.byteSwap(&B[0])
+		VEOR(&B[0], ACC0, &B[0])
+		VEXT(8, &B[0], &B[0], T0)
+		VEOR(&B[0], T0, T0)
 		// Karatsuba multiplication
-		VPMULL(B0, T1, ACC1)
-		VPMULL2(B0, T1, ACC0)
+		VPMULL(&B[0], T1, ACC1)
+		VPMULL2(&B[0], T1, ACC0)
 		VPMULL(T0, T2, ACCM)
 
-		g.mulRoundAAD(B1, T0, T1, T2, T3, ACC0, ACC1, ACCM, 1)
-		g.mulRoundAAD(B2, T0, T1, T2, T3, ACC0, ACC1, ACCM, 2)
-		g.mulRoundAAD(B3, T0, T1, T2, T3, ACC0, ACC1, ACCM, 3)
-		g.mulRoundAAD(B4, T0, T1, T2, T3, ACC0, ACC1, ACCM, 4)
-		g.mulRoundAAD(B5, T0, T1, T2, T3, ACC0, ACC1, ACCM, 5)
-		g.mulRoundAAD(B6, T0, T1, T2, T3, ACC0, ACC1, ACCM, 6)
-		g.mulRoundAAD(B7, T0, T1, T2, T3, ACC0, ACC1, ACCM, 7)
+		for i := 1; i < g.aggregation; i++ {
+			g.mulRoundAAD(&B[i], T0, T1, T2, T3, ACC0, ACC1, ACCM, i)
+		}
 
 		// delayed reduction
 		g.processClmulResult(ACC0, ACCM, ACC1, ZERO, T0)
 		g.fastReduction(ACC0, ACC1, ACC0, T0, POLY)
 		VEXT(8, ACC0, ACC0, ACC0) // ACC0.D[0] = ACC0.D[1], ACC0.D[1] = ACC0.D[0]
 
-		data = data[128:]
+		data = data[16*g.aggregation:]
 	}
 
 	// load precomputed values
-	VLD1_16B(g.bytesProductTable[16*14:], T1)
-	VLD1_16B(g.bytesProductTable[16*15:], T2)
+	VLD1_16B(g.bytesProductTable[16*(g.aggregation-1)*2:], T1)
+	VLD1_16B(g.bytesProductTable[16*((g.aggregation-1)*2+1):], T2)
 
 	// handle one block at a time
 	for len(data) >= 16 {
@@ -141,12 +161,22 @@ func (g *clmulARM64Ghash) Hash(T *[16]byte, data []byte) {
 		VLD1_16B(partialBlock[:], B0)
 		g.mulOneBlock(B0, ACC0, ACCM, ACC1, T0, T1, T2, POLY, ZERO)
 	}
-	VREV64_B(ACC0, ACC0)
+	g.byteSwap(ACC0)
 	VST1_16B(ACC0, T[:])
 }
 
+// byteSwap converts a block to the kernel representation [High part, Low part] and back.
+// GHASH blocks are big endian, POLYVAL blocks are little endian.
+func (g *clmulARM64Ghash) byteSwap(B *Vector128) {
+	if g.polyval {
+		VEXT(8, B, B, B)
+	} else {
+		VREV64_B(B, B)
+	}
+}
+
 func (g *clmulARM64Ghash) mulOneBlock(B0, ACC0, ACCM, ACC1, T0, T1, T2, POLY, ZERO *Vector128) {
-	VREV64_B(B0, B0)
+	g.byteSwap(B0)
 	VEOR(B0, ACC0, B0)
 	VEXT(8, B0, B0, T0)
 	VEOR(B0, T0, T0)
@@ -161,13 +191,13 @@ func (g *clmulARM64Ghash) mulOneBlock(B0, ACC0, ACCM, ACC1, T0, T1, T2, POLY, ZE
 
 }
 
-// Multiply X by 2H^(8-i) and accumulate the result in ACC0, ACC1, ACCM
+// Multiply X by 2H^(n-i) and accumulate the result in ACC0, ACC1, ACCM, n is the aggregation depth
 // Y = [ACC0, ACCM, ACC1]
-// Y = X * (2H)^(8-i) + Y
+// Y = X * (2H)^(n-i) + Y
 // T0, T1, T2, T3 are temporary registers
 func (g *clmulARM64Ghash) mulRoundAAD(X, T0, T1, T2, T3, ACC0, ACC1, ACCM *Vector128, i int) {
 	// prepare data for multiplication
-	VREV64_B(X, X)
+	g.byteSwap(X)
 	VEXT(8, X, X, T0)
 	VEOR(X, T0, T0)
 	// load precomputed values
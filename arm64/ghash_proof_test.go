@@ -0,0 +1,74 @@
+package arm64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/gf2"
+)
+
+// The registers are little-endian, the bit vector of a register is its bytes.
+// The blocks and keys are in the kernel representation [High part, Low part],
+// swapDwords converts them from and to the bit vectors.
+
+func swapDwords(b []byte) []byte {
+	return append(append([]byte{}, b[8:16]...), b[:8]...)
+}
+
+// TestGHashProcessClmulResultProof proves processClmulResult combines the Karatsuba terms for all inputs.
+func TestGHashProcessClmulResultProof(t *testing.T) {
+	g := NewClmulARM64Ghash(make([]byte, 16))
+	f := func(x []byte) ([]byte, gf2.Vec) {
+		var ACC0, ACCM, ACC1, ZERO, T Vector128
+		SetSymbolic(&ACC0, gf2.Var(0, 128), x[:16])
+		SetSymbolic(&ACCM, gf2.Var(128, 128), x[16:32])
+		SetSymbolic(&ACC1, gf2.Var(256, 128), x[32:])
+		g.processClmulResult(&ACC0, &ACCM, &ACC1, &ZERO, &T)
+		return append(append([]byte{}, ACC0.Bytes()...), ACC1.Bytes()...), gf2.Concat(ACC0.Symbolic(), ACC1.Symbolic())
+	}
+	if err := gf2.CompareSymbolic(384, f, gf2.Karatsuba); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashFastReductionProof proves fastReduction is the Montgomery reduction for all inputs,
+// the caller swaps the result to the kernel representation.
+func TestGHashFastReductionProof(t *testing.T) {
+	g := NewClmulARM64Ghash(make([]byte, 16))
+	f := func(x []byte) ([]byte, gf2.Vec) {
+		var ACC0, ACC1, T, POLY Vector128
+		VLD1_2D([]uint64{0xc200000000000000, 0x0000000000000001}, &POLY)
+		SetSymbolic(&ACC0, gf2.Var(0, 128), x[:16])
+		SetSymbolic(&ACC1, gf2.Var(128, 128), x[16:])
+		g.fastReduction(&ACC0, &ACC1, &ACC0, &T, &POLY)
+		return ACC0.Bytes(), ACC0.Symbolic()
+	}
+	if err := gf2.CompareSymbolic(256, f, gf2.MontgomeryReduce); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashTwistProof checks the precomputed key is the twisted key 2H on the basis vectors and random inputs.
+func TestGHashTwistProof(t *testing.T) {
+	f := func(h []byte) []byte {
+		g := NewClmulARM64GhashWithAggregation(h, 1)
+		return swapDwords(g.bytesProductTable[:16])
+	}
+	if err := gf2.CompareLinear(128, 128, f, gf2.Twist); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashMulProof checks the single block multiplication is the GHASH multiplication on the basis vectors and random inputs.
+func TestGHashMulProof(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	f := func(x, h []byte) []byte {
+		var T [16]byte
+		NewClmulARM64GhashWithAggregation(h, 1).Hash(&T, x)
+		return T[:]
+	}
+	if err := gf2.CompareBilinear(128, 128, 128, f, gf2.GHASHMul); err != nil {
+		t.Fatal(err)
+	}
+}
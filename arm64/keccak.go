@@ -0,0 +1,50 @@
+package arm64
+
+import "github.com/emmansun/simd/alg/keccak"
+
+// Keccak-f[1600] with the SHA3 instructions, two states interleaved: each register holds one lane
+// of both states, state 0 in the low doubleword. θ is EOR3 and RAX1, ρ and π are XAR (the rotation
+// and the xor of D fused), χ is BCAX.
+
+// keccakRound runs one round on the 25 lanes a, b holds the lanes after π.
+func keccakRound(a, b *[25]*Vector128, c, d *[5]*Vector128, rc *Vector128) {
+	for x := 0; x < 5; x++ {
+		EOR3(a[x+10], a[x+5], a[x], c[x])
+		EOR3(a[x+20], a[x+15], c[x], c[x])
+	}
+	for x := 0; x < 5; x++ {
+		RAX1(c[(x+1)%5], c[(x+4)%5], d[x]) // D[x] = C[x-1] ^ (C[x+1] <<< 1)
+	}
+	for i := range a {
+		XAR(byte(64-keccak.Rotc[i]), d[i%5], a[i], b[keccak.Pi(i)])
+	}
+	for y := 0; y < 25; y += 5 {
+		for x := 0; x < 5; x++ {
+			BCAX(b[y+(x+1)%5], b[y+(x+2)%5], b[y+x], a[y+x])
+		}
+	}
+	VEOR(a[0], rc, a[0])
+}
+
+// keccakF1600x2 applies Keccak-f[1600] to the states s0 and s1.
+func keccakF1600x2(s0, s1 *[25]uint64) {
+	var a, b [25]*Vector128
+	var c, d [5]*Vector128
+	for i := range a {
+		a[i], b[i] = &Vector128{}, &Vector128{}
+		VLD1_2D([]uint64{s0[i], s1[i]}, a[i])
+	}
+	for x := range c {
+		c[x], d[x] = &Vector128{}, &Vector128{}
+	}
+	rc := &Vector128{}
+	for _, k := range keccak.RC {
+		VLD1_2D([]uint64{k, k}, rc)
+		keccakRound(&a, &b, &c, &d, rc)
+	}
+	var t [2]uint64
+	for i := range a {
+		VST1_2D(a[i], t[:])
+		s0[i], s1[i] = t[0], t[1]
+	}
+}
@@ -0,0 +1,23 @@
+package arm64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/alg/keccak"
+)
+
+func TestKeccakF1600x2(t *testing.T) {
+	var s0, s1, want0, want1 [25]uint64
+	for i := range s1 {
+		s1[i] = uint64(i) * 0x9e3779b97f4a7c15
+	}
+	want0, want1 = s0, s1
+	for i := 0; i < 3; i++ {
+		keccakF1600x2(&s0, &s1)
+		keccak.F1600(&want0)
+		keccak.F1600(&want1)
+		if s0 != want0 || s1 != want1 {
+			t.Fatalf("keccakF1600x2 #%d = %x, %x, want %x, %x", i+1, s0, s1, want0, want1)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Code generated by "sboxgen -pkg arm64"; DO NOT EDIT.
+
+package arm64
+
+// AffineParams are the constants of S(x) = M2·f(M1·x+C1)+C2, see GenLookupTable.
+type AffineParams struct {
+	M1, M2 uint64
+	C1, C2 byte
+}
+
+// SM4SboxAESNIParams are the constants to compute SM4 S-box with AES SubBytes.
+var SM4SboxAESNIParams = []AffineParams{
+	{0xa7ac65de3de94796, 0xc101dd410ab464fa, 0x69, 0x61},
+	{0x34ac259e022dbc52, 0x4e87acc7b40a9acb, 0x65, 0x2f},
+	{0x4c287db91a22505d, 0x480e4c47651dbad3, 0x3e, 0x6c},
+	{0x242842865a99abe6, 0xce81fbc81d658b2d, 0x8e, 0xe9},
+	{0xddec4505ceae37d1, 0x336292532a5b1650, 0x86, 0x3c},
+	{0x8aec81c17591b3ee, 0x0b95eaa45b2a5619, 0xd6, 0x4d},
+	{0xd517b18efe321f4d, 0x6b0232fcc37428e8, 0xce, 0x81},
+	{0x06170a353a729b0d, 0x9c3a8cc474c361a8, 0x23, 0x3b},
+}
+
+// ZUCSboxAESNIParams are the constants to compute ZUC S-box with AES SubBytes.
+var ZUCSboxAESNIParams = []AffineParams{
+	{0xf33e408a76f65828, 0x2ef66ddb8e57fd81, 0x00, 0xab},
+	{0x2b3e78b290e2aa3c, 0x6e7093a30891430e, 0x00, 0xbc},
+	{0x95124e5a9e18acc6, 0x9636b3cc88265d01, 0x00, 0xd8},
+	{0xbf12a8bca6d25e0c, 0xdfb9827207e02587, 0x00, 0x58},
+	{0xdd06c8f01eae7c70, 0x0dedd9055ad8a502, 0x00, 0xfe},
+	{0x1106dce4d4485096, 0x3c1a99b2ad1ed43a, 0x00, 0x32},
+	{0x47f4c026028c6e52, 0x1584e79df5664595, 0x00, 0xec},
+	{0xa7f40aec16b4426a, 0xebbcaeeccda0f262, 0x00, 0xb7},
+}
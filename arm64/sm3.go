@@ -0,0 +1,90 @@
+package arm64
+
+import "github.com/emmansun/simd/alg/sm3"
+
+// SM3 without the SM3 extensions, with the vector message expansion of sm3.Round: each step
+// computes four words with VEXT and shifts, the word lane 0 is W[i].
+
+// vrol rotates each word of src left by n bits with VSHL and VSRI, dst must not be src.
+func vrol(n byte, src, dst *Vector128) {
+	VSHL_S(n, src, dst)
+	VSRI_S(32-n, src, dst)
+}
+
+// messageSchedule computes out = W[i+16..i+19] of w0 = W[i..i+3], ..., w3 = W[i+12..i+15], see sm3.Round.
+// VEXT with the zero register moves W[i+16] alone into lane 3 for the fix of W[i+19].
+func messageSchedule(w0, w1, w2, w3, zero, t0, t1, t2, out *Vector128) {
+	VEXT(12, w2, w1, t0)  // t0 = W[-9] = W10 W9 W8 W7
+	VEOR(t0, w0, t0)      // t0 = W[-9] ^ W[-16]
+	VEXT(4, zero, w3, t1) // t1 = W[-3] = 0 W15 W14 W13
+	vrol(15, t1, t2)
+	VEOR(t0, t2, t0) // t0 = W[-16] ^ W[-9] ^ (W[-3] <<< 15), lane 3 lacks W16 <<< 15
+
+	vrol(15, t0, t1)
+	vrol(23, t0, t2)
+	VEOR(t1, t2, t1)
+	VEOR(t1, t0, out) // out = P1(t0)
+
+	VEXT(12, w1, w0, t0) // t0 = W[-13] = W6 W5 W4 W3
+	vrol(7, t0, t1)
+	VEOR(out, t1, out)
+	VEXT(8, w3, w2, t0) // t0 = W[-6] = W13 W12 W11 W10
+	VEOR(out, t0, out)  // out = W19' W18 W17 W16
+
+	VEXT(4, out, zero, t0) // t0 = W16 0 0 0
+	vrol(15, t0, t1)
+	VEOR(out, t1, out)
+	vrol(30, t0, t1)
+	VEOR(out, t1, out)
+	vrol(6, t0, t1)
+	VEOR(out, t1, out) // out = W19 W18 W17 W16
+}
+
+// sm3blockNEON is the SM3 block function with the NEON message expansion and scalar rounds.
+func sm3blockNEON(state *[8]uint32, p []byte) {
+	var (
+		V0   = &Vector128{}
+		V1   = &Vector128{}
+		V2   = &Vector128{}
+		V3   = &Vector128{}
+		V4   = &Vector128{}
+		V5   = &Vector128{}
+		V6   = &Vector128{}
+		V7   = &Vector128{}
+		V8   = &Vector128{}
+		ZERO = &Vector128{}
+	)
+	var wt, wt1 [4]uint32 // W[i..i+3] and W'[i..i+3] of the step
+	VDUP_BYTE(0, ZERO)
+
+	for len(p) >= 64 {
+		s := *state
+		VLD1_16B(p, V0)
+		VREV32_B(V0, V0)
+		VLD1_16B(p[16:], V1)
+		VREV32_B(V1, V1)
+		VLD1_16B(p[32:], V2)
+		VREV32_B(V2, V2)
+		VLD1_16B(p[48:], V3)
+		VREV32_B(V3, V3)
+
+		w := [5]*Vector128{V0, V1, V2, V3, V4}
+		for i := 0; i < 64; i += 4 {
+			// W[i+16..i+19] is needed up to W[67]
+			if i < 52 {
+				messageSchedule(w[0], w[1], w[2], w[3], ZERO, V6, V7, V8, w[4])
+			}
+			VEOR(w[0], w[1], V5) // W'[i..i+3]
+			VST1_4S(w[0], wt[:])
+			VST1_4S(V5, wt1[:])
+			for j := 0; j < 4; j++ {
+				sm3.Round(i+j, &s, wt[j], wt1[j])
+			}
+			w = [5]*Vector128{w[1], w[2], w[3], w[4], w[0]}
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[64:]
+	}
+}
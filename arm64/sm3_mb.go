@@ -0,0 +1,137 @@
+package arm64
+
+import (
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/sm3"
+)
+
+// Multi-buffer SM3: lane k of each register holds message k, the four messages are compressed
+// with the scalar SM3 data flow on vertical registers. The message blocks and the states are
+// transposed with PRE_TRANSPOSE_S on input and output.
+
+// sm3P0 computes x ^ (x <<< 9) ^ (x <<< 17), dst must not be x.
+func sm3P0(x, t, dst *Vector128) {
+	vrol(9, x, t)
+	VEOR(t, x, t)
+	vrol(17, x, dst)
+	VEOR(dst, t, dst)
+}
+
+// sm3P1 computes x ^ (x <<< 15) ^ (x <<< 23), dst must not be x.
+func sm3P1(x, t, dst *Vector128) {
+	vrol(15, x, t)
+	VEOR(t, x, t)
+	vrol(23, x, dst)
+	VEOR(dst, t, dst)
+}
+
+// SM3MultiBlock4 is sm3block4 as a sm3.MultiBlockFunc, it panics unless len(state) and len(p) are 4.
+func SM3MultiBlock4(state [][8]uint32, p [][]byte) {
+	if len(state) != 4 || len(p) != 4 {
+		panic("arm64: SM3MultiBlock4 needs 4 lanes")
+	}
+	sm3block4((*[4][8]uint32)(state), (*[4][]byte)(p))
+}
+
+// sm3block4 compresses the blocks of four messages of the same length, state[k] is the state of message p[k].
+// It panics if the lengths differ.
+func sm3block4(state *[4][8]uint32, p *[4][]byte) {
+	for k := range p {
+		if len(p[k]) != len(p[0]) {
+			panic("arm64: sm3block4 messages of different lengths")
+		}
+	}
+	var (
+		s, save [8]Vector128
+		w       [68]Vector128 // the message schedule on the stack
+		T       = &Vector128{}
+		SS1     = &Vector128{}
+		SS2     = &Vector128{}
+		TT1     = &Vector128{}
+		TT2     = &Vector128{}
+		V0      = &Vector128{}
+		V1      = &Vector128{}
+		V2      = &Vector128{}
+	)
+	for half := 0; half < 2; half++ {
+		for k := range state {
+			VLD1_4S(state[k][4*half:], &s[4*half+k])
+		}
+		PRE_TRANSPOSE_S(&s[4*half], &s[4*half+1], &s[4*half+2], &s[4*half+3]) // s[i] = word i of the four states
+	}
+
+	for n := 0; n+sm3.BlockSize <= len(p[0]); n += sm3.BlockSize {
+		save = s
+		for q := 0; q < 4; q++ {
+			for k := range p {
+				VLD1_16B(p[k][n+16*q:], &w[4*q+k])
+				VREV32_B(&w[4*q+k], &w[4*q+k])
+			}
+			PRE_TRANSPOSE_S(&w[4*q], &w[4*q+1], &w[4*q+2], &w[4*q+3]) // w[j] = word j of the four blocks
+		}
+		for j := 16; j < 68; j++ {
+			VEOR(&w[j-16], &w[j-9], V0)
+			vrol(15, &w[j-3], V1)
+			VEOR(V0, V1, V0)
+			sm3P1(V0, V2, V1)
+			vrol(7, &w[j-13], V0)
+			VEOR(V1, V0, V1)
+			VEOR(V1, &w[j-6], &w[j])
+		}
+		for i := 0; i < 64; i++ {
+			a, b, c, d, e, f, g, h := &s[0], &s[1], &s[2], &s[3], &s[4], &s[5], &s[6], &s[7]
+			t := uint32(sm3.CONST0)
+			if i >= 16 {
+				t = sm3.CONST1
+			}
+			VDUP_S(bits.RotateLeft32(t, i), T)
+			vrol(12, a, SS2) // SS2 = a <<< 12
+			VADD_S(SS2, e, V0)
+			VADD_S(V0, T, V0)
+			vrol(7, V0, SS1)    // SS1 = ((a <<< 12) + e + T) <<< 7
+			VEOR(SS2, SS1, SS2) // SS2 = SS1 ^ (a <<< 12)
+			if i < 16 {
+				VEOR(a, b, TT1)
+				VEOR(TT1, c, TT1)
+				VEOR(e, f, TT2)
+				VEOR(TT2, g, TT2)
+			} else {
+				// FF = (a & b) | (c & (a | b)), GG = g ^ (e & (f ^ g))
+				VAND(a, b, V0)
+				VORR(a, b, V1)
+				VAND(V1, c, V1)
+				VORR(V0, V1, TT1)
+				VEOR(f, g, V0)
+				VAND(V0, e, V0)
+				VEOR(V0, g, TT2)
+			}
+			VADD_S(TT1, d, TT1)
+			VADD_S(TT1, SS2, TT1)
+			VEOR(&w[i], &w[i+4], V0)
+			VADD_S(TT1, V0, TT1) // TT1 = FF + d + SS2 + W'
+			VADD_S(TT2, h, TT2)
+			VADD_S(TT2, SS1, TT2)
+			VADD_S(TT2, &w[i], TT2) // TT2 = GG + h + SS1 + W
+			// d = c, c = b <<< 9, b = a, a = TT1, h = g, g = f <<< 19, f = e, e = P0(TT2)
+			VMOV(c, d)
+			vrol(9, b, c)
+			VMOV(a, b)
+			VMOV(TT1, a)
+			VMOV(g, h)
+			vrol(19, f, g)
+			VMOV(e, f)
+			sm3P0(TT2, V0, e)
+		}
+		for i := range s {
+			VEOR(&s[i], &save[i], &s[i])
+		}
+	}
+
+	for half := 0; half < 2; half++ {
+		PRE_TRANSPOSE_S(&s[4*half], &s[4*half+1], &s[4*half+2], &s[4*half+3])
+		for k := range state {
+			VST1_4S(&s[4*half+k], state[k][4*half:])
+		}
+	}
+}
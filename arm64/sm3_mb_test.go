@@ -0,0 +1,78 @@
+package arm64
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm3"
+)
+
+func TestSM3Block4(t *testing.T) {
+	var state, want [4][8]uint32
+	var p [4][]byte
+	for k := range p {
+		// different states and messages per lane
+		p[k] = make([]byte, 3*sm3.BlockSize)
+		for i := range p[k] {
+			p[k][i] = byte(i*13 + k*71 + 1)
+		}
+		state[k] = sm3.IV
+		state[k][2*k] ^= uint32(k) << 8
+		want[k] = state[k]
+		sm3block(&want[k], p[k])
+	}
+	sm3block4(&state, &p)
+	for k := range state {
+		if state[k] != want[k] {
+			t.Errorf("lane %d: got %08x; want %08x", k, state[k], want[k])
+		}
+	}
+}
+
+func TestSM3Block4Lengths(t *testing.T) {
+	var state [4][8]uint32
+	var p [4][]byte
+	for k := range p {
+		p[k] = make([]byte, sm3.BlockSize)
+	}
+	p[3] = p[3][:0]
+	defer func() {
+		if recover() == nil {
+			t.Errorf("messages of different lengths do not panic")
+		}
+	}()
+	sm3block4(&state, &p)
+}
+
+func TestSM3Block4Vectors(t *testing.T) {
+	// lane k hashes the padded "abc" of GB/T 32905
+	block := make([]byte, sm3.BlockSize)
+	copy(block, "abc\x80")
+	block[63] = 0x18
+	var state [4][8]uint32
+	var p [4][]byte
+	for k := range p {
+		state[k] = sm3.IV
+		p[k] = block
+	}
+	sm3block4(&state, &p)
+	want := [8]uint32{0x66c7f0f4, 0x62eeedd9, 0xd1f2d46b, 0xdc10e4e2, 0x4167c487, 0x5cf2f7a2, 0x297da02b, 0x8f4ba8e0}
+	for k := range state {
+		if state[k] != want {
+			t.Errorf("lane %d: got %08x; want %08x", k, state[k], want)
+		}
+	}
+}
+
+func TestKDFMultiBuffer4(t *testing.T) {
+	z := make([]byte, 150)
+	for i := range z {
+		z[i] = byte(i*7 + 3)
+	}
+	for _, zl := range []int{0, 20, 55, 64, 100, 150} {
+		want := sm3.KDF(sm3block, z[:zl], 300)
+		if got := sm3.KDFMultiBuffer(SM3MultiBlock4, 4, z[:zl], 300); !bytes.Equal(got, want) {
+			t.Errorf("z length %d: got %x; want %x", zl, got, want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package arm64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/sm3test"
+)
+
+var sm3Kernels = []sm3test.Kernel{
+	{Name: "sm3ni", Func: sm3block},
+	{Name: "neon", Func: sm3blockNEON, ScalarRounds: 64},
+}
+
+func TestSM3BlockNEON(t *testing.T) {
+	sm3test.TestBlockFunc(t, sm3blockNEON)
+}
+
+func TestSM3BlockCounts(t *testing.T) {
+	sm3test.TestCounts(t, sm3Kernels, SetCounter)
+}
+
+func BenchmarkSM3Block(b *testing.B) {
+	sm3test.BenchmarkBlockFuncs(b, sm3Kernels, SetCounter)
+}
@@ -42,3 +42,63 @@ func TestEIA16Bytes(t *testing.T) {
 		}
 	}
 }
+
+var test64Vectors = []struct {
+	dataHex string
+	keys    []uint32
+	want    uint64
+}{
+	{
+		"11111111111111111111111111111111",
+		[]uint32{0x3d9caf57, 0x8a89937c, 0x176b36fd, 0x11d75481, 0xfdfd3376, 0xf854d429, 0x44d97210, 0x59dbc2bb},
+		0xd42c65f566559766,
+	},
+	{
+		"983b41d47d780c9e1ad11d7eb70391b1",
+		[]uint32{0x3d9caf57, 0x8a89937c, 0x176b36fd, 0x11d75481, 0xfdfd3376, 0xf854d429, 0x44d97210, 0x59dbc2bb},
+		0x2e6a65f9d9cda19a,
+	},
+}
+
+func TestEIA256_64(t *testing.T) {
+	for _, tt := range test64Vectors {
+		data, _ := hex.DecodeString(tt.dataHex)
+		got := EIA256RoundTag8(data, tt.keys)
+		if got != tt.want {
+			t.Errorf("EIA256RoundTag8() = %x; want %x", got, tt.want)
+		}
+	}
+}
+
+var test128Vectors = []struct {
+	dataHex string
+	keys    []uint32
+	want1   uint64
+	want2   uint64
+}{
+	{
+		"11111111111111111111111111111111",
+		[]uint32{0x17c8fa3d, 0x4342534c, 0xca2c1aaf, 0xfe44033d, 0x8058b02, 0xcda8ecbf, 0xc26c7761, 0xf9fd0fc3},
+		0x8f8d87816971a2b4,
+		0x30d19f879dffca43,
+	},
+	{
+		"983b41d47d780c9e1ad11d7eb70391b1",
+		[]uint32{0x3d9caf57, 0x8a89937c, 0x176b36fd, 0x11d75481, 0xfdfd3376, 0xf854d429, 0x44d97210, 0x59dbc2bb},
+		0x2e6a65f9d9cda19a,
+		0xeafdf0e5fd66e534,
+	},
+}
+
+func TestEIA256_128(t *testing.T) {
+	for _, tt := range test128Vectors {
+		data, _ := hex.DecodeString(tt.dataHex)
+		got1, got2 := EIA256RoundTag16(data, tt.keys)
+		if got1 != tt.want1 {
+			t.Errorf("EIA256RoundTag16() = %x; want %x", got1, tt.want1)
+		}
+		if got2 != tt.want2 {
+			t.Errorf("EIA256RoundTag16() = %x; want %x", got2, tt.want2)
+		}
+	}
+}
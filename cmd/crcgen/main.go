@@ -0,0 +1,56 @@
+// Crcgen prints the folding constants of the carry-less multiplication CRC kernels for a reflected
+// CRC of width 32 or 64, see alg/crc for their definitions.
+//
+// Usage:
+//
+//	go run ./cmd/crcgen -width 64 -poly 0xc96c5795d7870f42 -name crc64ECMA
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+
+	"github.com/emmansun/simd/alg/crc"
+)
+
+func generate(w io.Writer, name string, width int, poly uint64) error {
+	c := crc.NewFoldConstants(width, poly)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s holds the folding constants of the reflected CRC%d polynomial 0x%x.\n", name, width, poly)
+	fmt.Fprintf(&buf, "var %s = crc.FoldConstants{\n", name)
+	fmt.Fprintf(&buf, "Width: %d,\n", c.Width)
+	fmt.Fprintf(&buf, "Poly: 0x%x,\n", c.Poly)
+	for _, k := range []struct {
+		name string
+		pair [2]uint64
+	}{{"Fold1", c.Fold1}, {"Fold4", c.Fold4}, {"Fold8", c.Fold8}, {"FoldWidth", c.FoldWidth}} {
+		fmt.Fprintf(&buf, "%s: [2]uint64{0x%016x, 0x%016x},\n", k.name, k.pair[0], k.pair[1])
+	}
+	fmt.Fprintf(&buf, "Mu: 0x%016x,\n", c.Mu)
+	fmt.Fprintf(&buf, "PolyX: 0x%016x,\n", c.PolyX)
+	fmt.Fprintf(&buf, "}\n")
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func main() {
+	width := flag.Int("width", 32, "CRC width, 32 or 64")
+	poly := flag.Uint64("poly", crc.IEEE, "reflected polynomial, e.g. 0xedb88320")
+	name := flag.String("name", "foldConstants", "name of the generated variable")
+	flag.Parse()
+	if *width != 32 && *width != 64 {
+		log.Fatalf("crcgen: unsupported width %d", *width)
+	}
+	if err := generate(os.Stdout, *name, *width, *poly); err != nil {
+		log.Fatal(err)
+	}
+}
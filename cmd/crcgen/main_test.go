@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/emmansun/simd/alg/crc"
+)
+
+func TestGenerate(t *testing.T) {
+	cases := []struct {
+		name  string
+		width int
+		poly  uint64
+		want  []string
+	}{
+		{"crc32IEEE", 32, crc.IEEE, []string{"var crc32IEEE = crc.FoldConstants{", "Width:     32,", "Mu:        0xb4e5b025f7011641,"}},
+		{"crc64ECMA", 64, crc.ECMA, []string{"var crc64ECMA = crc.FoldConstants{", "Width:     64,", "Mu:        0x9c3e466c172963d5,"}},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		if err := generate(&buf, c.name, c.width, c.poly); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		for _, want := range c.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("generated source misses %q:\n%s", want, out)
+			}
+		}
+	}
+}
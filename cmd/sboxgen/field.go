@@ -0,0 +1,174 @@
+package main
+
+import "github.com/emmansun/simd/internal/gf256"
+
+func fieldPow2(x byte, poly uint16) byte {
+	return gf256.Mul(x, x, poly)
+}
+
+func fieldPow4(x byte, poly uint16) byte {
+	return fieldPow2(fieldPow2(x, poly), poly)
+}
+
+func fieldPow16(x byte, poly uint16) byte {
+	return fieldPow4(fieldPow4(x, poly), poly)
+}
+
+// wzy holds the normal bases of the tower field GF(((2^2)^2)^2):
+// {W^2, W} of GF(2^2), {Z^4, Z} of GF(2^4) and {Y^16, Y} of GF(2^8).
+type wzy struct {
+	W, W2, Z, Z4, Y, Y16 byte
+}
+
+// allWZY finds all W, Z, Y in GF(2^8) such that
+// W^2+W+1=0, Z^2+Z+N=0 (N=W^2) and Y^2+Y+u=0 (u=N^2*Z).
+func allWZY(poly uint16) []wzy {
+	var result []wzy
+	for i := 0; i < 256; i++ {
+		w := byte(i)
+		if fieldPow2(w, poly)^w^1 != 0 {
+			continue
+		}
+		w2 := fieldPow2(w, poly)
+		n := w2
+		for j := 0; j < 256; j++ {
+			z := byte(j)
+			if fieldPow2(z, poly)^z^w2 != 0 {
+				continue
+			}
+			z4 := fieldPow4(z, poly)
+			u := gf256.Mul(fieldPow2(n, poly), z, poly)
+			for k := 0; k < 256; k++ {
+				y := byte(k)
+				if fieldPow2(y, poly)^y^u != 0 {
+					continue
+				}
+				result = append(result, wzy{w, w2, z, z4, y, fieldPow16(y, poly)})
+			}
+		}
+	}
+	return result
+}
+
+// genX returns the columns of the matrix which converts an element from
+// the tower field representation to the polynomial basis of the field.
+func genX(v wzy, poly uint16) [8]byte {
+	mul3 := func(a, b, c byte) byte {
+		return gf256.Mul(gf256.Mul(a, b, poly), c, poly)
+	}
+	return [8]byte{
+		mul3(v.W2, v.Z4, v.Y16),
+		mul3(v.W, v.Z4, v.Y16),
+		mul3(v.W2, v.Z, v.Y16),
+		mul3(v.W, v.Z, v.Y16),
+		mul3(v.W2, v.Z4, v.Y),
+		mul3(v.W, v.Z4, v.Y),
+		mul3(v.W2, v.Z, v.Y),
+		mul3(v.W, v.Z, v.Y),
+	}
+}
+
+// g4Mul is GF(2^2) multiply operator, normal basis is {W^2, W}
+func g4Mul(x, y byte) byte {
+	a := (x & 0x02) >> 1
+	b := x & 0x01
+	c := (y & 0x02) >> 1
+	d := y & 0x01
+	e := (a ^ b) & (c ^ d)
+	return (((a & c) ^ e) << 1) | ((b & d) ^ e)
+}
+
+// g4MulN is GF(2^2) multiply N, normal basis is {W^2, W}, N = W^2
+func g4MulN(x byte) byte {
+	a := (x & 0x02) >> 1
+	b := x & 0x01
+	return (b << 1) | (a ^ b)
+}
+
+// g4MulN2 is GF(2^2) multiply N^2, normal basis is {W^2, W}, N = W^2
+func g4MulN2(x byte) byte {
+	a := (x & 0x02) >> 1
+	b := x & 0x01
+	return ((a ^ b) << 1) | a
+}
+
+// g4Inv is GF(2^2) inverse operator
+func g4Inv(x byte) byte {
+	a := (x & 0x02) >> 1
+	b := x & 0x01
+	return (b << 1) | a
+}
+
+// g16Mul is GF(2^4) multiply operator, normal basis is {Z^4, Z}
+func g16Mul(x, y byte) byte {
+	a := (x & 0xc) >> 2
+	b := x & 0x03
+	c := (y & 0xc) >> 2
+	d := y & 0x03
+	e := g4MulN(g4Mul(a^b, c^d))
+	p := g4Mul(a, c) ^ e
+	q := g4Mul(b, d) ^ e
+	return (p << 2) | q
+}
+
+// g16SqMulU is GF(2^4) x^2 * u operator, u = N^2 Z, N = W^2
+func g16SqMulU(x byte) byte {
+	a := (x & 0xc) >> 2
+	b := x & 0x03
+	p := g4Inv(a ^ b)
+	q := g4MulN2(g4Inv(b))
+	return (p << 2) | q
+}
+
+// g16Inv is GF(2^4) inverse operator
+func g16Inv(x byte) byte {
+	a := (x & 0xc) >> 2
+	b := x & 0x03
+	c := g4MulN(g4Inv(a ^ b))
+	d := g4Mul(a, b)
+	e := g4Inv(c ^ d)
+	p := g4Mul(e, b)
+	q := g4Mul(e, a)
+	return (p << 2) | q
+}
+
+// g256Inv is GF(2^8) inverse operator in tower field representation
+func g256Inv(x byte) byte {
+	a := (x & 0xf0) >> 4
+	b := x & 0x0f
+	c := g16SqMulU(a ^ b)
+	d := g16Mul(a, b)
+	e := g16Inv(c ^ d)
+	p := g16Mul(e, b)
+	q := g16Mul(e, a)
+	return (p << 4) | q
+}
+
+// g256NewBasis returns x presentation under new basis b
+func g256NewBasis(x byte, b [8]byte) byte {
+	var y byte
+	for i := 0; i < 8; i++ {
+		if x&(1<<(7-i)) != 0 {
+			y ^= b[i]
+		}
+	}
+	return y
+}
+
+// fromCols returns the matrix of the columns cols, cols[j] is the image of 1<<(7-j).
+func fromCols(cols [8]byte) gf256.Matrix {
+	var m gf256.Matrix
+	for j := 0; j < 8; j++ {
+		m[7-j] = cols[j]
+	}
+	return m
+}
+
+// toCols is the inverse of fromCols.
+func toCols(m gf256.Matrix) [8]byte {
+	var cols [8]byte
+	for j := 0; j < 8; j++ {
+		cols[j] = m[7-j]
+	}
+	return cols
+}
@@ -0,0 +1,220 @@
+// Sboxgen searches the tower field isomorphisms between the AES field and the SM4/ZUC fields,
+// derives the affine constants used to compute SM4/ZUC S-box with AESNI or GFNI,
+// verifies them against the reference S-boxes and emits them as Go source.
+//
+// It is the Go port of the scripts in the python directory.
+//
+// Usage:
+//
+//	go run ./cmd/sboxgen -pkg sse -gfni -o sbox_params.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/emmansun/simd/alg/aes"
+	"github.com/emmansun/simd/alg/sm4"
+	"github.com/emmansun/simd/alg/zuc"
+	"github.com/emmansun/simd/internal/gf256"
+)
+
+type cipher struct {
+	name string
+	poly uint16
+	a    [8]byte // columns of the affine transform matrix
+	c    byte
+	// preAffine is true if the affine transform is also applied before the inversion, S(x) = A·inv(A·x+C)+C.
+	preAffine bool
+	sbox      *[256]byte
+}
+
+var (
+	aesCipher = cipher{
+		name: "AES",
+		poly: 0x11b,
+		a:    [8]byte{0b10001111, 0b11000111, 0b11100011, 0b11110001, 0b11111000, 0b01111100, 0b00111110, 0b00011111},
+		c:    0x63,
+		sbox: &aes.SBOX,
+	}
+	sm4Cipher = cipher{
+		name:      "SM4",
+		poly:      0x1f5,
+		a:         [8]byte{0b11100101, 0b11110010, 0b01111001, 0b10111100, 0b01011110, 0b00101111, 0b10010111, 0b11001011},
+		c:         0xd3,
+		preAffine: true,
+		sbox:      &sm4.SBOX,
+	}
+	zucCipher = cipher{
+		name: "ZUC",
+		poly: 0x18b,
+		a:    [8]byte{0b01110111, 0b10111011, 0b11011101, 0b11101110, 0b11001011, 0b01101101, 0b00111110, 0b10010111},
+		c:    0x55,
+		sbox: &zuc.SBOX,
+	}
+)
+
+// towerSbox computes the S-box of ci with the inversion in tower field representation,
+// x holds the columns of the basis change matrix.
+func towerSbox(ci *cipher, x [8]byte) [256]byte {
+	xinv, ok := fromCols(x).Inverse()
+	if !ok {
+		panic("sboxgen: singular basis change matrix")
+	}
+	xInvCols := toCols(xinv)
+	var sbox [256]byte
+	for i := 0; i < 256; i++ {
+		t := byte(i)
+		if ci.preAffine {
+			t = g256NewBasis(t, ci.a) ^ ci.c
+		}
+		t = g256NewBasis(t, xInvCols)
+		t = g256Inv(t)
+		t = g256NewBasis(t, x)
+		t = g256NewBasis(t, ci.a)
+		sbox[i] = t ^ ci.c
+	}
+	return sbox
+}
+
+// searchParams derives the affine constants for all tower field isomorphisms
+// between the AES field and the field of ci.
+func searchParams(ci *cipher, gfni bool) []gf256.AffineParams {
+	aesAInv, _ := fromCols(aesCipher.a).Inverse()
+	a := fromCols(ci.a)
+
+	var result []gf256.AffineParams
+	seen := make(map[gf256.AffineParams]bool)
+	for _, v1 := range allWZY(aesCipher.poly) {
+		xAES := fromCols(genX(v1, aesCipher.poly))
+		xAESInv, _ := xAES.Inverse()
+		for _, v2 := range allWZY(ci.poly) {
+			x := fromCols(genX(v2, ci.poly))
+			xInv, _ := x.Inverse()
+
+			m1 := xAES.Mul(xInv)
+			var c1 byte
+			if ci.preAffine {
+				c1 = m1.Apply(ci.c)
+				m1 = m1.Mul(a)
+			}
+			m2 := a.Mul(x).Mul(xAESInv)
+			c2 := ci.c
+			if !gfni {
+				m2 = m2.Mul(aesAInv)
+				c2 ^= m2.Apply(aesCipher.c)
+			}
+
+			p := gf256.AffineParams{M1: m1.Qword(), M2: m2.Qword(), C1: c1, C2: c2}
+			if !seen[p] {
+				seen[p] = true
+				result = append(result, p)
+			}
+		}
+	}
+	return result
+}
+
+func verifyTowerSbox(ci *cipher) error {
+	for i, v := range allWZY(ci.poly) {
+		if sbox := towerSbox(ci, genX(v, ci.poly)); sbox != *ci.sbox {
+			return fmt.Errorf("%s tower field S-box %d (%+v) mismatch", ci.name, i, v)
+		}
+	}
+	return nil
+}
+
+func verifyParams(ci *cipher, gfni bool, params []gf256.AffineParams) error {
+	for i, p := range params {
+		for x := 0; x < 256; x++ {
+			t := gf256.Affine(p.M1, byte(x), p.C1)
+			if gfni {
+				t = gf256.Inv(t, aesCipher.poly)
+			} else {
+				t = aes.SBOX[t]
+			}
+			t = gf256.Affine(p.M2, t, p.C2)
+			if t != ci.sbox[x] {
+				return fmt.Errorf("%s params %d %#v: S(%#02x) = %#02x; want %#02x", ci.name, i, p, x, t, ci.sbox[x])
+			}
+		}
+	}
+	return nil
+}
+
+func writeParams(w io.Writer, name, doc string, params []gf256.AffineParams) {
+	fmt.Fprintf(w, "\n// %s %s\n", name, doc)
+	fmt.Fprintf(w, "var %s = []AffineParams{\n", name)
+	for _, p := range params {
+		fmt.Fprintf(w, "\t{%#016x, %#016x, %#02x, %#02x},\n", p.M1, p.M2, p.C1, p.C2)
+	}
+	fmt.Fprintf(w, "}\n")
+}
+
+func generate(pkg string, gfni bool) ([]byte, error) {
+	for _, ci := range []*cipher{&aesCipher, &sm4Cipher, &zucCipher} {
+		if err := verifyTowerSbox(ci); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	args := []string{"-pkg", pkg}
+	if gfni {
+		args = append(args, "-gfni")
+	}
+	fmt.Fprintf(&buf, "// Code generated by \"sboxgen %s\"; DO NOT EDIT.\n\n", strings.Join(args, " "))
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "// AffineParams are the constants of S(x) = M2·f(M1·x+C1)+C2, see GenLookupTable.\n")
+	fmt.Fprintf(&buf, "type AffineParams struct {\n\tM1, M2 uint64\n\tC1, C2 byte\n}\n")
+
+	modes := []bool{false}
+	if gfni {
+		modes = append(modes, true)
+	}
+	for _, ci := range []*cipher{&sm4Cipher, &zucCipher} {
+		for _, mode := range modes {
+			params := searchParams(ci, mode)
+			if err := verifyParams(ci, mode, params); err != nil {
+				return nil, err
+			}
+			if mode {
+				writeParams(&buf, ci.name+"SboxGFNIParams", "are the constants to compute "+ci.name+" S-box with GF2P8AFFINEQB/GF2P8AFFINEINVQB.", params)
+			} else {
+				writeParams(&buf, ci.name+"SboxAESNIParams", "are the constants to compute "+ci.name+" S-box with AES SubBytes.", params)
+			}
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sboxgen: ")
+	pkg := flag.String("pkg", "", "package name of the generated source")
+	gfni := flag.Bool("gfni", false, "also generate GFNI constants")
+	output := flag.String("o", "", "output file name; default stdout")
+	flag.Parse()
+	if *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(*pkg, *gfni)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
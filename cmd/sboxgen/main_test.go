@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/emmansun/simd/internal/gf256"
+)
+
+func TestAllWZY(t *testing.T) {
+	for _, ci := range []*cipher{&aesCipher, &sm4Cipher, &zucCipher} {
+		if got := len(allWZY(ci.poly)); got != 8 {
+			t.Errorf("%s allWZY() = %v results; want 8", ci.name, got)
+		}
+		if err := verifyTowerSbox(ci); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestColumns(t *testing.T) {
+	a := fromCols(sm4Cipher.a)
+	if got := toCols(a); got != sm4Cipher.a {
+		t.Fatalf("toCols() = %x; want %x", got, sm4Cipher.a)
+	}
+	if got := a.Apply(0x80); got != sm4Cipher.a[0] {
+		t.Errorf("A·0x80 = %02x; want column 0 %02x", got, sm4Cipher.a[0])
+	}
+}
+
+func TestSearchParams(t *testing.T) {
+	cases := []struct {
+		ci   *cipher
+		gfni bool
+		want gf256.AffineParams
+	}{
+		{&sm4Cipher, false, gf256.AffineParams{M1: 0xa7ac65de3de94796, M2: 0xc101dd410ab464fa, C1: 0x69, C2: 0x61}},
+		{&sm4Cipher, true, gf256.AffineParams{M1: 0xa7ac65de3de94796, M2: 0x75f1228d6c1e85c9, C1: 0x69, C2: 0xd3}},
+		{&zucCipher, false, gf256.AffineParams{M1: 0xdd06c8f01eae7c70, M2: 0x0dedd9055ad8a502, C1: 0x00, C2: 0xfe}},
+		{&zucCipher, true, gf256.AffineParams{M1: 0xdd06c8f01eae7c70, M2: 0xb903e5360f14f0e3, C1: 0x00, C2: 0x55}},
+	}
+	for _, c := range cases {
+		params := searchParams(c.ci, c.gfni)
+		if err := verifyParams(c.ci, c.gfni, params); err != nil {
+			t.Error(err)
+		}
+		found := false
+		for _, p := range params {
+			if p == c.want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s gfni=%v: %#v not found", c.ci.name, c.gfni, c.want)
+		}
+	}
+}
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	cases := []struct {
+		pkg  string
+		gfni bool
+		file string
+	}{
+		{"sse", true, "../../amd64/sse/sbox_params.go"},
+		{"arm64", false, "../../arm64/sbox_params.go"},
+		{"ppc64", false, "../../ppc64/sbox_params.go"},
+	}
+	for _, c := range cases {
+		want, err := os.ReadFile(c.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := generate(c.pkg, c.gfni)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", c.file)
+		}
+	}
+}
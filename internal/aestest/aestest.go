@@ -0,0 +1,76 @@
+// Package aestest holds the AES test shared by the simulated ciphers of amd64, arm64, ppc64 and s390x.
+package aestest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+)
+
+// Vectors are the examples of FIPS-197 Appendix C.
+var Vectors = []struct {
+	Key, In, Out string
+}{
+	{"000102030405060708090a0b0c0d0e0f", "00112233445566778899aabbccddeeff", "69c4e0d86a7b0430d8cdb78070b4c55a"},
+	{"000102030405060708090a0b0c0d0e0f1011121314151617", "00112233445566778899aabbccddeeff", "dda97ca4864cdfe06eaf70a0ec0d7191"},
+	{"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "00112233445566778899aabbccddeeff", "8ea2b7ca516745bfeafc49904b496089"},
+}
+
+// TestCipher checks the cipher of newCipher against Vectors, against crypto/aes on a chain
+// of blocks for each key size, and that it rejects the other key sizes.
+func TestCipher(t *testing.T, newCipher func(key []byte) (cipher.Block, error)) {
+	for i, tt := range Vectors {
+		key, _ := hex.DecodeString(tt.Key)
+		in, _ := hex.DecodeString(tt.In)
+		out, _ := hex.DecodeString(tt.Out)
+		c, err := newCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := make([]byte, 16)
+		c.Encrypt(dst, in)
+		if !bytes.Equal(dst, out) {
+			t.Errorf("case %d: encrypt got %x, want %x", i, dst, out)
+		}
+		c.Decrypt(dst, out)
+		if !bytes.Equal(dst, in) {
+			t.Errorf("case %d: decrypt got %x, want %x", i, dst, in)
+		}
+	}
+
+	key := make([]byte, 32)
+	src := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i*37 + 11)
+	}
+	for _, n := range []int{16, 24, 32} {
+		c, err := newCipher(key[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := aes.NewCipher(key[:n])
+		got, expected := make([]byte, 16), make([]byte, 16)
+		for i := 0; i < 64; i++ {
+			c.Encrypt(got, src)
+			want.Encrypt(expected, src)
+			if !bytes.Equal(got, expected) {
+				t.Fatalf("AES-%d: encrypt(%x) = %x, want %x", n*8, src, got, expected)
+			}
+			c.Decrypt(got, src)
+			want.Decrypt(expected, src)
+			if !bytes.Equal(got, expected) {
+				t.Fatalf("AES-%d: decrypt(%x) = %x, want %x", n*8, src, got, expected)
+			}
+			copy(src, got)
+			src[i%16] ^= byte(i)
+		}
+	}
+
+	for _, n := range []int{0, 15, 17, 33} {
+		if _, err := newCipher(make([]byte, n)); err == nil {
+			t.Errorf("the cipher accepted a %d bytes key", n)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package crctest holds the CRC folding kernel test shared by amd64, arm64 and ppc64.
+package crctest
+
+import (
+	"hash/crc32"
+	"hash/crc64"
+	"testing"
+
+	"github.com/emmansun/simd/alg/crc"
+)
+
+// Kernel is a CRC folding kernel, Update continues the CRC v with p.
+type Kernel interface {
+	Update(v uint64, p []byte) uint64
+}
+
+// Reference returns the hash/crc32 or hash/crc64 update of the reflected polynomial poly.
+func Reference(width int, poly uint64) func(v uint64, p []byte) uint64 {
+	if width == 32 {
+		tab := crc32.MakeTable(uint32(poly))
+		return func(v uint64, p []byte) uint64 { return uint64(crc32.Update(uint32(v), tab, p)) }
+	}
+	tab := crc64.MakeTable(poly)
+	return func(v uint64, p []byte) uint64 { return crc64.Update(v, tab, p) }
+}
+
+// TestKernel compares the kernels of newKernel folding 4 and 8 blocks with Reference for the
+// CRC32 IEEE and Castagnoli and the CRC64 ISO and ECMA polynomials, at every 7th length up to 600 bytes.
+func TestKernel(t *testing.T, newKernel func(width int, poly uint64, lanes int) Kernel) {
+	msg := make([]byte, 600)
+	for i := range msg {
+		msg[i] = byte(i*31 + i>>8 + 5)
+	}
+	for _, tt := range []struct {
+		name  string
+		width int
+		poly  uint64
+	}{
+		{"IEEE", 32, crc.IEEE},
+		{"Castagnoli", 32, crc.Castagnoli},
+		{"ISO", 64, crc.ISO},
+		{"ECMA", 64, crc.ECMA},
+	} {
+		ref := Reference(tt.width, tt.poly)
+		for _, lanes := range []int{4, 8} {
+			k := newKernel(tt.width, tt.poly, lanes)
+			for n := 0; n <= len(msg); n += 7 {
+				for _, v := range []uint64{0, 0x12345678} {
+					if got, want := k.Update(v, msg[:n]), ref(v, msg[:n]); got != want {
+						t.Fatalf("%s lanes %d, %d bytes, init %x: got %x, want %x", tt.name, lanes, n, v, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkCRC32 updates the CRC32 IEEE of 1KiB with the 8 blocks kernel of newKernel.
+func BenchmarkCRC32(b *testing.B, newKernel func(width int, poly uint64, lanes int) Kernel) {
+	k := newKernel(32, crc.IEEE, 8)
+	buf := make([]byte, 1024)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		k.Update(0, buf)
+	}
+}
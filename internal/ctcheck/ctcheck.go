@@ -0,0 +1,120 @@
+// Package ctcheck is a test utility which flags secret dependent table lookups and branches.
+//
+// The code under test reports each table index and branch condition to a Tracer.
+// Check runs it with different secrets of the same public size and compares the traces,
+// constant-time code has the same trace for all secrets.
+//
+// The alg/ghash methods are instrumented, the constant-time method reports its multiplications as
+// operations so its trace is not empty. The simulators of amd64/sse, amd64/avx, amd64/avx2, arm64,
+// ppc64 and s390x report the indices of their table lookup instructions (PSHUFB, VTBL, VTBX, VPERM
+// and VPERMXOR) and the S-box inputs of their AES instructions to the Tracer given to their
+// SetTracer. A kernel which looks up with public indices, as the byte swaps of the CLMUL kernels,
+// has the same trace for all secrets, one which looks up secret bytes, as an S-box built on AES-NI,
+// is flagged. Such a lookup stays in registers and is constant time on hardware, the flag marks
+// that the kernel relies on the instruction for it.
+package ctcheck
+
+import "fmt"
+
+type kind int
+
+const (
+	kindLookup kind = iota
+	kindBranch
+	kindOp
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindLookup:
+		return "table lookup"
+	case kindBranch:
+		return "branch"
+	}
+	return "operation"
+}
+
+type event struct {
+	kind  kind
+	site  string
+	value int
+}
+
+// Tracer records the table lookups and branches, a nil Tracer records nothing.
+type Tracer struct {
+	events []event
+}
+
+// Lookup records an access of table at index.
+func (t *Tracer) Lookup(table string, index int) {
+	if t != nil {
+		t.events = append(t.events, event{kindLookup, table, index})
+	}
+}
+
+// Branch records the branch condition at site and returns it.
+func (t *Tracer) Branch(site string, cond bool) bool {
+	if t != nil {
+		v := 0
+		if cond {
+			v = 1
+		}
+		t.events = append(t.events, event{kindBranch, site, v})
+	}
+	return cond
+}
+
+// Op records a constant-time operation at site, so the trace of constant-time code is not empty.
+func (t *Tracer) Op(site string) {
+	if t != nil {
+		t.events = append(t.events, event{kindOp, site, 0})
+	}
+}
+
+// Len returns the number of the recorded events.
+func (t *Tracer) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.events)
+}
+
+// Leak describes the first difference between the traces of two secrets.
+type Leak struct {
+	Kind   string // "table lookup", "branch" or "operation"
+	Site   string // table, branch or operation name
+	Event  int    // index in the trace
+	Secret int    // index of the secret whose trace differs from the first one
+	Got    int
+	Want   int
+}
+
+func (l *Leak) Error() string {
+	return fmt.Sprintf("ctcheck: secret dependent %s %s at event %d of secret %d: %d, secret 0: %d",
+		l.Kind, l.Site, l.Event, l.Secret, l.Got, l.Want)
+}
+
+// Check runs f with each secret and returns a *Leak if any trace differs from the trace of the first secret.
+func Check(f func(t *Tracer, secret []byte), secrets ...[]byte) error {
+	var want []event
+	for i, secret := range secrets {
+		t := &Tracer{}
+		f(t, secret)
+		if i == 0 {
+			want = t.events
+			continue
+		}
+		got := t.events
+		for j := 0; j < len(got) || j < len(want); j++ {
+			switch {
+			case j >= len(want):
+				return &Leak{got[j].kind.String(), got[j].site, j, i, got[j].value, -1}
+			case j >= len(got):
+				return &Leak{want[j].kind.String(), want[j].site, j, i, -1, want[j].value}
+			case got[j] != want[j]:
+				return &Leak{got[j].kind.String(), got[j].site, j, i, got[j].value, want[j].value}
+			}
+		}
+	}
+	return nil
+}
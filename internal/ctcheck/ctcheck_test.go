@@ -0,0 +1,78 @@
+package ctcheck
+
+import (
+	"errors"
+	"testing"
+)
+
+var table [256]byte
+
+func leakyLookup(t *Tracer, secret []byte) {
+	for _, b := range secret {
+		t.Lookup("table", int(b))
+		_ = table[b]
+	}
+}
+
+func leakyBranch(t *Tracer, secret []byte) {
+	var r byte
+	for _, b := range secret {
+		if t.Branch("odd", b&1 == 1) {
+			r ^= b
+		}
+	}
+}
+
+// leakyOp multiplies once more for an odd third byte.
+func leakyOp(t *Tracer, secret []byte) {
+	for i := 0; i < int(secret[2]&1)+1; i++ {
+		t.Op("mul")
+	}
+}
+
+func constantTime(t *Tracer, secret []byte) {
+	var r byte
+	for i, b := range secret {
+		t.Lookup("table", i)
+		r ^= table[i] & -(b & 1)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	secrets := [][]byte{{0, 2, 4, 6}, {0, 2, 5, 6}}
+	tests := []struct {
+		name string
+		f    func(*Tracer, []byte)
+		want *Leak
+	}{
+		{"lookup", leakyLookup, &Leak{"table lookup", "table", 2, 1, 5, 4}},
+		{"branch", leakyBranch, &Leak{"branch", "odd", 2, 1, 1, 0}},
+		{"operation", leakyOp, &Leak{"operation", "mul", 1, 1, 0, -1}},
+		{"constant time", constantTime, nil},
+	}
+	for _, tt := range tests {
+		err := Check(tt.f, secrets...)
+		var leak *Leak
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: got %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &leak) || *leak != *tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tr *Tracer
+	tr.Lookup("table", 1)
+	tr.Op("mul")
+	if tr.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", tr.Len())
+	}
+	if !tr.Branch("b", true) || tr.Branch("b", false) {
+		t.Errorf("Branch does not return the condition")
+	}
+}
@@ -0,0 +1,47 @@
+// Package ctchecktest holds the test of the table lookups the simulators of amd64/sse, amd64/avx,
+// amd64/avx2, arm64, ppc64 and s390x report to a ctcheck.Tracer.
+package ctchecktest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+)
+
+// Case is an instruction sequence on a secret register. Run loads secret, installs tr as the
+// tracer of the simulator around the sequence and removes it. Site is the lookup the sequence
+// is flagged at, "" if its lookups take public indices only.
+type Case struct {
+	Name string
+	Site string
+	Run  func(tr *ctcheck.Tracer, secret []byte)
+}
+
+// TestLookups runs each case with four 16 bytes secrets which differ in every nibble, and checks
+// it reports lookups and is flagged at Site, or not flagged if Site is "".
+func TestLookups(t *testing.T, cases []Case) {
+	secrets := make([][]byte, 4)
+	for i := range secrets {
+		secrets[i] = make([]byte, 16)
+		for j := range secrets[i] {
+			secrets[i][j] = byte(i*0x43 + j*13)
+		}
+	}
+	for _, c := range cases {
+		events := 0
+		err := ctcheck.Check(func(tr *ctcheck.Tracer, secret []byte) {
+			c.Run(tr, secret)
+			events = tr.Len()
+		}, secrets...)
+		var leak *ctcheck.Leak
+		switch {
+		case events == 0:
+			t.Errorf("%s: empty trace, no lookups reported", c.Name)
+		case c.Site == "" && err != nil:
+			t.Errorf("%s: %v", c.Name, err)
+		case c.Site != "" && (!errors.As(err, &leak) || leak.Site != c.Site):
+			t.Errorf("%s: got %v, want a secret dependent lookup at %s", c.Name, err, c.Site)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package gf128test holds the GF(2^128) multiplication by x and x^k test shared by alg/gf128, amd64,
+// arm64, ppc64 and s390x, which checks the simulated kernels of an architecture against alg/gf128.
+package gf128test
+
+import (
+	"encoding/hex"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/emmansun/simd/alg/gf128"
+)
+
+// Vectors are known answers of the multiplication by x, In·x = Out in the convention of the mode.
+var Vectors = []struct {
+	Convention, In, Out string
+}{
+	// RFC 4493 section 2.3, L = AES-128(K, 0) and the CMAC subkeys K1 = L·x, K2 = K1·x
+	{"BE", "7df76b0c1ab899b33e42f047b91b546f", "fbeed618357133667c85e08f7236a8de"},
+	{"BE", "fbeed618357133667c85e08f7236a8de", "f7ddac306ae266ccf90bc11ee46d513b"},
+	// IEEE 1619 XTS tweak
+	{"LE", "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff", "67e3e5e7e9ebedeff1f3f5f7f9fbfdff"},
+	{"LE", "00000000000000800000000000000000", "00000000000000000100000000000000"},
+	// GB/T 17964 XTS tweak
+	{"GB", "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff", "9978f979fa7afb7bfc7cfd7dfe7eff7f"},
+	{"GB", "00000000000000010000000000000000", "00000000000000008000000000000000"},
+}
+
+// blocks returns random blocks with all the combinations of the boundary bits of the 64-bit halves.
+func blocks() [][16]byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	b := make([][16]byte, 256)
+	for i := range b {
+		for j := range b[i] {
+			b[i][j] = byte(r.Uint32())
+		}
+		for k, pos := range []int{0, 7, 8, 15} {
+			b[i][pos] = b[i][pos]&0x7e | byte(i>>(2*k)&1)<<7 | byte(i>>(2*k+1)&1)
+		}
+	}
+	return b
+}
+
+// TestMulX checks the multiplications by x of the little endian, big endian and GB conventions
+// against Vectors and alg/gf128.
+func TestMulX(t *testing.T, le, be, gb func(*[16]byte)) {
+	var cases = []struct {
+		name string
+		mulX func(*[16]byte)
+		ref  func(*[16]byte)
+	}{
+		{"LE", le, gf128.MulXLE},
+		{"BE", be, gf128.MulXBE},
+		{"GB", gb, gf128.MulXGB},
+	}
+	for _, c := range cases {
+		for i, v := range Vectors {
+			if v.Convention != c.name {
+				continue
+			}
+			var b [16]byte
+			hex.Decode(b[:], []byte(v.In))
+			c.mulX(&b)
+			if got := hex.EncodeToString(b[:]); got != v.Out {
+				t.Errorf("vector %d %s: got %v, want %v", i, c.name, got, v.Out)
+			}
+		}
+		for _, want := range blocks() {
+			got := want
+			c.mulX(&got)
+			c.ref(&want)
+			if got != want {
+				t.Fatalf("%s: got %x, want %x", c.name, got, want)
+			}
+		}
+	}
+}
+
+// TestMulXPow checks the multiplications by x^k, 1 <= k <= maxK, of the little endian, big endian
+// and GB conventions against alg/gf128.
+func TestMulXPow(t *testing.T, le, be, gb func(*[16]byte, int), maxK int) {
+	var cases = []struct {
+		name    string
+		mulXPow func(*[16]byte, int)
+		ref     func(*[16]byte, int)
+	}{
+		{"LE", le, gf128.MulXPowLE},
+		{"BE", be, gf128.MulXPowBE},
+		{"GB", gb, gf128.MulXPowGB},
+	}
+	for _, c := range cases {
+		for k := 1; k <= maxK; k++ {
+			for _, want := range blocks() {
+				got := want
+				c.mulXPow(&got, k)
+				c.ref(&want, k)
+				if got != want {
+					t.Fatalf("%s, k = %d: got %x, want %x", c.name, k, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package gf2
+
+// The CLMUL GHASH kernels work on bit reflected values: register bit k of a block is the coefficient
+// of x^(127-k) of the GHASH element. With r(A) = z^127·A(1/z) and Q = z^128·P(1/z), for C = A·B mod P
+//
+//	r(A)·r(B) = z^127·r(C) mod Q
+//
+// so r(C) = r(A)·(r(B)·z)·z^-128 mod Q. The kernels precompute the twisted key r(H)·z mod Q
+// (the "2H"), multiply it by the block with 128x128 bits carry-less multiplications (Karatsuba)
+// and reduce the 256 bits product by the Montgomery reduction D·z^-128 mod Q, which is also the
+// POLYVAL dot operation of RFC 8452.
+
+var (
+	// GHASHPoly is the GHASH modulus x^128 + x^7 + x^2 + x + 1.
+	GHASHPoly = Monomial(128).Add(Monomial(7)).Add(Monomial(2)).Add(Monomial(1)).Add(Monomial(0))
+	// PolyvalPoly is the POLYVAL modulus x^128 + x^127 + x^126 + x^121 + 1, the bit reflected GHASHPoly.
+	PolyvalPoly = Monomial(128).Add(Monomial(127)).Add(Monomial(126)).Add(Monomial(121)).Add(Monomial(0))
+	// montgomeryFactor is x^-128 mod PolyvalPoly.
+	montgomeryFactor = inverseX128()
+)
+
+func inverseX128() Poly {
+	// x·(x^127 + x^126 + x^125 + x^120) = PolyvalPoly + 1
+	inv := Monomial(127).Add(Monomial(126)).Add(Monomial(125)).Add(Monomial(120))
+	r := Monomial(0)
+	for i := 0; i < 128; i++ {
+		r = r.MulMod(inv, PolyvalPoly)
+	}
+	return r
+}
+
+// ghashPoly returns the polynomial of the 16 bytes GHASH block b, the most significant bit of b[0] is the coefficient of x^0.
+func ghashPoly(b []byte) Poly {
+	r := make([]byte, 16)
+	for i, v := range b {
+		for k := 0; k < 8; k++ {
+			r[i] |= (v >> (7 - k) & 1) << k
+		}
+	}
+	return PolyFromBits(r)
+}
+
+// ghashBlock is the inverse of ghashPoly.
+func ghashBlock(p Poly) []byte {
+	return ghashPoly(p.Bits(16)).Bits(16)
+}
+
+// GHASHMul returns the GHASH product x·h mod GHASHPoly of the 16 bytes blocks x and h.
+func GHASHMul(x, h []byte) []byte {
+	return ghashBlock(ghashPoly(x).MulMod(ghashPoly(h), GHASHPoly))
+}
+
+// Karatsuba returns the 256 bits product of the 48 bytes Karatsuba terms [L | M | H],
+// L = a0·b0, M = (a0+a1)·(b0+b1) and H = a1·b1 are 128 bits little-endian values,
+// the product is L + (L+M+H)·z^64 + H·z^128.
+func Karatsuba(t []byte) []byte {
+	l, m, h := PolyFromBits(t[:16]), PolyFromBits(t[16:32]), PolyFromBits(t[32:48])
+	return Schoolbook(append(append(t[:16:16], l.Add(m).Add(h).Bits(16)...), t[32:48]...))
+}
+
+// Schoolbook returns the 256 bits product of the 48 bytes terms [L | M | H],
+// L = a0·b0, M = a0·b1 + a1·b0 and H = a1·b1 are 128 bits little-endian values,
+// the product is L + M·z^64 + H·z^128. VPMSUMD and VGFMG compute M with one instruction.
+func Schoolbook(t []byte) []byte {
+	l, m, h := PolyFromBits(t[:16]), PolyFromBits(t[16:32]), PolyFromBits(t[32:48])
+	return l.Add(m.Shl(64)).Add(h.Shl(128)).Bits(32)
+}
+
+// MontgomeryReduce returns D·z^-128 mod PolyvalPoly of the 256 bits little-endian value D.
+func MontgomeryReduce(d []byte) []byte {
+	return PolyFromBits(d).MulMod(montgomeryFactor, PolyvalPoly).Bits(16)
+}
+
+// Twist returns the twisted key r(H)·z mod PolyvalPoly of the 16 bytes GHASH key h, little-endian.
+func Twist(h []byte) []byte {
+	return Reflect(h).Shl(1).Mod(PolyvalPoly).Bits(16)
+}
+
+// Reflect returns r(B) of the 16 bytes GHASH block b, register bit k is the coefficient of x^(127-k) of b.
+func Reflect(b []byte) Poly {
+	r := make([]byte, 16)
+	for i := range r {
+		r[i] = b[15-i]
+	}
+	return PolyFromBits(r)
+}
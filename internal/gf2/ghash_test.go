@@ -0,0 +1,80 @@
+package gf2
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestGHASHMul(t *testing.T) {
+	// GCM spec test case 2, GHASH(H, C) = C·H for the single ciphertext block
+	h, _ := hex.DecodeString("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	c, _ := hex.DecodeString("0388dace60b6a392f328c2b971b2fe78")
+	if got, want := hex.EncodeToString(GHASHMul(c, h)), "5e2ec746917062882c85b0685353deb7"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// TestReflectedMontgomery proves r(A·H mod P) = MontgomeryReduce(r(A)·Twist(H)) for all A and H,
+// the identity the CLMUL kernels rely on.
+func TestReflectedMontgomery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	kernel := func(a, h []byte) []byte {
+		d := Reflect(a).Mul(PolyFromBits(Twist(h))).Bits(32)
+		r := MontgomeryReduce(d)
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = r[15-i]
+		}
+		return b
+	}
+	if err := CompareBilinear(128, 128, 128, kernel, GHASHMul); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestKaratsuba proves Karatsuba(a0·b0, (a0+a1)·(b0+b1), a1·b1) = a·b and
+// Schoolbook(a0·b0, a0·b1+a1·b0, a1·b1) = a·b for all 128 bits a and b.
+func TestKaratsuba(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	split := func(x []byte) (Poly, Poly) {
+		p := PolyFromBits(x)
+		lo := make(Poly, 1)
+		if len(p) > 0 {
+			lo[0] = p[0]
+		}
+		hi := make(Poly, 1)
+		if len(p) > 1 {
+			hi[0] = p[1]
+		}
+		return lo.norm(), hi.norm()
+	}
+	karatsuba := func(a, b []byte) []byte {
+		a0, a1 := split(a)
+		b0, b1 := split(b)
+		t := make([]byte, 0, 48)
+		t = append(t, a0.Mul(b0).Bits(16)...)
+		t = append(t, a0.Add(a1).Mul(b0.Add(b1)).Bits(16)...)
+		t = append(t, a1.Mul(b1).Bits(16)...)
+		return Karatsuba(t)
+	}
+	schoolbook := func(a, b []byte) []byte {
+		a0, a1 := split(a)
+		b0, b1 := split(b)
+		t := make([]byte, 0, 48)
+		t = append(t, a0.Mul(b0).Bits(16)...)
+		t = append(t, a0.Mul(b1).Add(a1.Mul(b0)).Bits(16)...)
+		t = append(t, a1.Mul(b1).Bits(16)...)
+		return Schoolbook(t)
+	}
+	clmul := func(a, b []byte) []byte { return PolyFromBits(a).Mul(PolyFromBits(b)).Bits(32) }
+	if err := CompareBilinear(128, 128, 256, karatsuba, clmul); err != nil {
+		t.Fatal(err)
+	}
+	if err := CompareBilinear(128, 128, 256, schoolbook, clmul); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+package gf2
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// Matrix is a GF(2) linear map of In bits to Out bits, column j is the image of the basis vector e_j.
+// The bit vectors are little-endian, bit k is bit k%8 of byte k/8.
+type Matrix struct {
+	In, Out int
+	cols    []Poly
+}
+
+// samples is the number of random inputs Extract checks the linearity on.
+const samples = 16
+
+// Extract returns the matrix of the linear map f of in bits to out bits, in and out are multiples of 8.
+// The input of f must not be modified. It returns an error if f(0) is not zero or f differs from
+// the matrix on a random input, f is not linear then.
+func Extract(in, out int, f func(x []byte) []byte) (*Matrix, error) {
+	m := &Matrix{In: in, Out: out, cols: make([]Poly, in)}
+	x := make([]byte, in/8)
+	if y := f(x); len(y) != out/8 || !bytes.Equal(y, make([]byte, out/8)) {
+		return nil, fmt.Errorf("gf2: f(0) = %x is not zero", y)
+	}
+	for j := range m.cols {
+		x[j/8] = 1 << (j % 8)
+		m.cols[j] = PolyFromBits(f(x))
+		x[j/8] = 0
+	}
+	r := rand.New(rand.NewPCG(uint64(in), uint64(out)))
+	for i := 0; i < samples; i++ {
+		for k := range x {
+			x[k] = byte(r.Uint32())
+		}
+		if got, want := f(x), m.Apply(x); !bytes.Equal(got, want) {
+			return nil, fmt.Errorf("gf2: f(%x) = %x, the basis vectors give %x, f is not linear", x, got, want)
+		}
+	}
+	return m, nil
+}
+
+// Apply returns the image of x.
+func (m *Matrix) Apply(x []byte) []byte {
+	var y Poly
+	for j, c := range m.cols {
+		if x[j/8]>>(j%8)&1 == 1 {
+			y = y.Add(c)
+		}
+	}
+	return y.Bits(m.Out / 8)
+}
+
+// Row returns output bit i as a polynomial in the input bits, the coefficient of x^j is 1 if input bit j is a term of the sum.
+func (m *Matrix) Row(i int) Poly {
+	r := make(Poly, (m.In+63)/64)
+	for j, c := range m.cols {
+		if c.Coeff(i) == 1 {
+			r[j/64] |= 1 << (j % 64)
+		}
+	}
+	return r.norm()
+}
+
+// FormatRow returns output bit i in the form of "in[127] + in[3] + in[0]".
+func (m *Matrix) FormatRow(i int) string {
+	r := m.Row(i)
+	var terms []string
+	for j := r.Deg(); j >= 0; j-- {
+		if r.Coeff(j) == 1 {
+			terms = append(terms, "in["+strconv.Itoa(j)+"]")
+		}
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " + ")
+}
+
+// Compare returns an error which shows the first output bit of got different from want, nil if they are the same map.
+func Compare(got, want *Matrix) error {
+	if got.In != want.In || got.Out != want.Out {
+		return fmt.Errorf("gf2: got a %d to %d bits map, want %d to %d bits", got.In, got.Out, want.In, want.Out)
+	}
+	for i := 0; i < got.Out; i++ {
+		if !got.Row(i).Equal(want.Row(i)) {
+			return fmt.Errorf("gf2: out[%d] = %s, want %s", i, got.FormatRow(i), want.FormatRow(i))
+		}
+	}
+	return nil
+}
+
+// CompareLinear extracts the matrices of the linear maps got and want of in bits to out bits and compares them.
+func CompareLinear(in, out int, got, want func(x []byte) []byte) error {
+	g, err := Extract(in, out, got)
+	if err != nil {
+		return err
+	}
+	w, err := Extract(in, out, want)
+	if err != nil {
+		return err
+	}
+	return Compare(g, w)
+}
+
+// CompareBilinear compares the bilinear maps got and want of inA and inB bits to out bits.
+// A bilinear map is determined by the linear maps of a for each basis vector b = e_j,
+// got is also compared with want on random inputs first.
+func CompareBilinear(inA, inB, out int, got, want func(a, b []byte) []byte) error {
+	a, b := make([]byte, inA/8), make([]byte, inB/8)
+	r := rand.New(rand.NewPCG(uint64(inA), uint64(inB)))
+	for i := 0; i < samples; i++ {
+		for k := range a {
+			a[k] = byte(r.Uint32())
+		}
+		for k := range b {
+			b[k] = byte(r.Uint32())
+		}
+		if g, w := got(a, b), want(a, b); !bytes.Equal(g, w) {
+			return fmt.Errorf("gf2: f(%x, %x) = %x, want %x", a, b, g, w)
+		}
+	}
+	clear(b)
+	for j := 0; j < inB; j++ {
+		b[j/8] = 1 << (j % 8)
+		err := CompareLinear(inA, out,
+			func(a []byte) []byte { return got(a, b) },
+			func(a []byte) []byte { return want(a, b) })
+		if err != nil {
+			return fmt.Errorf("b = e_%d: %w", j, err)
+		}
+		b[j/8] = 0
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package gf2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	// rotate left by 1 bit of a byte
+	rotl := func(x []byte) []byte { return []byte{x[0]<<1 | x[0]>>7} }
+	m, err := Extract(8, 8, rotl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.FormatRow(0), "in[7]"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := m.Apply([]byte{0x81}), []byte{0x03}; got[0] != want[0] {
+		t.Errorf("got %x, want %x", got, want)
+	}
+
+	if _, err := Extract(8, 8, func(x []byte) []byte { return []byte{x[0] | 1} }); err == nil {
+		t.Errorf("f(0) != 0 is not detected")
+	}
+	if _, err := Extract(8, 8, func(x []byte) []byte { return []byte{x[0] & (x[0] >> 1)} }); err == nil {
+		t.Errorf("non linear map is not detected")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	xor := func(x []byte) []byte { return []byte{x[0] ^ x[1]} }
+	if err := CompareLinear(16, 8, xor, xor); err != nil {
+		t.Fatal(err)
+	}
+	err := CompareLinear(16, 8, xor, func(x []byte) []byte { return []byte{x[0] ^ x[1]&0xfe} })
+	if err == nil || !strings.Contains(err.Error(), "out[0] = in[8] + in[0], want in[0]") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestCompareBilinear(t *testing.T) {
+	and := func(a, b []byte) []byte { return []byte{a[0] & b[0]} }
+	if err := CompareBilinear(8, 8, 8, and, and); err != nil {
+		t.Fatal(err)
+	}
+	or := func(a, b []byte) []byte { return []byte{a[0] & (b[0] | 1)} }
+	if err := CompareBilinear(8, 8, 8, or, and); err == nil {
+		t.Errorf("non bilinear map is not detected")
+	}
+}
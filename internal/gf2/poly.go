@@ -0,0 +1,156 @@
+// Package gf2 is a small GF(2) toolkit to prove that the simulated GHASH kernels are correct for all inputs.
+//
+// The kernels only use GF(2) linear instructions (xor, shuffles, shifts and carry-less
+// multiplication), so each reduction step is a linear map of its input registers and the block
+// multiplication is a bilinear map of the block and the key. A linear map is exactly determined
+// by the images of the basis vectors, Extract computes its matrix, which gives every output bit
+// as a GF(2) polynomial (a sum) of the input bits, and Compare checks it against the matrix of
+// the polynomial arithmetic it must implement.
+//
+// Extract alone does not prove a kernel linear, it only checks it on random inputs. The simulators
+// carry symbolic bits (Vec) through the instructions of the reductions and the Karatsuba
+// combinations, so a run on symbolic registers builds the matrix from the instructions themselves
+// and CompareSymbolic proves those steps for all inputs. The twisted key and the whole block
+// multiplication are still checked with Extract.
+package gf2
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Poly is a polynomial over GF(2), bit i of word i/64 is the coefficient of x^i.
+type Poly []uint64
+
+// Monomial returns x^i.
+func Monomial(i int) Poly {
+	p := make(Poly, i/64+1)
+	p[i/64] = 1 << (i % 64)
+	return p
+}
+
+// PolyFromBits returns the polynomial of the little-endian bit vector b, bit k of b is the coefficient of x^k.
+func PolyFromBits(b []byte) Poly {
+	p := make(Poly, (len(b)+7)/8)
+	for i, v := range b {
+		p[i/8] |= uint64(v) << (8 * (i % 8))
+	}
+	return p.norm()
+}
+
+// Bits returns the n bytes little-endian bit vector of p, the coefficients of x^(8n) and above are dropped.
+func (p Poly) Bits(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		if i/8 < len(p) {
+			b[i] = byte(p[i/8] >> (8 * (i % 8)))
+		}
+	}
+	return b
+}
+
+func (p Poly) norm() Poly {
+	for len(p) > 0 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// Deg returns the degree of p, -1 for the zero polynomial.
+func (p Poly) Deg() int {
+	p = p.norm()
+	if len(p) == 0 {
+		return -1
+	}
+	return 64*(len(p)-1) + 63 - bits.LeadingZeros64(p[len(p)-1])
+}
+
+// Coeff returns the coefficient of x^i.
+func (p Poly) Coeff(i int) uint {
+	if i/64 >= len(p) {
+		return 0
+	}
+	return uint(p[i/64]>>(i%64)) & 1
+}
+
+// Add returns p + q.
+func (p Poly) Add(q Poly) Poly {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	r := append(Poly(nil), p...)
+	for i, v := range q {
+		r[i] ^= v
+	}
+	return r.norm()
+}
+
+// Shl returns p * x^n.
+func (p Poly) Shl(n int) Poly {
+	r := make(Poly, len(p)+n/64+1)
+	for i := 0; i <= p.Deg(); i++ {
+		if p.Coeff(i) == 1 {
+			j := i + n
+			r[j/64] |= 1 << (j % 64)
+		}
+	}
+	return r.norm()
+}
+
+// Mul returns p * q.
+func (p Poly) Mul(q Poly) Poly {
+	var r Poly
+	for i := 0; i <= q.Deg(); i++ {
+		if q.Coeff(i) == 1 {
+			r = r.Add(p.Shl(i))
+		}
+	}
+	return r
+}
+
+// Mod returns p mod m.
+func (p Poly) Mod(m Poly) Poly {
+	dm := m.Deg()
+	r := append(Poly(nil), p...).norm()
+	for d := r.Deg(); d >= dm; d = r.Deg() {
+		r = r.Add(m.Shl(d - dm))
+	}
+	return r
+}
+
+// MulMod returns p * q mod m.
+func (p Poly) MulMod(q, m Poly) Poly {
+	return p.Mul(q).Mod(m)
+}
+
+// Equal reports whether p and q are the same polynomial.
+func (p Poly) Equal(q Poly) bool {
+	return p.Add(q).Deg() < 0
+}
+
+// String returns p in the form of "x^128 + x^7 + x^2 + x + 1" with variable x.
+func (p Poly) String() string {
+	return p.format("x")
+}
+
+func (p Poly) format(v string) string {
+	var terms []string
+	for i := p.Deg(); i >= 0; i-- {
+		if p.Coeff(i) == 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			terms = append(terms, "1")
+		case 1:
+			terms = append(terms, v)
+		default:
+			terms = append(terms, v+"^"+strconv.Itoa(i))
+		}
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " + ")
+}
@@ -0,0 +1,37 @@
+package gf2
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPolyMulMod(t *testing.T) {
+	if got, want := GHASHPoly.String(), "x^128 + x^7 + x^2 + x + 1"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// (x + 1)^2 = x^2 + 1
+	if got := Monomial(1).Add(Monomial(0)).Mul(Monomial(1).Add(Monomial(0))); got.String() != "x^2 + 1" {
+		t.Errorf("got %v", got)
+	}
+	// x^128 = x^7 + x^2 + x + 1 mod GHASHPoly
+	if got := Monomial(128).Mod(GHASHPoly); got.String() != "x^7 + x^2 + x + 1" {
+		t.Errorf("got %v", got)
+	}
+	if got := montgomeryFactor.MulMod(Monomial(128), PolyvalPoly); got.String() != "1" {
+		t.Errorf("x^-128 * x^128 = %v", got)
+	}
+}
+
+func TestPolyBits(t *testing.T) {
+	b := []byte{0x01, 0x80, 0x00, 0x02}
+	p := PolyFromBits(b)
+	if got, want := p.String(), "x^25 + x^15 + 1"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := p.Bits(4); !bytes.Equal(got, b) {
+		t.Errorf("got %x, want %x", got, b)
+	}
+	if p.Deg() != 25 || Poly(nil).Deg() != -1 {
+		t.Errorf("wrong degree")
+	}
+}
@@ -0,0 +1,135 @@
+package gf2
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Vec is a symbolic bit vector, bit k is the sum of the input bits of v[k]: the coefficient of x^j
+// of v[k] is 1 if input bit j is a term. The simulators carry a Vec next to the bytes of a register
+// and their linear instructions compute it from the Vecs of the operands, so a kernel run on Var
+// inputs gives its linear map for all inputs and Linear returns its matrix. A Vec is not modified
+// once built, the operations return new vectors.
+type Vec []Poly
+
+// Var returns the vector of the n input bits off, ..., off+n-1.
+func Var(off, n int) Vec {
+	v := make(Vec, n)
+	for k := range v {
+		v[k] = Monomial(off + k)
+	}
+	return v
+}
+
+// Const returns the vector of the little-endian bits b, a linear map has no constant terms so b must be zero.
+// It panics otherwise.
+func Const(b []byte) Vec {
+	for _, c := range b {
+		if c != 0 {
+			panic(fmt.Sprintf("gf2: constant %x in a linear map", b))
+		}
+	}
+	return make(Vec, 8*len(b))
+}
+
+// Concat returns the vector of the bits of vs, the bits of vs[0] first.
+func Concat(vs ...Vec) Vec {
+	var r Vec
+	for _, v := range vs {
+		r = append(r, v...)
+	}
+	return r
+}
+
+// Add returns v + w, it panics if their lengths differ.
+func (v Vec) Add(w Vec) Vec {
+	if len(v) != len(w) {
+		panic("gf2: Add of vectors of different lengths")
+	}
+	r := make(Vec, len(v))
+	for k := range r {
+		r[k] = v[k].Add(w[k])
+	}
+	return r
+}
+
+// ReverseBytes returns v with the order of its bytes reversed, the bits of a byte keep their order.
+func (v Vec) ReverseBytes() Vec {
+	r := make(Vec, 0, len(v))
+	for i := len(v) - 8; i >= 0; i -= 8 {
+		r = append(r, v[i:i+8]...)
+	}
+	return r
+}
+
+// Clmul returns the 128 bits carry-less product of the 64 bits vector v and the constant c.
+func (v Vec) Clmul(c uint64) Vec {
+	if len(v) != 64 {
+		panic("gf2: Clmul of a vector which is not 64 bits")
+	}
+	r := make(Vec, 128)
+	for j := 0; j < 64; j++ {
+		if c>>j&1 == 0 {
+			continue
+		}
+		for i, p := range v {
+			r[i+j] = r[i+j].Add(p)
+		}
+	}
+	return r
+}
+
+// Eval returns the little-endian bits of v for the input bits x.
+func (v Vec) Eval(x []byte) []byte {
+	in := PolyFromBits(x)
+	y := make([]byte, (len(v)+7)/8)
+	for k, p := range v {
+		var s uint
+		for j := range 8 * len(x) {
+			s ^= p.Coeff(j) & in.Coeff(j)
+		}
+		y[k/8] |= byte(s) << (k % 8)
+	}
+	return y
+}
+
+// Linear returns the matrix of the map of in input bits to the bits of v.
+func Linear(in int, v Vec) *Matrix {
+	m := &Matrix{In: in, Out: len(v), cols: make([]Poly, in)}
+	for k, p := range v {
+		if p.Deg() >= in {
+			panic(fmt.Sprintf("gf2: bit %d depends on input bit %d of %d", k, p.Deg(), in))
+		}
+		for j := 0; j <= p.Deg(); j++ {
+			if p.Coeff(j) == 1 {
+				m.cols[j] = m.cols[j].Add(Monomial(k))
+			}
+		}
+	}
+	return m
+}
+
+// CompareSymbolic runs the kernel f on the input bits x, f sets its input registers symbolic with
+// Var and returns the bytes and the symbolic bits of its output. It checks the symbolic bits give
+// the bytes, which fails if an instruction of f does not carry them, and compares the map of the
+// symbolic bits of in input bits with the linear map want. The comparison holds for all inputs.
+func CompareSymbolic(in int, f func(x []byte) ([]byte, Vec), want func(x []byte) []byte) error {
+	x := make([]byte, in/8)
+	r := rand.New(rand.NewPCG(uint64(in), 1))
+	for k := range x {
+		x[k] = byte(r.Uint32())
+	}
+	y, v := f(x)
+	if len(v) != 8*len(y) {
+		return fmt.Errorf("gf2: %d symbolic bits for %d output bytes", len(v), len(y))
+	}
+	if e := v.Eval(x); !bytes.Equal(e, y) {
+		return fmt.Errorf("gf2: f(%x) = %x, the symbolic bits give %x, an instruction does not carry them", x, y, e)
+	}
+	w, err := Extract(in, len(v), want)
+	if err != nil {
+		return err
+	}
+	return Compare(Linear(in, v), w)
+}
@@ -0,0 +1,45 @@
+package gf2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVec(t *testing.T) {
+	x := []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+	v := Var(0, 64)
+	if got := v.Eval(x); !bytes.Equal(got, x) {
+		t.Errorf("Var: got %x, want %x", got, x)
+	}
+	if got, want := v.ReverseBytes().Eval(x), []byte{0xf0, 0xde, 0xbc, 0x9a, 0x78, 0x56, 0x34, 0x12}; !bytes.Equal(got, want) {
+		t.Errorf("ReverseBytes: got %x, want %x", got, want)
+	}
+	if got, want := Concat(v[8:], Const(make([]byte, 1))).Add(v).Eval(x), []byte{0x26, 0x62, 0x2e, 0xe2, 0x26, 0x62, 0x2e, 0xf0}; !bytes.Equal(got, want) {
+		t.Errorf("Add: got %x, want %x", got, want)
+	}
+	const c = 0x87
+	if got, want := v.Clmul(c).Eval(x), PolyFromBits(x).Mul(PolyFromBits([]byte{c})).Bits(16); !bytes.Equal(got, want) {
+		t.Errorf("Clmul: got %x, want %x", got, want)
+	}
+}
+
+func TestCompareSymbolic(t *testing.T) {
+	// rotate left by 1 bit of a byte
+	rotl := func(x []byte) []byte { return []byte{x[0]<<1 | x[0]>>7} }
+	symbolic := func(x []byte) ([]byte, Vec) {
+		v := Var(0, 8)
+		return rotl(x), Concat(v[7:], v[:7])
+	}
+	if err := CompareSymbolic(8, symbolic, rotl); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Linear(8, Concat(Var(7, 1), Var(0, 7))).FormatRow(0), "in[7]"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	stale := func(x []byte) ([]byte, Vec) { return rotl(x), Var(0, 8) }
+	if err := CompareSymbolic(8, stale, rotl); err == nil || !strings.Contains(err.Error(), "does not carry") {
+		t.Errorf("stale symbolic bits are not detected: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package gf256 holds the GF(2^8) arithmetic and the GF(2)^8 affine transforms shared by the S-box
+// tools, cmd/sboxgen and alg/sbox.
+package gf256
+
+// Mul multiplies x and y in GF(2^8) defined by the irreducible polynomial poly.
+func Mul(x, y byte, poly uint16) byte {
+	var r uint16
+	a := uint16(x)
+	for y != 0 {
+		if y&1 == 1 {
+			r ^= a
+		}
+		a <<= 1
+		if a&0x100 != 0 {
+			a ^= poly
+		}
+		y >>= 1
+	}
+	return byte(r)
+}
+
+// Inv returns the multiplicative inverse of x in GF(2^8), 0 is mapped to 0.
+func Inv(x byte, poly uint16) byte {
+	// x^254 = x^-1
+	r := byte(1)
+	for i := 0; i < 254; i++ {
+		r = Mul(r, x, poly)
+	}
+	return r
+}
+
+// AffineParams are the constants of S(x) = M2·f(M1·x+C1)+C2, where f is the AES S-box (AESNI) or
+// the AES field inversion (GFNI). M1 and M2 are in the qword layout of GF2P8AFFINEQB and GenLookupTable.
+type AffineParams struct {
+	M1, M2 uint64
+	C1, C2 byte
+}
+
+// Matrix is a linear map of GF(2)^8, element j is the image of 1<<j.
+type Matrix [8]byte
+
+// Identity is the identity map.
+var Identity = Matrix{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80}
+
+// Apply returns m·x.
+func (m Matrix) Apply(x byte) byte {
+	var y byte
+	for j := 0; j < 8; j++ {
+		if x&(1<<j) != 0 {
+			y ^= m[j]
+		}
+	}
+	return y
+}
+
+// Mul returns m·n, that is x -> m(n(x)).
+func (m Matrix) Mul(n Matrix) Matrix {
+	var r Matrix
+	for j := 0; j < 8; j++ {
+		r[j] = m.Apply(n[j])
+	}
+	return r
+}
+
+// Inverse returns the inverse of m by Gauss-Jordan elimination, ok is false if m is singular.
+func (m Matrix) Inverse() (inv Matrix, ok bool) {
+	// eliminate on the images, inv tracks the preimages
+	inv = Identity
+	for c := 0; c < 8; c++ {
+		bit := byte(1) << c
+		p := -1
+		for r := c; r < 8; r++ {
+			if m[r]&bit != 0 {
+				p = r
+				break
+			}
+		}
+		if p < 0 {
+			return inv, false
+		}
+		m[c], m[p] = m[p], m[c]
+		inv[c], inv[p] = inv[p], inv[c]
+		for r := 0; r < 8; r++ {
+			if r != c && m[r]&bit != 0 {
+				m[r] ^= m[c]
+				inv[r] ^= inv[c]
+			}
+		}
+	}
+	// m is the identity now, inv[j] is the preimage of 1<<j
+	return inv, true
+}
+
+// Qword returns the matrix in the qword layout of GF2P8AFFINEQB,
+// the row of output bit i is the byte 7-i of the qword.
+func (m Matrix) Qword() uint64 {
+	var q uint64
+	for i := 0; i < 8; i++ {
+		var row byte
+		for j := 0; j < 8; j++ {
+			row |= ((m[j] >> i) & 1) << j
+		}
+		q |= uint64(row) << (8 * (7 - i))
+	}
+	return q
+}
+
+// FromQword returns the matrix of the qword layout of GF2P8AFFINEQB.
+func FromQword(q uint64) Matrix {
+	var m Matrix
+	for i := 0; i < 8; i++ {
+		row := byte(q >> (8 * (7 - i)))
+		for j := 0; j < 8; j++ {
+			m[j] |= ((row >> j) & 1) << i
+		}
+	}
+	return m
+}
+
+// Affine computes M·x+c, the same as GF2P8AFFINEQB with qword m and imm c.
+func Affine(m uint64, x, c byte) byte {
+	return FromQword(m).Apply(x) ^ c
+}
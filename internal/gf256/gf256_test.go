@@ -0,0 +1,49 @@
+package gf256
+
+import "testing"
+
+func TestMulInv(t *testing.T) {
+	// FIPS-197 4.2: {57}·{83} = {c1}, {53}^-1 = {ca}
+	if got := Mul(0x57, 0x83, 0x11b); got != 0xc1 {
+		t.Errorf("Mul(57, 83) = %02x; want c1", got)
+	}
+	if got := Inv(0x53, 0x11b); got != 0xca {
+		t.Errorf("Inv(53) = %02x; want ca", got)
+	}
+	if got := Inv(0, 0x11b); got != 0 {
+		t.Errorf("Inv(0) = %02x; want 0", got)
+	}
+	for x := 1; x < 256; x++ {
+		if Mul(byte(x), Inv(byte(x), 0x18b), 0x18b) != 1 {
+			t.Fatalf("x·Inv(x) != 1 for x = %02x", x)
+		}
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	// the affine transform of AES S-box
+	a := Matrix{0x1f, 0x3e, 0x7c, 0xf8, 0xf1, 0xe3, 0xc7, 0x8f}
+	inv, ok := a.Inverse()
+	if !ok {
+		t.Fatal("Inverse() failed")
+	}
+	if got := a.Mul(inv); got != Identity {
+		t.Errorf("A·A^-1 = %x; want %x", got, Identity)
+	}
+	if got := inv.Mul(a); got != Identity {
+		t.Errorf("A^-1·A = %x; want %x", got, Identity)
+	}
+	if _, ok := (Matrix{0x01, 0x01}).Inverse(); ok {
+		t.Error("Inverse() of singular matrix succeeded")
+	}
+	if got := FromQword(a.Qword()); got != a {
+		t.Errorf("FromQword(Qword()) = %x; want %x", got, a)
+	}
+	// GF2P8AFFINEQB with the AES affine transform, S(0) = 0x63 and S(1) = A·inv(1)+0x63 = 0x7c
+	if got := Affine(a.Qword(), 0, 0x63); got != 0x63 {
+		t.Errorf("Affine(A, 0, 63) = %02x; want 63", got)
+	}
+	if got := Affine(a.Qword(), 1, 0x63); got != 0x7c {
+		t.Errorf("Affine(A, 1, 63) = %02x; want 7c", got)
+	}
+}
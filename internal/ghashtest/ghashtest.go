@@ -0,0 +1,149 @@
+// Package ghashtest holds the GHASH aggregation test and benchmark shared by the
+// CLMUL kernels of amd64, arm64, ppc64 and s390x.
+//
+// The simulators record the instructions of the kernels into an opcount.Counter, the
+// benchmarks report the instructions per block, each instruction per block and the peak
+// number of live registers of every aggregation depth next to the wall-clock time.
+// TestConstantTime checks the table lookups the simulators report to a ctcheck.Tracer.
+package ghashtest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/emmansun/simd/alg/ghash"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/opcount"
+)
+
+// Keys covers a key with the top bit of H clear and keys with it set, the latter take
+// the reduction branch of the key doubling.
+var Keys = []string{
+	"66e94bd4ef8a2c3b884cfa59ca342b2e",
+	"b83b533708bf535d0aa6e52980d53b78",
+	"ffffffffffffffffffffffffffffffff",
+	"80000000000000000000000000000001",
+}
+
+// Depths are the supported aggregation depths.
+var Depths = []int{1, 2, 4, 8, 16}
+
+// TestAggregation compares the kernel returned by newHasher with the alg/ghash GCM method
+// for every key and depth, at the lengths around the aggregation boundaries.
+// newHasher must panic for the unsupported depth 3.
+func TestAggregation(t *testing.T, newHasher func(key []byte, n int) ghash.Hasher) {
+	data := make([]byte, 16*50+7)
+	for i := range data {
+		data[i] = byte(i*7 + 3)
+	}
+	for _, k := range Keys {
+		key, _ := hex.DecodeString(k)
+		g2 := ghash.NewGCMMethod(key)
+		for _, n := range Depths {
+			g1 := newHasher(key, n)
+			for _, l := range []int{0, 15, 16, 16*n - 1, 16 * n, 16*n + 5, 16*3*n + 17, len(data)} {
+				var T1, T2 [16]byte
+				g1.Hash(&T1, data[:l])
+				g2.Hash(&T2, data[:l])
+				if !bytes.Equal(T1[:], T2[:]) {
+					t.Errorf("key %s aggregation %d length %d: got %x, want %x", k, n, l, T1, T2)
+				}
+			}
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("aggregation 3 does not panic")
+		}
+	}()
+	key, _ := hex.DecodeString(Keys[0])
+	newHasher(key, 3)
+}
+
+// count returns the instructions of hashing data with g, setCounter sets the counter of the simulator.
+func count(g ghash.Hasher, data []byte, setCounter func(c *opcount.Counter)) *opcount.Counter {
+	c := &opcount.Counter{}
+	var T [16]byte
+	setCounter(c)
+	defer setCounter(nil)
+	g.Hash(&T, data)
+	return c
+}
+
+// TestCounts checks the kernel records its instructions and the aggregation tradeoff: the
+// instructions per block go down and the live registers go up with the depth.
+func TestCounts(t *testing.T, newHasher func(key []byte, n int) ghash.Hasher, setCounter func(c *opcount.Counter)) {
+	key, _ := hex.DecodeString(Keys[0])
+	data := make([]byte, 16*16)
+	var prev *opcount.Counter
+	for _, n := range Depths {
+		c := count(newHasher(key, n), data, setCounter)
+		if c.Total() == 0 {
+			t.Fatalf("aggregation %d: no instructions recorded", n)
+		}
+		if prev != nil && (c.Total() >= prev.Total() || c.PeakLive() <= prev.PeakLive()) {
+			t.Errorf("aggregation %d: %d instructions and %d live registers, the previous depth %d and %d",
+				n, c.Total(), c.PeakLive(), prev.Total(), prev.PeakLive())
+		}
+		prev = c
+	}
+}
+
+// BenchmarkAggregation hashes 1KiB with each aggregation depth, and reports the instructions
+// per block, each instruction per block and the peak live registers of the kernel.
+func BenchmarkAggregation(b *testing.B, newHasher func(key []byte, n int) ghash.Hasher, setCounter func(c *opcount.Counter)) {
+	key, _ := hex.DecodeString(Keys[0])
+	data := make([]byte, 1024)
+	blocks := float64(len(data) / 16)
+	for _, n := range Depths {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			g := newHasher(key, n)
+			var T [16]byte
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				g.Hash(&T, data)
+			}
+			b.StopTimer()
+			c := count(g, data, setCounter)
+			b.ReportMetric(float64(c.Total())/blocks, "insns/block")
+			for _, name := range c.Names() {
+				b.ReportMetric(float64(c.Count(name))/blocks, name+"/block")
+			}
+			b.ReportMetric(float64(c.PeakLive()), "regs")
+		})
+	}
+}
+
+// TestConstantTime checks the kernel looks up with public indices only: its trace of table lookups
+// is not empty and the same for every secret key and data of the same length, at every depth.
+// setTracer installs the tracer of the simulator after the construction, so only Hash is traced.
+func TestConstantTime(t *testing.T, newHasher func(key []byte, n int) ghash.Hasher, setTracer func(tr *ctcheck.Tracer)) {
+	secrets := make([][]byte, 4)
+	for i := range secrets {
+		secrets[i] = make([]byte, 16+16*20+5)
+		for j := range secrets[i] {
+			secrets[i][j] = byte(i*67 + j*13)
+		}
+	}
+	defer setTracer(nil)
+	for _, n := range Depths {
+		events := 0
+		err := ctcheck.Check(func(tr *ctcheck.Tracer, secret []byte) {
+			g := newHasher(secret[:16], n)
+			setTracer(tr)
+			var T [16]byte
+			g.Hash(&T, secret[16:])
+			setTracer(nil)
+			events = tr.Len()
+		}, secrets...)
+		if err != nil {
+			t.Errorf("aggregation %d: %v", n, err)
+		}
+		if events == 0 {
+			t.Errorf("aggregation %d: empty trace, the kernel has no reported lookups", n)
+		}
+	}
+}
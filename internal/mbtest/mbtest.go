@@ -0,0 +1,148 @@
+// Package mbtest holds the test shared by the multi-buffer SHA-1 and MD5 block functions of
+// amd64/sse (4 lanes) and amd64/avx2 (8 lanes).
+package mbtest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"testing"
+
+	refmd5 "github.com/emmansun/simd/alg/md5"
+	refsha1 "github.com/emmansun/simd/alg/sha1"
+)
+
+// State is the chaining value of MD5 or SHA-1.
+type State interface {
+	[4]uint32 | [5]uint32
+}
+
+// Hash is the reference of a hash with the state S.
+type Hash[S State] struct {
+	IV        S
+	BlockSize int
+	Block     func(state *S, p []byte)
+	Pad       func(msg []byte) []byte // the message with its padding and length blocks
+	Digest    func(state *S) []byte
+	Sum       func(msg []byte) []byte // the standard library digest
+}
+
+// SHA1 is the reference of SHA-1, the digest is the big endian state.
+var SHA1 = Hash[[5]uint32]{
+	IV:        refsha1.IV,
+	BlockSize: refsha1.BlockSize,
+	Block:     refsha1.Block,
+	Pad:       refsha1.Pad,
+	Digest: func(state *[5]uint32) []byte {
+		d := make([]byte, sha1.Size)
+		for i, v := range state {
+			binary.BigEndian.PutUint32(d[4*i:], v)
+		}
+		return d
+	},
+	Sum: func(msg []byte) []byte {
+		d := sha1.Sum(msg)
+		return d[:]
+	},
+}
+
+// MD5 is the reference of MD5, the digest is the little endian state.
+var MD5 = Hash[[4]uint32]{
+	IV:        refmd5.IV,
+	BlockSize: refmd5.BlockSize,
+	Block:     refmd5.Block,
+	Pad:       refmd5.Pad,
+	Digest: func(state *[4]uint32) []byte {
+		d := make([]byte, md5.Size)
+		for i, v := range state {
+			binary.LittleEndian.PutUint32(d[4*i:], v)
+		}
+		return d
+	},
+	Sum: func(msg []byte) []byte {
+		d := md5.Sum(msg)
+		return d[:]
+	},
+}
+
+// MultiBlock compresses the blocks of len(state) messages of the same length, state[k] is the state of message p[k].
+type MultiBlock[S State] func(state []S, p [][]byte)
+
+// TestBlock checks mb with lanes lanes against the reference block function, each lane starts
+// from a different state, and the digests of padded messages against the standard library.
+// It also checks that mb panics if the last lane is one block shorter than the others.
+func TestBlock[S State](t *testing.T, h Hash[S], lanes int, mb MultiBlock[S]) {
+	state, want := make([]S, lanes), make([]S, lanes)
+	p := make([][]byte, lanes)
+	for k := range p {
+		// different states and messages per lane
+		first := make([]byte, h.BlockSize)
+		first[0] = byte(k)
+		state[k] = h.IV
+		h.Block(&state[k], first)
+		p[k] = make([]byte, 3*h.BlockSize)
+		for i := range p[k] {
+			p[k][i] = byte(i*13 + k*71 + 1)
+		}
+		want[k] = state[k]
+		h.Block(&want[k], p[k])
+	}
+	mb(state, p)
+	for k := range state {
+		if state[k] != want[k] {
+			t.Errorf("lane %d: got %08x; want %08x", k, state[k], want[k])
+		}
+	}
+
+	for n := 0; n < 200; n += 7 {
+		msg := make([][]byte, lanes)
+		for k := range p {
+			msg[k] = make([]byte, n)
+			for i := range msg[k] {
+				msg[k][i] = byte(i*7 + k*31)
+			}
+			p[k] = h.Pad(msg[k])
+			state[k] = h.IV
+		}
+		mb(state, p)
+		for k := range state {
+			if got, want := h.Digest(&state[k]), h.Sum(msg[k]); !bytes.Equal(got, want) {
+				t.Errorf("%d bytes lane %d: got %x; want %x", n, k, got, want)
+			}
+		}
+	}
+
+	for k := range p {
+		p[k] = make([]byte, 2*h.BlockSize)
+	}
+	p[lanes-1] = p[lanes-1][:h.BlockSize]
+	defer func() {
+		if recover() == nil {
+			t.Error("messages of different lengths: no panic")
+		}
+	}()
+	mb(state, p)
+}
+
+// BenchmarkBlock compresses 1KiB per lane with mb, and 1KiB with the standard library.
+func BenchmarkBlock[S State](b *testing.B, h Hash[S], lanes int, mb MultiBlock[S]) {
+	b.Run("lanes", func(b *testing.B) {
+		state := make([]S, lanes)
+		p := make([][]byte, lanes)
+		for k := range p {
+			p[k] = make([]byte, 1024)
+		}
+		b.SetBytes(int64(lanes) * 1024)
+		for i := 0; i < b.N; i++ {
+			mb(state, p)
+		}
+	})
+	b.Run("stdlib", func(b *testing.B) {
+		msg := make([]byte, 1024)
+		b.SetBytes(1024)
+		for i := 0; i < b.N; i++ {
+			h.Sum(msg)
+		}
+	})
+}
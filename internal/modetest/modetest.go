@@ -0,0 +1,78 @@
+// Package modetest holds the CMAC and OCB3 test shared by amd64, arm64, ppc64 and s390x,
+// which runs alg/cmac and alg/ocb with the simulated AES and GF(2^128) doubling of an architecture.
+package modetest
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/alg/cmac"
+	"github.com/emmansun/simd/alg/ocb"
+)
+
+// CMACCases are the AES-128 examples of RFC 4493 section 4.
+var CMACCases = []struct {
+	msg, mac string
+}{
+	{"", "bb1d6929e95937287fa37d129b756746"},
+	{"6bc1bee22e409f96e93d7e117393172a", "070a16b46b4d4144f79bdd9dd04a287c"},
+	{"6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411", "dfa66747de9ae63030ca32611497c827"},
+	{"6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710", "51f0bebf7e3b9d92fc49741779363cfe"},
+}
+
+// OCBCases are the AES-128 examples of RFC 7253 appendix A with 128-bit tags,
+// one for each of the 0, 8, 16, 24 and 32 bytes lengths.
+var OCBCases = []struct {
+	nonce, ad, plaintext, ciphertext string
+}{
+	{"bbaa99887766554433221100", "", "", "785407bfffc8ad9edcc5520ac9111ee6"},
+	{"bbaa99887766554433221101", "0001020304050607", "0001020304050607", "6820b3657b6f615a5725bda0d3b4eb3a257c9af1f8f03009"},
+	{"bbaa99887766554433221104", "000102030405060708090a0b0c0d0e0f", "000102030405060708090a0b0c0d0e0f", "571d535b60b277188be5147170a9a22c3ad7a4ff3835b8c5701c1ccec8fc3358"},
+	{"bbaa99887766554433221107", "000102030405060708090a0b0c0d0e0f1011121314151617", "000102030405060708090a0b0c0d0e0f1011121314151617", "1ca2207308c87c010756104d8840ce1952f09673a448a122c92c62241051f57356d7f3c90bb0e07f"},
+	{"bbaa9988776655443322110a", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "bd6f6c496201c69296c11efd138a467abd3c707924b964deaffc40319af5a48540fbba186c5553c68ad9f592a79a4240"},
+	{"bbaa9988776655443322110c", "", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "2942bfc773bda23cabc6acfd9bfd5835bd300f0973792ef46040c53f1432bcdfb5e1dde3bc18a5f840b52e653444d5df"},
+}
+
+// TestModes runs the CMAC and OCB3 cases with the block cipher of newCipher and the doubling mulX.
+func TestModes(t *testing.T, newCipher func(key []byte) (cipher.Block, error), mulX func(*[16]byte)) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	block, err := newCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range CMACCases {
+		msg, _ := hex.DecodeString(c.msg)
+		h, err := cmac.New(block, mulX)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write(msg)
+		if got := hex.EncodeToString(h.Sum(nil)); got != c.mac {
+			t.Errorf("CMAC case %d: got %v, want %v", i, got, c.mac)
+		}
+	}
+
+	key, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	block, err = newCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := ocb.New(block, mulX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range OCBCases {
+		nonce, _ := hex.DecodeString(c.nonce)
+		ad, _ := hex.DecodeString(c.ad)
+		plaintext, _ := hex.DecodeString(c.plaintext)
+		ct := aead.Seal(nil, nonce, plaintext, ad)
+		if got := hex.EncodeToString(ct); got != c.ciphertext {
+			t.Errorf("OCB case %d: got %v, want %v", i, got, c.ciphertext)
+		}
+		if pt, err := aead.Open(nil, nonce, ct, ad); err != nil || !bytes.Equal(pt, plaintext) {
+			t.Errorf("OCB case %d: Open got %x, %v", i, pt, err)
+		}
+	}
+}
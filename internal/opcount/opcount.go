@@ -0,0 +1,79 @@
+// Package opcount counts the instructions a simulated kernel executes and its register pressure.
+//
+// A simulator which supports counting records each instruction into the Counter given to its
+// SetCounter, with the register it writes and the registers it reads. The register pressure is
+// the number of registers live at once: a register is live from a write to its last read before
+// the next write, and the register an instruction writes is taken even if it is never read.
+package opcount
+
+import "sort"
+
+type step struct {
+	dst  any
+	srcs []any
+}
+
+// Counter records the instructions, a nil Counter records nothing.
+type Counter struct {
+	ops   map[string]int
+	steps []step
+}
+
+// Op records the instruction name which writes dst and reads srcs, dst is nil for a store.
+// The registers are pointers and are compared by identity.
+func (c *Counter) Op(name string, dst any, srcs ...any) {
+	if c == nil {
+		return
+	}
+	if c.ops == nil {
+		c.ops = make(map[string]int)
+	}
+	c.ops[name]++
+	c.steps = append(c.steps, step{dst, append([]any(nil), srcs...)})
+}
+
+// Count returns the number of the recorded name instructions.
+func (c *Counter) Count(name string) int {
+	return c.ops[name]
+}
+
+// Total returns the number of the recorded instructions.
+func (c *Counter) Total() int {
+	return len(c.steps)
+}
+
+// Names returns the names of the recorded instructions in sorted order.
+func (c *Counter) Names() []string {
+	names := make([]string, 0, len(c.ops))
+	for name := range c.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PeakLive returns the most registers live at once, the registers read before any write are
+// live from the start.
+func (c *Counter) PeakLive() int {
+	live := make(map[any]bool)
+	peak := 0
+	for i := len(c.steps) - 1; i >= 0; i-- {
+		s := c.steps[i]
+		if s.dst != nil {
+			live[s.dst] = true
+			peak = max(peak, len(live))
+			delete(live, s.dst)
+		}
+		for _, r := range s.srcs {
+			live[r] = true
+		}
+		peak = max(peak, len(live))
+	}
+	return peak
+}
+
+// Reset clears the recorded instructions.
+func (c *Counter) Reset() {
+	c.ops = nil
+	c.steps = nil
+}
@@ -0,0 +1,40 @@
+package opcount
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	var a, b, c, d int
+	x := &Counter{}
+	x.Op("LOAD", &a)
+	x.Op("LOAD", &b)
+	x.Op("XOR", &c, &a, &b)
+	x.Op("LOAD", &d)
+	x.Op("XOR", &a, &a, &c)
+	x.Op("XOR", &a, &a, &d)
+	x.Op("STORE", nil, &a)
+	if got, want := x.Total(), 7; got != want {
+		t.Errorf("Total() = %d, want %d", got, want)
+	}
+	if got, want := x.Count("LOAD"), 3; got != want {
+		t.Errorf(`Count("LOAD") = %d, want %d`, got, want)
+	}
+	if got, want := x.Names(), []string{"LOAD", "STORE", "XOR"}; !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	// d is loaded while a and c are live, c reuses the register of b
+	if got, want := x.PeakLive(), 3; got != want {
+		t.Errorf("PeakLive() = %d, want %d", got, want)
+	}
+
+	x.Reset()
+	x.Op("XOR", &a, &a, &b) // a and b are live from the start
+	if got, want := x.PeakLive(), 2; got != want {
+		t.Errorf("PeakLive() = %d, want %d", got, want)
+	}
+
+	var n *Counter
+	n.Op("XOR", &a, &b)
+}
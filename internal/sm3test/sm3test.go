@@ -0,0 +1,110 @@
+// Package sm3test holds the SM3 block function test shared by the SIMD message expansions
+// of amd64/avx, arm64, ppc64 and s390x, and the benchmark which compares them with the SM3
+// extensions by their simulated instructions.
+package sm3test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/emmansun/simd/alg/sm3"
+	"github.com/emmansun/simd/internal/opcount"
+)
+
+// Vectors are the examples of GB/T 32905-2016 appendix A.
+var Vectors = []struct {
+	In, Out string
+}{
+	{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+	{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+}
+
+// TestBlockFunc hashes Vectors with f, compares f with sm3.Block on four blocks
+// and compares the hashes of every length up to 300 bytes with sm3.Sum.
+func TestBlockFunc(t *testing.T, f sm3.BlockFunc) {
+	for _, c := range Vectors {
+		h := sm3.New(f)
+		h.Write([]byte(c.In))
+		if got := hex.EncodeToString(h.Sum(nil)); got != c.Out {
+			t.Errorf("SM3(%q) = %s; want %s", c.In, got, c.Out)
+		}
+	}
+
+	p := make([]byte, 4*sm3.BlockSize)
+	for i := range p {
+		p[i] = byte(i*31 + 7)
+	}
+	got, want := sm3.IV, sm3.IV
+	f(&got, p)
+	sm3.Block(&want, p)
+	if got != want {
+		t.Errorf("got %08x; want %08x", got, want)
+	}
+
+	msg := make([]byte, 300)
+	for i := range msg {
+		msg[i] = byte(i*13 + 5)
+	}
+	for n := 0; n <= len(msg); n++ {
+		h := sm3.New(f)
+		h.Write(msg[:n])
+		want := sm3.Sum(msg[:n])
+		if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Fatalf("length %d: got %x; want %x", n, got, want)
+		}
+	}
+}
+
+// Kernel is a block function to benchmark, ScalarRounds is the number of rounds per block it
+// leaves to scalar code: 64 for a SIMD message expansion, 0 for the SM3 extensions.
+type Kernel struct {
+	Name         string
+	Func         sm3.BlockFunc
+	ScalarRounds int
+}
+
+// count runs f on one block with the instructions recorded by the counters of setCounters.
+func count(f sm3.BlockFunc, setCounters []func(c *opcount.Counter)) *opcount.Counter {
+	c := &opcount.Counter{}
+	for _, set := range setCounters {
+		set(c)
+		defer set(nil)
+	}
+	state := sm3.IV
+	f(&state, make([]byte, sm3.BlockSize))
+	return c
+}
+
+// TestCounts checks every kernel records its vector instructions.
+func TestCounts(t *testing.T, kernels []Kernel, setCounters ...func(c *opcount.Counter)) {
+	for _, k := range kernels {
+		if c := count(k.Func, setCounters); c.Total() == 0 {
+			t.Errorf("%s: no instructions recorded", k.Name)
+		}
+	}
+}
+
+// BenchmarkBlockFuncs benchmarks the kernels on one block and reports the cost model of each:
+// the simulated vector instructions per block, by name, and the rounds left to scalar code.
+// setCounters install the counter of the simulators the kernels use.
+func BenchmarkBlockFuncs(b *testing.B, kernels []Kernel, setCounters ...func(c *opcount.Counter)) {
+	p := make([]byte, sm3.BlockSize)
+	for _, k := range kernels {
+		b.Run(k.Name, func(b *testing.B) {
+			state := sm3.IV
+			b.SetBytes(sm3.BlockSize)
+			for i := 0; i < b.N; i++ {
+				k.Func(&state, p)
+			}
+			b.StopTimer()
+			c := count(k.Func, setCounters)
+			b.ReportMetric(float64(c.Total()), "insns/block")
+			for _, name := range c.Names() {
+				b.ReportMetric(float64(c.Count(name)), name+"/block")
+			}
+			b.ReportMetric(float64(k.ScalarRounds), "scalar-rounds/block")
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package ppc64
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/emmansun/simd/alg/aes"
+)
+
+type aesPPC64Cipher struct {
+	enc []Vector128 // VNCIPHER decrypts with the encryption round keys
+}
+
+// NewAESCipher returns the AES-128/192/256 cipher with the POWER8 crypto instructions.
+func NewAESCipher(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, aes.KeySizeError(len(key))
+	}
+	c := &aesPPC64Cipher{}
+	c.expandKey(key)
+	return c, nil
+}
+
+// subWord applies the S-box to the word w and xors rcon with VCIPHERLAST,
+// ShiftRows does nothing on the splatted word.
+func subWord(w uint32, rcon byte, T, RCON *Vector128) uint32 {
+	LXVW4X_UINT32([]uint32{w, w, w, w}, T)
+	r := uint32(rcon) << 24
+	LXVW4X_UINT32([]uint32{r, r, r, r}, RCON)
+	VCIPHERLAST(T, RCON, T)
+	return T.Uint32s()[0]
+}
+
+func (c *aesPPC64Cipher) expandKey(key []byte) {
+	nk := len(key) / 4
+	nr := nk + 6
+	w := make([]uint32, 4*(nr+1))
+	for i := 0; i < nk; i++ {
+		w[i] = binary.BigEndian.Uint32(key[4*i:])
+	}
+	T, RCON := &Vector128{}, &Vector128{}
+	for i := nk; i < len(w); i++ {
+		t := w[i-1]
+		switch {
+		case i%nk == 0:
+			t = subWord(bits.RotateLeft32(t, 8), aes.Rcon[i/nk-1], T, RCON)
+		case nk > 6 && i%nk == 4:
+			t = subWord(t, 0, T, RCON)
+		}
+		w[i] = w[i-nk] ^ t
+	}
+	c.enc = make([]Vector128, nr+1)
+	for r := range c.enc {
+		LXVW4X_UINT32(w[4*r:], &c.enc[r])
+	}
+}
+
+func (c *aesPPC64Cipher) BlockSize() int {
+	return 16
+}
+
+func (c *aesPPC64Cipher) Encrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("ppc64: input not full block")
+	}
+	nr := len(c.enc) - 1
+	V0 := &Vector128{}
+	LXVD2X(src, V0)
+	VXOR(V0, &c.enc[0], V0)
+	for r := 1; r < nr; r++ {
+		VCIPHER(V0, &c.enc[r], V0)
+	}
+	VCIPHERLAST(V0, &c.enc[nr], V0)
+	STXVD2X(V0, dst)
+}
+
+func (c *aesPPC64Cipher) Decrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("ppc64: input not full block")
+	}
+	nr := len(c.enc) - 1
+	V0 := &Vector128{}
+	LXVD2X(src, V0)
+	VXOR(V0, &c.enc[nr], V0)
+	for r := nr - 1; r > 0; r-- {
+		VNCIPHER(V0, &c.enc[r], V0)
+	}
+	VNCIPHERLAST(V0, &c.enc[0], V0)
+	STXVD2X(V0, dst)
+}
@@ -0,0 +1,11 @@
+package ppc64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/aestest"
+)
+
+func TestAES(t *testing.T) {
+	aestest.TestCipher(t, NewAESCipher)
+}
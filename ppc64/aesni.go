@@ -2,7 +2,10 @@ package ppc64
 
 import "github.com/emmansun/simd/alg/aes"
 
+//go:generate go run ../cmd/sboxgen -pkg ppc64 -o sbox_params.go
+
 func VSBOX(src, dst *Vector128) {
+	lookup("VSBOX", src.bytes[:])
 	tmp := &Vector128{}
 	for i := 0; i < 16; i++ {
 		tmp.bytes[i] = aes.SBOX[src.bytes[i]]
@@ -10,6 +13,50 @@ func VSBOX(src, dst *Vector128) {
 	copy(dst.bytes[:], tmp.bytes[:])
 }
 
+// VCIPHER performs one AES encryption round of the state src, dst = MixColumns(SubBytes(ShiftRows(src))) ^ rk.
+// The state is in the byte order of the memory, byte 0 is the most significant byte.
+func VCIPHER(src, rk, dst *Vector128) {
+	lookup("VCIPHER", src.bytes[:])
+	s := src.bytes
+	aes.ShiftRows(&s)
+	aes.SubBytes(&s)
+	aes.MixColumns(&s)
+	aes.AddRoundKey(&s, &rk.bytes)
+	dst.bytes = s
+}
+
+// VCIPHERLAST performs the last AES encryption round, dst = SubBytes(ShiftRows(src)) ^ rk.
+func VCIPHERLAST(src, rk, dst *Vector128) {
+	lookup("VCIPHERLAST", src.bytes[:])
+	s := src.bytes
+	aes.ShiftRows(&s)
+	aes.SubBytes(&s)
+	aes.AddRoundKey(&s, &rk.bytes)
+	dst.bytes = s
+}
+
+// VNCIPHER performs one AES decryption round, dst = InvMixColumns(InvSubBytes(InvShiftRows(src)) ^ rk).
+// The round key is xored before InvMixColumns, so the decryption uses the encryption round keys.
+func VNCIPHER(src, rk, dst *Vector128) {
+	lookup("VNCIPHER", src.bytes[:])
+	s := src.bytes
+	aes.InvShiftRows(&s)
+	aes.InvSubBytes(&s)
+	aes.AddRoundKey(&s, &rk.bytes)
+	aes.InvMixColumns(&s)
+	dst.bytes = s
+}
+
+// VNCIPHERLAST performs the last AES decryption round, dst = InvSubBytes(InvShiftRows(src)) ^ rk.
+func VNCIPHERLAST(src, rk, dst *Vector128) {
+	lookup("VNCIPHERLAST", src.bytes[:])
+	s := src.bytes
+	aes.InvShiftRows(&s)
+	aes.InvSubBytes(&s)
+	aes.AddRoundKey(&s, &rk.bytes)
+	dst.bytes = s
+}
+
 func SboxWithAESNI(m1l, m1h, m2l, m2h, x *Vector128) {
 	VPERMXOR(m1h, m1l, x, x)
 	VSBOX(x, x)
@@ -1,10 +1,15 @@
 package ppc64
 
 import (
+	"encoding/binary"
 	"testing"
 
+	"github.com/emmansun/simd/alg/camellia"
+	"github.com/emmansun/simd/alg/sbox"
 	"github.com/emmansun/simd/alg/sm4"
 	"github.com/emmansun/simd/alg/zuc"
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
 )
 
 func testSboxWithAESNI(t *testing.T, idx int, m1l, m1h, m2l, m2h *Vector128, sbox *[256]byte) {
@@ -156,3 +161,118 @@ func TestZUCSBOXWithAESNI(t *testing.T) {
 		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
 	}
 }
+
+func TestGeneratedSBOXWithAESNIParams(t *testing.T) {
+	for i, c := range SM4SboxAESNIParams {
+		m1l := &Vector128{}
+		m1h := &Vector128{}
+		m2l := &Vector128{}
+		m2h := &Vector128{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &sm4.SBOX)
+	}
+	for i, c := range ZUCSboxAESNIParams {
+		m1l := &Vector128{}
+		m1h := &Vector128{}
+		m2l := &Vector128{}
+		m2h := &Vector128{}
+		GenLookupTable(c.M1, c.C1, m1l, m1h)
+		GenLookupTable(c.M2, c.C2, m2l, m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, &zuc.SBOX)
+	}
+}
+
+// intelBlock loads the 128 bits integer of the Intel white paper, it is little-endian in the memory.
+func intelBlock(hi, lo uint64, v *Vector128) {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:], lo)
+	binary.LittleEndian.PutUint64(b[8:], hi)
+	LXVD2X(b[:], v)
+}
+
+// The AES-NI examples of the Intel white paper, AESDEC is VNCIPHER with a zero key and the xor of the round key.
+func TestAESInstructions(t *testing.T) {
+	state, rk, zero := &Vector128{}, &Vector128{}, &Vector128{}
+	intelBlock(0x7b5b546573745665, 0x63746f725d53475d, state)
+	intelBlock(0x4869285368617929, 0x5b477565726f6e5d, rk)
+	cases := []struct {
+		name   string
+		f      func(v *Vector128)
+		hi, lo uint64
+	}{
+		{"VCIPHER", func(v *Vector128) { VCIPHER(v, rk, v) }, 0xa8311c2f9fdba3c5, 0x8b104b58ded7e595},
+		{"VCIPHERLAST", func(v *Vector128) { VCIPHERLAST(v, rk, v) }, 0xc7fb881e938c5964, 0x177ec42553fdc611},
+		{"VNCIPHER", func(v *Vector128) { VNCIPHER(v, zero, v); VXOR(v, rk, v) }, 0x138ac342faea2787, 0xb58eb95eb730392a},
+		{"VNCIPHERLAST", func(v *Vector128) { VNCIPHERLAST(v, rk, v) }, 0xc5a391ef6b317f95, 0xd410637b72a593d0},
+	}
+	for _, c := range cases {
+		v, want := *state, &Vector128{}
+		c.f(&v)
+		intelBlock(c.hi, c.lo, want)
+		if v != *want {
+			t.Errorf("%s got %x, want %x", c.name, v.Bytes(), want.Bytes())
+		}
+	}
+}
+
+// compiledSboxes are the S-boxes which alg/sbox compiles to the AESNI and GFNI constants.
+var compiledSboxes = []struct {
+	name string
+	sbox *[256]byte
+}{
+	{"SM4", &sm4.SBOX},
+	{"ZUC S1", &zuc.SBOX},
+	{"Camellia s1", &camellia.SBOX1},
+}
+
+func TestCompiledSBOXWithAESNI(t *testing.T) {
+	for i, c := range compiledSboxes {
+		tables, err := sbox.Compile(c.sbox)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		m1l, m1h, m2l, m2h := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+		LXVD2X(tables.M1L[:], m1l)
+		LXVD2X(tables.M1H[:], m1h)
+		LXVD2X(tables.M2L[:], m2l)
+		LXVD2X(tables.M2H[:], m2h)
+		testSboxWithAESNI(t, i+1, m1l, m1h, m2l, m2h, c.sbox)
+		// the tables are the ones GenLookupTable builds
+		l, h := &Vector128{}, &Vector128{}
+		GenLookupTable(tables.AESNI.M1, tables.AESNI.C1, l, h)
+		if *l != *m1l || *h != *m1h {
+			t.Errorf("%s: M1L, M1H = %x, %x; GenLookupTable %x, %x", c.name, m1l.Bytes(), m1h.Bytes(), l.Bytes(), h.Bytes())
+		}
+		GenLookupTable(tables.AESNI.M2, tables.AESNI.C2, l, h)
+		if *l != *m2l || *h != *m2h {
+			t.Errorf("%s: M2L, M2H = %x, %x; GenLookupTable %x, %x", c.name, m2l.Bytes(), m2h.Bytes(), l.Bytes(), h.Bytes())
+		}
+	}
+}
+
+func TestLookupTracer(t *testing.T) {
+	run := func(f func(x *Vector128)) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			x := &Vector128{}
+			LXVD2X(secret, x)
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(x)
+		}
+	}
+	reverse := func(x *Vector128) {
+		perm := &Vector128{}
+		LXVD2X([]byte{15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, perm)
+		VPERM(x, x, perm, x)
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "byte shuffle", Run: run(reverse)},
+		{Name: "permute", Site: "VPERM", Run: run(func(x *Vector128) { VPERM(&Vector128{}, &Vector128{}, x, &Vector128{}) })},
+		{Name: "permute xor", Site: "VPERMXOR", Run: run(func(x *Vector128) { VPERMXOR(&Vector128{}, &Vector128{}, x, &Vector128{}) })},
+		{Name: "VSBOX", Site: "VSBOX", Run: run(func(x *Vector128) { VSBOX(x, x) })},
+		{Name: "VCIPHER", Site: "VCIPHER", Run: run(func(x *Vector128) { VCIPHER(x, &Vector128{}, x) })},
+		{Name: "VNCIPHERLAST", Site: "VNCIPHERLAST", Run: run(func(x *Vector128) { VNCIPHERLAST(x, &Vector128{}, x) })},
+		{Name: "S-box", Site: "VPERMXOR", Run: run(func(x *Vector128) { SboxWithAESNI(&Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}, x) })},
+	})
+}
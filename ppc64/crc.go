@@ -0,0 +1,116 @@
+// The CRC kernel folds 4 or 8 blocks in parallel, combines the accumulators into one block, folds
+// it over the CRC width and reduces it with Barrett, see alg/crc for the constants.
+// A block is loaded as the 128 bits little endian number, VPMSUMD multiplies both doublewords and
+// sums the products, so one instruction folds a block.
+
+package ppc64
+
+import "github.com/emmansun/simd/alg/crc"
+
+type clmulPPC64CRC struct {
+	c         *crc.FoldConstants
+	lanes     int
+	isPPC64LE bool
+}
+
+// NewClmulPPC64CRC returns the folding kernel of the reflected CRC of width 32 or 64 with the
+// reflected polynomial poly, e.g. crc.IEEE, crc.Castagnoli, crc.ISO or crc.ECMA, which folds
+// lanes (4 or 8) blocks per iteration.
+func NewClmulPPC64CRC(width int, poly uint64, lanes int, isPPC64LE bool) *clmulPPC64CRC {
+	if lanes != 4 && lanes != 8 {
+		panic("ppc64: unsupported CRC fold lanes")
+	}
+	return &clmulPPC64CRC{c: crc.NewFoldConstants(width, poly), lanes: lanes, isPPC64LE: isPPC64LE}
+}
+
+// Update returns the CRC of p updated from v, as hash/crc32.Update and hash/crc64.Update.
+func (k *clmulPPC64CRC) Update(v uint64, p []byte) uint64 {
+	c := k.c
+	if len(p) < 16*k.lanes {
+		return crc.Update(c.Width, c.Poly, v, p)
+	}
+	mask := ^uint64(0) >> (64 - c.Width)
+	var (
+		X     = make([]Vector128, k.lanes)
+		B     = Vector128{}
+		Q     = Vector128{}
+		ZERO  = Vector128{}
+		XPERM = Vector128{}
+		KN    = Vector128{}
+		K1    = Vector128{}
+		KW    = Vector128{}
+		MU    = Vector128{}
+		POLYX = Vector128{}
+		INIT  = Vector128{}
+	)
+	// the high doubleword multiplies the high qword of the block
+	if k.lanes == 4 {
+		LXVD2X_UINT64([]uint64{c.Fold4[1], c.Fold4[0]}, &KN)
+	} else {
+		LXVD2X_UINT64([]uint64{c.Fold8[1], c.Fold8[0]}, &KN)
+	}
+	LXVD2X_UINT64([]uint64{c.Fold1[1], c.Fold1[0]}, &K1)
+	LXVD2X_UINT64([]uint64{c.FoldWidth[1], c.FoldWidth[0]}, &KW)
+	LXVD2X_UINT64([]uint64{0, c.Mu}, &MU)
+	LXVD2X_UINT64([]uint64{0, c.PolyX}, &POLYX)
+	LXVD2X_UINT64([]uint64{0, ^v & mask}, &INIT)
+	if !k.isPPC64LE {
+		LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, &XPERM)
+	}
+
+	for i := range X {
+		k.loadBlock(p[16*i:], &X[i], &XPERM)
+	}
+	VXOR(&X[0], &INIT, &X[0])
+	p = p[16*k.lanes:]
+
+	for len(p) >= 16*k.lanes {
+		for i := range X {
+			k.loadBlock(p[16*i:], &B, &XPERM)
+			VPMSUMD(&X[i], &KN, &X[i])
+			VXOR(&X[i], &B, &X[i])
+		}
+		p = p[16*k.lanes:]
+	}
+
+	// combine the accumulators
+	for i := 1; i < k.lanes; i++ {
+		VPMSUMD(&X[0], &K1, &X[0])
+		VXOR(&X[0], &X[i], &X[0])
+	}
+	for len(p) >= 16 {
+		k.loadBlock(p, &B, &XPERM)
+		VPMSUMD(&X[0], &K1, &X[0])
+		VXOR(&X[0], &B, &X[0])
+		p = p[16:]
+	}
+
+	// fold over the width, X = R128(Ah·x^w + Al)
+	VPMSUMD(&X[0], &KW, &X[0])
+
+	// Barrett reduction, q = Ah·Mu / x^63
+	if c.Width == 32 {
+		VSLDOI(12, &ZERO, &X[0], &Q)
+	} else {
+		VOR(&X[0], &X[0], &Q)
+	}
+	VPMSUMD(&Q, &MU, &Q)
+	VPMSUMD(&Q, &POLYX, &B)
+	VXOR(&X[0], &B, &X[0])
+	VSLDOI(8, &Q, &ZERO, &Q)
+	VXOR(&X[0], &Q, &X[0])
+
+	v = X[0].Uint64s()[0]>>(64-c.Width) ^ mask
+	return crc.Update(c.Width, c.Poly, v, p)
+}
+
+// loadBlock loads 16 bytes as the 128 bits little endian number.
+func (k *clmulPPC64CRC) loadBlock(p []byte, B, XPERM *Vector128) {
+	if k.isPPC64LE {
+		LXVD2X_PPC64LE(p, B)
+		VSLDOI(8, B, B, B)
+	} else {
+		LXVD2X(p, B)
+		VPERM(B, B, XPERM, B)
+	}
+}
@@ -0,0 +1,22 @@
+package ppc64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/crctest"
+)
+
+func newClmulCRC(isPPC64LE bool) func(width int, poly uint64, lanes int) crctest.Kernel {
+	return func(width int, poly uint64, lanes int) crctest.Kernel {
+		return NewClmulPPC64CRC(width, poly, lanes, isPPC64LE)
+	}
+}
+
+func TestClmulCRC(t *testing.T) {
+	t.Run("ppc64le", func(t *testing.T) { crctest.TestKernel(t, newClmulCRC(true)) })
+	t.Run("ppc64", func(t *testing.T) { crctest.TestKernel(t, newClmulCRC(false)) })
+}
+
+func BenchmarkClmulCRC32(b *testing.B) {
+	crctest.BenchmarkCRC32(b, newClmulCRC(true))
+}
@@ -0,0 +1,102 @@
+package ppc64
+
+// GF128MulXBE multiplies B by x in the big endian convention (CMAC, OCB).
+// T0, T1 are temporary registers.
+func GF128MulXBE(B, T0, T1 *Vector128) {
+	POLY := &Vector128{}
+	LXVD2X_UINT64([]uint64{0, 0x87}, POLY)
+	VSPLTB(0, B, T0) // most significant byte
+	VSPLTISB(7, T1)
+	VSRAB(T0, T1, T0) // broadcast the carry bit
+	VAND(POLY, T0, T0)
+	VSPLTISB(1, T1)
+	VSL(B, T1, T1) // B << 1
+	VXOR(T0, T1, B)
+}
+
+// GF128MulXLE multiplies B by x in the little endian convention (IEEE 1619 XTS).
+// T0, T1 are temporary registers.
+func GF128MulXLE(B, T0, T1 *Vector128) {
+	ESPERM := &Vector128{}
+	LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, ESPERM)
+	VPERM(B, B, ESPERM, B)
+	GF128MulXBE(B, T0, T1)
+	VPERM(B, B, ESPERM, B)
+}
+
+// GF128MulXGB multiplies B by x in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value with the reduction 0xe1 << 120.
+// T0, T1 are temporary registers.
+func GF128MulXGB(B, T0, T1 *Vector128) {
+	POLY := &Vector128{}
+	LXVD2X_UINT64([]uint64{0xe100000000000000, 0}, POLY)
+	VSPLTB(15, B, T0) // least significant byte
+	VSPLTISB(7, T1)
+	VSLB(T0, T1, T0)  // move the carry bit to the most significant bit
+	VSRAB(T0, T1, T0) // broadcast it
+	VAND(POLY, T0, T0)
+	VSPLTISB(1, T1)
+	VSR(B, T1, B) // B >> 1
+	VXOR(T0, B, B)
+}
+
+// checkMulXPow panics unless 1 <= k <= 8, the k bits shifted out of the block fit in a byte.
+func checkMulXPow(k int) {
+	if k < 1 || k > 8 {
+		panic("ppc64: GF128MulXPow k out of range")
+	}
+}
+
+// GF128MulXPowBE multiplies B by x^k, 1 <= k <= 8, in the big endian convention (CMAC, OCB).
+// VSL shifts by at most 7 bits, x^8 is a byte shift. T0, T1 are temporary registers.
+func GF128MulXPowBE(B, T0, T1 *Vector128, k int) {
+	checkMulXPow(k)
+	POLY := &Vector128{}
+	ZERO := &Vector128{}
+	LXVD2X_UINT64([]uint64{0, 0x87}, POLY)
+	VSPLTISB(0, ZERO)
+	VSLDOI(1, ZERO, B, T0) // the most significant byte at the bottom
+	VSPLTISB(byte(8-k), T1)
+	VSR(T0, T1, T0)       // the bits shifted out
+	VPMSUMD(T0, POLY, T0) // ·0x87
+	if k == 8 {
+		VSLDOI(1, B, ZERO, B)
+	} else {
+		VSPLTISB(byte(k), T1)
+		VSL(B, T1, B)
+	}
+	VXOR(T0, B, B)
+}
+
+// GF128MulXPowLE multiplies B by x^k, 1 <= k <= 8, in the little endian convention (IEEE 1619 XTS).
+// T0, T1 are temporary registers.
+func GF128MulXPowLE(B, T0, T1 *Vector128, k int) {
+	ESPERM := &Vector128{}
+	LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, ESPERM)
+	VPERM(B, B, ESPERM, B)
+	GF128MulXPowBE(B, T0, T1, k)
+	VPERM(B, B, ESPERM, B)
+}
+
+// GF128MulXPowGB multiplies B by x^k, 1 <= k <= 8, in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value by k bits, the bits shifted out are reduced with 0xe1 << (121 - k).
+// T0, T1 are temporary registers.
+func GF128MulXPowGB(B, T0, T1 *Vector128, k int) {
+	checkMulXPow(k)
+	MASK := &Vector128{}
+	POLY := &Vector128{}
+	ZERO := &Vector128{}
+	LXVD2X_UINT64([]uint64{0, 1<<k - 1}, MASK)
+	LXVD2X_UINT64([]uint64{0, 0xe1 << (57 - k)}, POLY)
+	VSPLTISB(0, ZERO)
+	VAND(B, MASK, T0)       // the bits shifted out
+	VPMSUMD(T0, POLY, T0)   // ·0xe1 << (57 - k)
+	VSLDOI(8, T0, ZERO, T0) // to the high doubleword
+	if k == 8 {
+		VSLDOI(15, ZERO, B, B)
+	} else {
+		VSPLTISB(byte(k), T1)
+		VSR(B, T1, B)
+	}
+	VXOR(T0, B, B)
+}
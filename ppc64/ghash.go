@@ -1,16 +1,52 @@
-// The theoretical proof behind it is still not very clear.
+// The kernel computes r(X)·(r(H)·z)·z^-128 mod the bit reflected polynomial, see internal/gf2 for
+// the derivation and ghash_proof_test.go which proves the reduction for all inputs.
+
 package ppc64
 
 import "encoding/binary"
 
 type clmulPPC64Ghash struct {
 	isPPC64LE         bool
-	bytesProductTable [400]byte // (8 * 3 + 1) * 16
+	polyval           bool
+	aggregation       int
+	bytesProductTable []byte // (aggregation * 3 + 1) * 16
+}
+
+// defaultAggregation is the number of blocks processed by a reduction.
+const defaultAggregation = 8
+
+// checkAggregation panics if n is not a supported aggregation depth.
+func checkAggregation(n int) {
+	switch n {
+	case 1, 2, 4, 8, 16:
+	default:
+		panic("ppc64: unsupported GHASH aggregation depth")
+	}
 }
 
 func NewClmulPPC64Ghash(h []byte, isPPC64LE bool) *clmulPPC64Ghash {
+	return newClmulPPC64Ghash(h, isPPC64LE, defaultAggregation, false)
+}
+
+// NewClmulPPC64GhashWithAggregation returns the kernel which aggregates n (1, 2, 4, 8 or 16) blocks
+// per reduction, the precomputed table holds n powers of H.
+func NewClmulPPC64GhashWithAggregation(h []byte, isPPC64LE bool, n int) *clmulPPC64Ghash {
+	checkAggregation(n)
+	return newClmulPPC64Ghash(h, isPPC64LE, n, false)
+}
+
+// NewClmulPPC64Polyval returns the POLYVAL (RFC 8452) variant of the kernel,
+// the blocks are byte reversed instead of byte swapped and the key is not doubled.
+func NewClmulPPC64Polyval(h []byte, isPPC64LE bool) *clmulPPC64Ghash {
+	return newClmulPPC64Ghash(h, isPPC64LE, defaultAggregation, true)
+}
+
+func newClmulPPC64Ghash(h []byte, isPPC64LE bool, n int, polyval bool) *clmulPPC64Ghash {
 	g := &clmulPPC64Ghash{}
 	g.isPPC64LE = isPPC64LE
+	g.polyval = polyval
+	g.aggregation = n
+	g.bytesProductTable = make([]byte, (n*3+1)*16)
 	var (
 		XC2  = &Vector128{}
 		T0   = &Vector128{}
@@ -29,6 +65,14 @@ func NewClmulPPC64Ghash(h []byte, isPPC64LE bool) *clmulPPC64Ghash {
 		H2H  = &Vector128{}
 	)
 
+	if polyval {
+		// POLYVAL key is little endian
+		var v [16]byte
+		for i := range v {
+			v[i] = h[15-i]
+		}
+		h = v[:]
+	}
 	var h1, h2 uint64
 	if isPPC64LE {
 		// can use VPERM to convert from little-endian to big-endian
@@ -45,31 +89,35 @@ func NewClmulPPC64Ghash(h []byte, isPPC64LE bool) *clmulPPC64Ghash {
 	VXOR(ZERO, ZERO, ZERO)
 	g.initPoly(XC2, ZERO, T0, T1)
 
-	// Multiply by 2 modulo P
-	VSPLTISB(7, T2)
-	VSPLTB(0, H, T1)  // most significant byte
-	VSL(H, T0, H)     // H<<=1
-	VSRAB(T1, T2, T1) // broadcast carry bit
-	VAND(T1, XC2, T1)
-	VXOR(H, T1, IN)        // twisted H
+	if polyval {
+		VOR(H, H, IN)
+	} else {
+		// Multiply by 2 modulo P
+		VSPLTISB(7, T2)
+		VSPLTB(0, H, T1)  // most significant byte
+		VSL(H, T0, H)     // H<<=1
+		VSRAB(T1, T2, T1) // broadcast carry bit
+		VAND(T1, XC2, T1)
+		VXOR(H, T1, IN) // twisted H
+	}
 	VSLDOI(8, IN, IN, H)   // twist even more ...
 	VSLDOI(8, ZERO, H, HL) // ... and split
 	VSLDOI(8, H, ZERO, HH)
 
 	VSLDOI(8, ZERO, XC2, XC2) // 0xc2.0
 	if isPPC64LE {
-		STXVD2X_PPC64LE(XC2, g.bytesProductTable[24*16:])
-		STXVD2X_PPC64LE(HL, g.bytesProductTable[21*16:])
-		STXVD2X_PPC64LE(H, g.bytesProductTable[22*16:])
-		STXVD2X_PPC64LE(HH, g.bytesProductTable[23*16:])
+		STXVD2X_PPC64LE(XC2, g.bytesProductTable[(3*n)*16:])
+		STXVD2X_PPC64LE(HL, g.bytesProductTable[(3*n-3)*16:])
+		STXVD2X_PPC64LE(H, g.bytesProductTable[(3*n-2)*16:])
+		STXVD2X_PPC64LE(HH, g.bytesProductTable[(3*n-1)*16:])
 	} else {
-		STXVD2X(XC2, g.bytesProductTable[24*16:])
-		STXVD2X(HL, g.bytesProductTable[21*16:])
-		STXVD2X(H, g.bytesProductTable[22*16:])
-		STXVD2X(HH, g.bytesProductTable[23*16:])
+		STXVD2X(XC2, g.bytesProductTable[(3*n)*16:])
+		STXVD2X(HL, g.bytesProductTable[(3*n-3)*16:])
+		STXVD2X(H, g.bytesProductTable[(3*n-2)*16:])
+		STXVD2X(HH, g.bytesProductTable[(3*n-1)*16:])
 	}
 
-	for i := 6; i >= 0; i-- {
+	for i := n - 2; i >= 0; i-- {
 		// Multiplication
 		VPMSUMD(IN, HL, XL) // H.lo·H.lo
 		VPMSUMD(IN, H, XM)  // H.hi·H.lo+H.lo·H.hi
@@ -109,25 +157,21 @@ func (g *clmulPPC64Ghash) Hash(T *[16]byte, data []byte) {
 		ACC1  = &Vector128{}
 		ACCM  = &Vector128{}
 		B0    = &Vector128{}
-		B1    = &Vector128{}
-		B2    = &Vector128{}
-		B3    = &Vector128{}
-		B4    = &Vector128{}
-		B5    = &Vector128{}
-		B6    = &Vector128{}
-		B7    = &Vector128{}
+		B     = make([]Vector128, g.aggregation)
 	)
-	if g.isPPC64LE {
+	if g.isPPC64LE && !g.polyval {
 		LXVD2X_UINT64([]uint64{0x0706050403020100, 0x0f0e0d0c0b0a0908}, XPERM)
+	} else if !g.isPPC64LE && g.polyval {
+		LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, XPERM)
 	}
 	VXOR(ZERO, ZERO, ZERO)
 	VXOR(ACC0, ACC0, ACC0)
-	g.loadPrecomputed(24, XC2)
+	g.loadPrecomputed(3*g.aggregation, XC2)
 
-	// handle 8 blocks at a time
-	for len(data) >= 128 {
-		// load 8 blocks
-		g.load8Blocks(data, B0, B1, B2, B3, B4, B5, B6, B7, XPERM)
+	// handle aggregation blocks at a time
+	for len(data) >= 16*g.aggregation {
+		// load blocks
+		g.loadBlocks(data, B, XPERM)
 
 		// load precomputed values
 		g.loadPrecomputed(0, HL)
@@ -136,38 +180,32 @@ func (g *clmulPPC64Ghash) Hash(T *[16]byte, data []byte) {
 
 		// process first block
 		// add previous result
-		VXOR(B0, ACC0, B0)
+		VXOR(&B[0], ACC0, &B[0])
 		// multiplication
-		VPMSUMD(B0, HL, ACC0)
-		VPMSUMD(B0, H, ACCM)
-		VPMSUMD(B0, HH, ACC1)
-
-		g.mulRoundAAD(B1, ACC0, ACC1, ACCM, HL, H, HH, T0, 1)
-		g.mulRoundAAD(B2, ACC0, ACC1, ACCM, HL, H, HH, T0, 2)
-		g.mulRoundAAD(B3, ACC0, ACC1, ACCM, HL, H, HH, T0, 3)
-		g.mulRoundAAD(B4, ACC0, ACC1, ACCM, HL, H, HH, T0, 4)
-		g.mulRoundAAD(B5, ACC0, ACC1, ACCM, HL, H, HH, T0, 5)
-		g.mulRoundAAD(B6, ACC0, ACC1, ACCM, HL, H, HH, T0, 6)
-		g.mulRoundAAD(B7, ACC0, ACC1, ACCM, HL, H, HH, T0, 7)
+		VPMSUMD(&B[0], HL, ACC0)
+		VPMSUMD(&B[0], H, ACCM)
+		VPMSUMD(&B[0], HH, ACC1)
+
+		for i := 1; i < g.aggregation; i++ {
+			g.mulRoundAAD(&B[i], ACC0, ACC1, ACCM, HL, H, HH, T0, i)
+		}
 
 		g.processClmulResult(ACC0, ACCM, ACC1, ZERO, T0)
 		g.fastReduction(ACC0, ACC1, ACC0, T0, XC2)
 
-		data = data[128:]
+		data = data[16*g.aggregation:]
 	}
 
 	// load precomputed values
-	g.loadPrecomputed(21, HL)
-	g.loadPrecomputed(22, H)
-	g.loadPrecomputed(23, HH)
+	g.loadPrecomputed(3*g.aggregation-3, HL)
+	g.loadPrecomputed(3*g.aggregation-2, H)
+	g.loadPrecomputed(3*g.aggregation-1, HH)
 
 	// handle one block at a time
 	for len(data) >= 16 {
 		// load 1 block
 		g.loadData(data, 0, B0)
-		if g.isPPC64LE {
-			VPERM(B0, B0, XPERM, B0)
-		}
+		g.permute(B0, XPERM)
 		g.mulOneBlock(B0, ACC0, ACCM, ACC1, HL, H, HH, T0, XC2, ZERO)
 		data = data[16:]
 	}
@@ -175,38 +213,35 @@ func (g *clmulPPC64Ghash) Hash(T *[16]byte, data []byte) {
 		var partialBlock [16]byte
 		copy(partialBlock[:], data)
 		g.loadData(partialBlock[:], 0, B0)
-		if g.isPPC64LE {
-			VPERM(B0, B0, XPERM, B0)
-		}
+		g.permute(B0, XPERM)
 		g.mulOneBlock(B0, ACC0, ACCM, ACC1, HL, H, HH, T0, XC2, ZERO)
 	}
+	g.permute(ACC0, XPERM)
 	if g.isPPC64LE {
-		VPERM(ACC0, ACC0, XPERM, ACC0)
 		STXVD2X_PPC64LE(ACC0, T[:])
 	} else {
 		STXVD2X(ACC0, T[:])
 	}
 }
 
-func (g *clmulPPC64Ghash) load8Blocks(data []byte, B0, B1, B2, B3, B4, B5, B6, B7, XPERM *Vector128) {
-	g.loadData(data, 0, B0)
-	g.loadData(data, 1, B1)
-	g.loadData(data, 2, B2)
-	g.loadData(data, 3, B3)
-	g.loadData(data, 4, B4)
-	g.loadData(data, 5, B5)
-	g.loadData(data, 6, B6)
-	g.loadData(data, 7, B7)
+func (g *clmulPPC64Ghash) loadBlocks(data []byte, B []Vector128, XPERM *Vector128) {
+	for i := range B {
+		g.loadData(data, i, &B[i])
+	}
+	for i := range B {
+		g.permute(&B[i], XPERM)
+	}
+}
 
-	if g.isPPC64LE {
-		VPERM(B0, B0, XPERM, B0)
-		VPERM(B1, B1, XPERM, B1)
-		VPERM(B2, B2, XPERM, B2)
-		VPERM(B3, B3, XPERM, B3)
-		VPERM(B4, B4, XPERM, B4)
-		VPERM(B5, B5, XPERM, B5)
-		VPERM(B6, B6, XPERM, B6)
-		VPERM(B7, B7, XPERM, B7)
+// permute converts a loaded block to the kernel representation and back, all of them are involutions:
+// GHASH on ppc64le swaps the bytes in each doubleword, POLYVAL on ppc64le swaps the doublewords,
+// POLYVAL on ppc64 reverses the bytes.
+func (g *clmulPPC64Ghash) permute(B, XPERM *Vector128) {
+	switch {
+	case g.isPPC64LE && g.polyval:
+		VSLDOI(8, B, B, B)
+	case g.isPPC64LE || g.polyval:
+		VPERM(B, B, XPERM, B)
 	}
 }
 
@@ -0,0 +1,99 @@
+package ppc64
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/gf2"
+)
+
+// The registers are big-endian, the bit vector of a register is its bytes in reverse order.
+
+func toBits(v *Vector128) []byte {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = v.bytes[15-i]
+	}
+	return b
+}
+
+// fromBits sets v to the symbolic input bits off, ..., off+127 of value b.
+func fromBits(b []byte, off int, v *Vector128) {
+	x := make([]byte, 16)
+	for i := range x {
+		x[i] = b[15-i]
+	}
+	SetSymbolic(v, gf2.Var(off, 128).ReverseBytes(), x)
+}
+
+// toSymbolic returns the symbolic bits of v in the order of toBits.
+func toSymbolic(v *Vector128) gf2.Vec {
+	return v.Symbolic().ReverseBytes()
+}
+
+// TestGHashProcessClmulResultProof proves processClmulResult combines the partial products for all inputs.
+func TestGHashProcessClmulResultProof(t *testing.T) {
+	g := NewClmulPPC64Ghash(make([]byte, 16), true)
+	f := func(x []byte) ([]byte, gf2.Vec) {
+		var ACC0, ACCM, ACC1, ZERO, T Vector128
+		fromBits(x[:16], 0, &ACC0)
+		fromBits(x[16:32], 128, &ACCM)
+		fromBits(x[32:], 256, &ACC1)
+		g.processClmulResult(&ACC0, &ACCM, &ACC1, &ZERO, &T)
+		return append(toBits(&ACC0), toBits(&ACC1)...), gf2.Concat(toSymbolic(&ACC0), toSymbolic(&ACC1))
+	}
+	if err := gf2.CompareSymbolic(384, f, gf2.Schoolbook); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGHashFastReductionProof proves fastReduction is the Montgomery reduction for all inputs.
+func TestGHashFastReductionProof(t *testing.T) {
+	for _, isPPC64LE := range []bool{true, false} {
+		g := NewClmulPPC64Ghash(make([]byte, 16), isPPC64LE)
+		f := func(x []byte) ([]byte, gf2.Vec) {
+			var ACC0, ACC1, T, XC2 Vector128
+			g.loadPrecomputed(3*g.aggregation, &XC2)
+			fromBits(x[:16], 0, &ACC0)
+			fromBits(x[16:], 128, &ACC1)
+			g.fastReduction(&ACC0, &ACC1, &ACC0, &T, &XC2)
+			return toBits(&ACC0), toSymbolic(&ACC0)
+		}
+		if err := gf2.CompareSymbolic(256, f, gf2.MontgomeryReduce); err != nil {
+			t.Fatalf("isPPC64LE %v: %v", isPPC64LE, err)
+		}
+	}
+}
+
+// TestGHashTwistProof checks the precomputed key is the twisted key 2H on the basis vectors and random inputs,
+// the table holds it with the doublewords swapped.
+func TestGHashTwistProof(t *testing.T) {
+	for _, isPPC64LE := range []bool{true, false} {
+		f := func(h []byte) []byte {
+			var H Vector128
+			g := NewClmulPPC64GhashWithAggregation(h, isPPC64LE, 1)
+			g.loadPrecomputed(1, &H)
+			VSLDOI(8, &H, &H, &H)
+			return toBits(&H)
+		}
+		if err := gf2.CompareLinear(128, 128, f, gf2.Twist); err != nil {
+			t.Fatalf("isPPC64LE %v: %v", isPPC64LE, err)
+		}
+	}
+}
+
+// TestGHashMulProof checks the single block multiplication is the GHASH multiplication on the basis vectors and random inputs.
+func TestGHashMulProof(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	for _, isPPC64LE := range []bool{true, false} {
+		f := func(x, h []byte) []byte {
+			var T [16]byte
+			NewClmulPPC64GhashWithAggregation(h, isPPC64LE, 1).Hash(&T, x)
+			return T[:]
+		}
+		if err := gf2.CompareBilinear(128, 128, 128, f, gf2.GHASHMul); err != nil {
+			t.Fatalf("isPPC64LE %v: %v", isPPC64LE, err)
+		}
+	}
+}
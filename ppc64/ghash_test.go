@@ -2,10 +2,12 @@ package ppc64
 
 import (
 	"bytes"
+	"crypto/aes"
 	"encoding/hex"
 	"testing"
 
 	"github.com/emmansun/simd/alg/ghash"
+	"github.com/emmansun/simd/internal/ghashtest"
 )
 
 var ghashCases = []struct {
@@ -44,3 +46,110 @@ func TestPPC64LEGHash(t *testing.T) {
 		}
 	}
 }
+
+func TestPPC64LEGHashStream(t *testing.T) {
+	for i, c := range ghashCases {
+		key, _ := hex.DecodeString(c.key)
+		data, _ := hex.DecodeString(c.data)
+		aad, ct := data[:len(data)/3], data[len(data)/3:]
+		s1 := ghash.NewStream(NewClmulPPC64Ghash(key, true))
+		s2 := ghash.NewStream(ghash.NewGCMMethod(key))
+		s1.WriteAAD(aad[:len(aad)/2])
+		s1.WriteAAD(aad[len(aad)/2:])
+		for p := ct; len(p) > 0; p = p[min(7, len(p)):] {
+			s1.Write(p[:min(7, len(p))])
+		}
+		s2.WriteAAD(aad)
+		s2.Write(ct)
+		T1, T2 := s1.Sum(nil), s2.Sum(nil)
+		if !bytes.Equal(T1, T2) {
+			t.Errorf("case %d: got %v, want %v", i, hex.EncodeToString(T1), hex.EncodeToString(T2))
+		}
+	}
+}
+
+func TestPPC64LEPolyval(t *testing.T) {
+	// RFC 8452 appendix A
+	key, _ := hex.DecodeString("25629347589242761d31f826ba4b757b")
+	data, _ := hex.DecodeString("4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362")
+	var T [16]byte
+	NewClmulPPC64Polyval(key, true).Hash(&T, data)
+	if got, want := hex.EncodeToString(T[:]), "f7a3b47b846119fae5b7866cf5e5b77e"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for i, c := range ghashCases {
+		key, _ := hex.DecodeString(c.key)
+		g1 := NewClmulPPC64Polyval(key, true)
+		g2 := ghash.NewPolyvalMethod(key)
+		var T1, T2 [16]byte
+		data, _ := hex.DecodeString(c.data)
+		g1.Hash(&T1, data)
+		g2.Hash(&T2, data)
+		if !bytes.Equal(T1[:], T2[:]) {
+			t.Errorf("case %d: got %v, want %v", i, hex.EncodeToString(T1[:]), hex.EncodeToString(T2[:]))
+		}
+	}
+}
+
+func TestPPC64Polyval(t *testing.T) {
+	// RFC 8452 appendix A
+	key, _ := hex.DecodeString("25629347589242761d31f826ba4b757b")
+	data, _ := hex.DecodeString("4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362")
+	var T [16]byte
+	NewClmulPPC64Polyval(key, false).Hash(&T, data)
+	if got, want := hex.EncodeToString(T[:]), "f7a3b47b846119fae5b7866cf5e5b77e"; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for i, c := range ghashCases {
+		key, _ := hex.DecodeString(c.key)
+		g1 := NewClmulPPC64Polyval(key, false)
+		g2 := ghash.NewPolyvalMethod(key)
+		var T1, T2 [16]byte
+		data, _ := hex.DecodeString(c.data)
+		g1.Hash(&T1, data)
+		g2.Hash(&T2, data)
+		if !bytes.Equal(T1[:], T2[:]) {
+			t.Errorf("case %d: got %v, want %v", i, hex.EncodeToString(T1[:]), hex.EncodeToString(T2[:]))
+		}
+	}
+}
+
+func TestPPC64LEGCM(t *testing.T) {
+	key, _ := hex.DecodeString("feffe9928665731c6d6a8f9467308308")
+	block, _ := aes.NewCipher(key)
+	for i, c := range ghashCases {
+		data, _ := hex.DecodeString(c.data)
+		aad, plaintext := data[:len(data)/3], data[len(data)/3:]
+		for _, nonceSize := range []int{8, 12, 60} {
+			nonce := make([]byte, nonceSize)
+			copy(nonce, data)
+			g1, _ := ghash.NewGCMWithNonceAndTagSize(block, func(h []byte) ghash.Hasher { return NewClmulPPC64Ghash(h, true) }, nonceSize, 16)
+			g2, _ := ghash.NewGCMWithNonceAndTagSize(block, func(h []byte) ghash.Hasher { return ghash.NewGCMMethod(h) }, nonceSize, 16)
+			ct1 := g1.Seal(nil, nonce, plaintext, aad)
+			ct2 := g2.Seal(nil, nonce, plaintext, aad)
+			if !bytes.Equal(ct1, ct2) {
+				t.Errorf("case %d nonce size %d: got %x, want %x", i, nonceSize, ct1, ct2)
+			}
+		}
+	}
+}
+
+func newPPC64LEAggregation(key []byte, n int) ghash.Hasher {
+	return NewClmulPPC64GhashWithAggregation(key, true, n)
+}
+
+func TestPPC64LEGHashAggregation(t *testing.T) {
+	ghashtest.TestAggregation(t, newPPC64LEAggregation)
+}
+
+func TestPPC64LEGHashCounts(t *testing.T) {
+	ghashtest.TestCounts(t, newPPC64LEAggregation, SetCounter)
+}
+
+func TestPPC64LEGHashConstantTime(t *testing.T) {
+	ghashtest.TestConstantTime(t, newPPC64LEAggregation, SetTracer)
+}
+
+func BenchmarkPPC64LEGHashAggregation(b *testing.B) {
+	ghashtest.BenchmarkAggregation(b, newPPC64LEAggregation, SetCounter)
+}
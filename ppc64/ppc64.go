@@ -1,15 +1,94 @@
 package ppc64
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/gf2"
+	"github.com/emmansun/simd/internal/opcount"
+)
 
 type Vector128 struct {
 	bytes [16]byte
+	sym   *gf2.Vec // the symbolic bits of the register, nil if it is not symbolic, a pointer keeps Vector128 comparable
 }
 
 func (m *Vector128) Bytes() []byte {
 	return m.bytes[:]
 }
 
+// SetSymbolic makes dst symbolic with the bits v and sets the bytes to x, bit 8i+k of v is bit k of byte i
+// in the register order, the big endian order of the elements. VXOR, VSLDOI and VPMSUMD with a constant
+// operand carry the symbolic bits, so a GF(2) linear kernel run on symbolic registers computes its linear
+// map, see internal/gf2.
+func SetSymbolic(dst *Vector128, v gf2.Vec, x []byte) {
+	copy(dst.bytes[:], x)
+	dst.setSymbolic(v)
+}
+
+// Symbolic returns the symbolic bits of m in the order of SetSymbolic, nil if m is not symbolic.
+func (m *Vector128) Symbolic() gf2.Vec {
+	if m.sym == nil {
+		return nil
+	}
+	return *m.sym
+}
+
+// counter records the executed instructions, see SetCounter.
+var counter *opcount.Counter
+
+// SetCounter makes the instructions of the GHASH kernel (LXVD2X, STXVD2X, VSPLTISB, VSPLTB,
+// VADDUBM, VAND, VOR, VXOR, VSL, VSRAB, VSLDOI, VPERM and VPMSUMD) record themselves into c,
+// nil stops the recording. The recording is not safe for concurrent use.
+func SetCounter(c *opcount.Counter) {
+	counter = c
+}
+
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes VPERM, VPERMXOR and the AES instructions report the indices of their table
+// lookups, the permute control and the S-box inputs, to t, nil stops the reporting. The reporting
+// is not safe for concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
+// setSymbolic sets the symbolic bits of m to v, m is not symbolic if v is nil.
+func (m *Vector128) setSymbolic(v gf2.Vec) {
+	m.sym = nil
+	if v != nil {
+		m.sym = &v
+	}
+}
+
+// symbolic returns the symbolic bits of the operands, the constant ones must be zero, nil if none is symbolic.
+func symbolic(ms ...*Vector128) []gf2.Vec {
+	for _, m := range ms {
+		if m.sym != nil {
+			v := make([]gf2.Vec, len(ms))
+			for i, m := range ms {
+				v[i] = m.Symbolic()
+				if v[i] == nil {
+					v[i] = gf2.Const(m.bytes[:])
+				}
+			}
+			return v
+		}
+	}
+	return nil
+}
+
 func (m *Vector128) Uint64s() []uint64 {
 	return []uint64{binary.BigEndian.Uint64(m.bytes[:]), binary.BigEndian.Uint64(m.bytes[8:])}
 }
@@ -28,23 +107,29 @@ func LVX_UINT64(ints []uint64, dst *Vector128) {
 }
 
 func LXVD2X(rawbytes []byte, dst *Vector128) {
+	counter.Op("LXVD2X", dst)
 	copy(dst.bytes[:], rawbytes)
+	dst.sym = nil
 }
 
 func LXVD2X_PPC64LE(rawbytes []byte, dst *Vector128) {
+	counter.Op("LXVD2X", dst)
 	for i := 0; i < 8; i++ {
 		dst.bytes[i] = rawbytes[7-i]
 	}
 	for i := 8; i < 16; i++ {
 		dst.bytes[i] = rawbytes[23-i]
 	}
+	dst.sym = nil
 }
 
 func STXVD2X(v *Vector128, dst []byte) {
+	counter.Op("STXVD2X", nil, v)
 	copy(dst, v.bytes[:])
 }
 
 func STXVD2X_PPC64LE(v *Vector128, dst []byte) {
+	counter.Op("STXVD2X", nil, v)
 	for i := 0; i < 8; i++ {
 		dst[i] = v.bytes[7-i]
 	}
@@ -54,8 +139,10 @@ func STXVD2X_PPC64LE(v *Vector128, dst []byte) {
 }
 
 func LXVD2X_UINT64(ints []uint64, dst *Vector128) {
+	counter.Op("LXVD2X", dst)
 	binary.BigEndian.PutUint64(dst.bytes[:], ints[0])
 	binary.BigEndian.PutUint64(dst.bytes[8:], ints[1])
+	dst.sym = nil
 }
 
 func LXVW4X_UINT32(ints []uint32, dst *Vector128) {
@@ -66,21 +153,29 @@ func LXVW4X_UINT32(ints []uint32, dst *Vector128) {
 }
 
 func VAND(src1, src2, dst *Vector128) {
+	counter.Op("VAND", dst, src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] & src2.bytes[i]
 	}
 }
 
 func VOR(src1, src2, dst *Vector128) {
+	counter.Op("VOR", dst, src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] | src2.bytes[i]
 	}
 }
 
 func VXOR(src1, src2, dst *Vector128) {
+	counter.Op("VXOR", dst, src1, src2)
+	v := symbolic(src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] ^ src2.bytes[i]
 	}
+	dst.sym = nil
+	if v != nil {
+		dst.setSymbolic(v[0].Add(v[1]))
+	}
 }
 
 func signExtend32(b byte) uint32 {
@@ -115,12 +210,14 @@ func signExtend8(b byte) byte {
 }
 
 func VSPLTISB(b byte, dst *Vector128) {
+	counter.Op("VSPLTISB", dst)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = signExtend8(b)
 	}
 }
 
 func VSPLTB(ind byte, src, dst *Vector128) {
+	counter.Op("VSPLTB", dst, src)
 	ind = ind & 0x0f
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src.bytes[ind]
@@ -177,10 +274,11 @@ func VSLW(src, indicator, dst *Vector128) {
 }
 
 func VSL(src, indicator, dst *Vector128) {
-	sh := indicator.bytes[15] & 0x03
+	counter.Op("VSL", dst, src, indicator)
+	sh := indicator.bytes[15] & 0x07
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
-		ind := indicator.bytes[i] & 0x03
+		ind := indicator.bytes[i] & 0x07
 		if sh != ind {
 			panic("VSL: shift amount must be the same for all bytes")
 		}
@@ -194,10 +292,10 @@ func VSL(src, indicator, dst *Vector128) {
 }
 
 func VSR(src, indicator, dst *Vector128) {
-	sh := indicator.bytes[15] & 0x03
+	sh := indicator.bytes[15] & 0x07
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
-		ind := indicator.bytes[i] & 0x03
+		ind := indicator.bytes[i] & 0x07
 		if sh != ind {
 			panic("VSR: shift amount must be the same for all bytes")
 		}
@@ -210,15 +308,17 @@ func VSR(src, indicator, dst *Vector128) {
 	copy(dst.bytes[:], tmp.bytes[:])
 }
 
+// VRLW rotates each word of src left by the low 5 bits of the corresponding word of indicator.
 func VRLW(src, indicator, dst *Vector128) {
 	for i := 0; i < 16; i += 4 {
 		ind := indicator.bytes[i+3] & 0x1f
 		s := binary.BigEndian.Uint32(src.bytes[i:])
-		binary.BigEndian.PutUint32(dst.bytes[i:], (s>>ind)|(s<<(32-ind)))
+		binary.BigEndian.PutUint32(dst.bytes[i:], (s<<ind)|(s>>(32-ind)))
 	}
 }
 
 func VSLDOI(shb byte, vA, vB, vD *Vector128) {
+	counter.Op("VSLDOI", vD, vA, vB)
 	intShb := int(shb) & 0x0f
 	tmp := Vector128{}
 	for i := intShb; i < 16; i++ {
@@ -227,17 +327,31 @@ func VSLDOI(shb byte, vA, vB, vD *Vector128) {
 	for i := 0; i < intShb; i++ {
 		tmp.bytes[16-intShb+i] = vB.bytes[i]
 	}
+	if v := symbolic(vA, vB); v != nil {
+		tmp.setSymbolic(gf2.Concat(v[0][8*intShb:], v[1][:8*intShb]))
+	}
 	copy(vD.bytes[:], tmp.bytes[:])
+	vD.sym = tmp.sym
 }
 
 func VSRAB(src, indicator, dst *Vector128) {
+	counter.Op("VSRAB", dst, src, indicator)
 	for i := 0; i < 16; i++ {
 		ind := indicator.bytes[i] & 0x7
 		dst.bytes[i] = byte(int8(src.bytes[i]) >> ind)
 	}
 }
 
+// VSEL selects the bits of vB where the bits of vC are set and the bits of vA otherwise.
+func VSEL(vA, vB, vC, dst *Vector128) {
+	for i := 0; i < 16; i++ {
+		dst.bytes[i] = vA.bytes[i]&^vC.bytes[i] | vB.bytes[i]&vC.bytes[i]
+	}
+}
+
 func VPERM(src1, src2, perm, dst *Vector128) {
+	counter.Op("VPERM", dst, src1, src2, perm)
+	lookup("VPERM", perm.bytes[:])
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
 		idx := perm.bytes[i] & 0x1f
@@ -251,6 +365,7 @@ func VPERM(src1, src2, perm, dst *Vector128) {
 }
 
 func VPERMXOR(src1, src2, perm, dst *Vector128) {
+	lookup("VPERMXOR", perm.bytes[:])
 	tmp := &Vector128{}
 	for i := 0; i < 16; i++ {
 		idxHi := perm.bytes[i] >> 4
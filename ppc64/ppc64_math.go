@@ -1,6 +1,10 @@
 package ppc64
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/internal/gf2"
+)
 
 func clmul(a, b uint64) (hi, lo uint64) {
 	var temp uint64
@@ -22,17 +26,36 @@ func clmul(a, b uint64) (hi, lo uint64) {
 }
 
 func VPMSUMD(src1, src2, dst *Vector128) {
+	counter.Op("VPMSUMD", dst, src1, src2)
 	hi1 := binary.BigEndian.Uint64(src1.bytes[:])
 	lo1 := binary.BigEndian.Uint64(src1.bytes[8:])
 	hi2 := binary.BigEndian.Uint64(src2.bytes[:])
 	lo2 := binary.BigEndian.Uint64(src2.bytes[8:])
 
+	var sym gf2.Vec
+	switch {
+	case src1.sym != nil && src2.sym != nil:
+		panic("ppc64: VPMSUMD of two symbolic operands is not linear")
+	case src1.sym != nil:
+		sym = pmsumdSymbolic(src1.Symbolic(), hi2, lo2)
+	case src2.sym != nil:
+		sym = pmsumdSymbolic(src2.Symbolic(), hi1, lo1)
+	}
+
 	hi, lo := clmul(hi1, hi2)
 	hi3, lo3 := clmul(lo1, lo2)
 	hi ^= hi3
 	lo ^= lo3
 	binary.BigEndian.PutUint64(dst.bytes[:], hi)
 	binary.BigEndian.PutUint64(dst.bytes[8:], lo)
+	dst.setSymbolic(sym)
+}
+
+// pmsumdSymbolic returns the symbolic bits of VPMSUMD of the symbolic register v and the constant doublewords hi and lo.
+func pmsumdSymbolic(v gf2.Vec, hi, lo uint64) gf2.Vec {
+	// the bits of a big endian doubleword are in the reversed byte order of the register
+	p := v[:64].ReverseBytes().Clmul(hi).Add(v[64:].ReverseBytes().Clmul(lo))
+	return p.ReverseBytes()
 }
 
 func VMULOUB(src1, src2, dst *Vector128) {
@@ -116,6 +139,7 @@ func VMULESH(src1, src2, dst *Vector128) {
 }
 
 func VADDUBM(src1, src2, dst *Vector128) {
+	counter.Op("VADDUBM", dst, src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] + src2.bytes[i]
 	}
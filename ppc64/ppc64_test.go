@@ -1,8 +1,11 @@
 package ppc64
 
 import (
+	"bytes"
 	"encoding/hex"
 	"testing"
+
+	"github.com/emmansun/simd/internal/gf2"
 )
 
 func TestTransposeMatrix1(t *testing.T) {
@@ -190,3 +193,53 @@ func TestPreTransposeMatrix3(t *testing.T) {
 		t.Errorf("t3 = %v; want 3333333377777777bbbbbbbbffffffff", got3)
 	}
 }
+
+func TestVRLW(t *testing.T) {
+	src, ind, dst := &Vector128{}, &Vector128{}, &Vector128{}
+	LXVW4X_UINT32([]uint32{0x80000001, 0x12345678, 0x12345678, 0xdeadbeef}, src)
+	LXVW4X_UINT32([]uint32{1, 8, 0xfffffff4, 0}, ind) // the low 5 bits count, -12 is 20
+	VRLW(src, ind, dst)
+	want := []uint32{0x00000003, 0x34567812, 0x67812345, 0xdeadbeef}
+	for i, v := range dst.Uint32s() {
+		if v != want[i] {
+			t.Errorf("VRLW word %d = %08x; want %08x", i, v, want[i])
+		}
+	}
+}
+
+func TestVSEL(t *testing.T) {
+	a, b, c, dst := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	LXVD2X_UINT64([]uint64{0x0123456789abcdef, 0x0123456789abcdef}, a)
+	LXVD2X_UINT64([]uint64{0xfedcba9876543210, 0xfedcba9876543210}, b)
+	LXVD2X_UINT64([]uint64{0xffffffff00000000, 0x00ff00ff00ff00ff}, c)
+	VSEL(a, b, c, dst)
+	if got := dst.Uint64s(); got[0] != 0xfedcba9889abcdef || got[1] != 0x01dc45988954cd10 {
+		t.Errorf("VSEL = %x", got)
+	}
+}
+
+// TestSymbolic checks the symbolic bits of the instructions which carry them give their bytes.
+func TestSymbolic(t *testing.T) {
+	in, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0ff0e1d2c3b4a5968778695a4b3c2d1e0f")
+	var POLY Vector128
+	poly, _ := hex.DecodeString("c2000000000000010000000000000087")
+	copy(POLY.bytes[:], poly)
+	cases := []struct {
+		name string
+		op   func(x, y *Vector128)
+	}{
+		{"VXOR", func(x, y *Vector128) { VXOR(x, y, x) }},
+		{"VSLDOI", func(x, y *Vector128) { VSLDOI(5, x, y, x) }},
+		{"VPMSUMD src1", func(x, y *Vector128) { VPMSUMD(x, &POLY, x) }},
+		{"VPMSUMD src2", func(x, y *Vector128) { VPMSUMD(&POLY, y, x) }},
+	}
+	for _, c := range cases {
+		var x, y Vector128
+		SetSymbolic(&x, gf2.Var(0, 128), in[:16])
+		SetSymbolic(&y, gf2.Var(128, 128), in[16:])
+		c.op(&x, &y)
+		if got := x.Symbolic().Eval(in); !bytes.Equal(got, x.Bytes()) {
+			t.Errorf("%s: the symbolic bits give %x, want %x", c.name, got, x.Bytes())
+		}
+	}
+}
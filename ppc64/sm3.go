@@ -0,0 +1,100 @@
+package ppc64
+
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/sm3"
+)
+
+// SM3 with the vector message expansion of sm3.Round: each step computes four words with VSLDOI,
+// VRLW and VXOR. The registers are big-endian, word lane 0 is W[i].
+
+// vrlw rotates each word of src left by n bits, T holds the splatted count.
+// VSPLTISW takes a 5-bit signed immediate and VRLW uses the low 5 bits of each word,
+// so the rotations by 23 and 30 are written -9 and -2.
+func vrlw(n int8, src, T, dst *Vector128) {
+	if n < -16 || n > 15 {
+		panic("ppc64: vrlw count out of the VSPLTISW range")
+	}
+	VSPLTISW(byte(n), T)
+	VRLW(src, T, dst)
+}
+
+// messageSchedule computes out = W[i+16..i+19] of w0 = W[i..i+3], ..., w3 = W[i+12..i+15], see sm3.Round.
+// The fix of W[i+19] is computed on all lanes and VSEL merges it into lane 3 with LANE3 = [0, 0, 0, 0xffffffff].
+func messageSchedule(w0, w1, w2, w3, ZERO, LANE3, T, t0, t1, t2, out *Vector128) {
+	VSLDOI(12, w1, w2, t0)  // t0 = W[-9] = W7 W8 W9 W10
+	VXOR(t0, w0, t0)        // t0 = W[-9] ^ W[-16]
+	VSLDOI(4, w3, ZERO, t1) // t1 = W[-3] = W13 W14 W15 0
+	vrlw(15, t1, T, t1)
+	VXOR(t0, t1, t0) // t0 = W[-16] ^ W[-9] ^ (W[-3] <<< 15), lane 3 lacks W16 <<< 15
+
+	vrlw(15, t0, T, t1)
+	vrlw(-9, t0, T, t2) // <<< 23
+	VXOR(t1, t2, t1)
+	VXOR(t1, t0, out) // out = P1(t0)
+
+	VSLDOI(12, w0, w1, t0) // t0 = W[-13] = W3 W4 W5 W6
+	vrlw(7, t0, T, t0)
+	VXOR(out, t0, out)
+	VSLDOI(8, w2, w3, t0) // t0 = W[-6] = W10 W11 W12 W13
+	VXOR(out, t0, out)    // out = W16 W17 W18 W19'
+
+	VSPLTW(0, out, t0) // t0 = W16 W16 W16 W16
+	vrlw(15, t0, T, t1)
+	vrlw(-2, t0, T, t2) // <<< 30
+	VXOR(t1, t2, t1)
+	vrlw(6, t0, T, t2)
+	VXOR(t1, t2, t1)
+	VSEL(ZERO, t1, LANE3, t1)
+	VXOR(out, t1, out) // out = W16 W17 W18 W19
+}
+
+// sm3block is the SM3 block function with the vector message expansion and scalar rounds.
+func sm3block(state *[8]uint32, p []byte) {
+	var (
+		V0    = &Vector128{}
+		V1    = &Vector128{}
+		V2    = &Vector128{}
+		V3    = &Vector128{}
+		V4    = &Vector128{}
+		V5    = &Vector128{}
+		V6    = &Vector128{}
+		V7    = &Vector128{}
+		V8    = &Vector128{}
+		T     = &Vector128{}
+		ZERO  = &Vector128{}
+		LANE3 = &Vector128{}
+	)
+	var wt, wt1 [16]byte // W[i..i+3] and W'[i..i+3] of the step
+	VSPLTISW(0, ZERO)
+	LXVW4X_UINT32([]uint32{0, 0, 0, 0xffffffff}, LANE3)
+
+	for len(p) >= 64 {
+		s := *state
+		// the block words are big-endian, the ppc64le version permutes the bytes after the load
+		LXVD2X(p, V0)
+		LXVD2X(p[16:], V1)
+		LXVD2X(p[32:], V2)
+		LXVD2X(p[48:], V3)
+
+		w := [5]*Vector128{V0, V1, V2, V3, V4}
+		for i := 0; i < 64; i += 4 {
+			// W[i+16..i+19] is needed up to W[67]
+			if i < 52 {
+				messageSchedule(w[0], w[1], w[2], w[3], ZERO, LANE3, T, V6, V7, V8, w[4])
+			}
+			VXOR(w[0], w[1], V5) // W'[i..i+3]
+			STXVD2X(w[0], wt[:])
+			STXVD2X(V5, wt1[:])
+			for j := 0; j < 4; j++ {
+				sm3.Round(i+j, &s, binary.BigEndian.Uint32(wt[4*j:]), binary.BigEndian.Uint32(wt1[4*j:]))
+			}
+			w = [5]*Vector128{w[1], w[2], w[3], w[4], w[0]}
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[64:]
+	}
+}
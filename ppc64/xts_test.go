@@ -60,54 +60,3 @@ func TestVSL(t *testing.T) {
 		t.Errorf("XC2 = %v; want 84000000000000000000000000000002", hex.EncodeToString(XC2.Bytes()))
 	}
 }
-
-func TestXTSMul2(t *testing.T) {
-	var (
-		B0     = Vector128{}
-		ESPERM = Vector128{}
-		POLY   = Vector128{}
-		T0     = Vector128{}
-		T1     = Vector128{}
-	)
-	rawBytes, _ := hex.DecodeString("F0F1F2F3F4F5F6F7F8F9FAFBFCFDFEFF")
-	LXVD2X(rawBytes, &B0)
-	LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, &ESPERM)
-	LXVD2X_UINT64([]uint64{0x0000000000000000, 0x0000000000000087}, &POLY)
-	VPERM(&B0, &B0, &ESPERM, &B0)
-	VSPLTB(0, &B0, &T0)
-	VSPLTISB(7, &T1)
-	VSRAB(&T0, &T1, &T0)
-	VAND(&POLY, &T0, &T0)
-	VSPLTISB(1, &T1)
-	VSL(&B0, &T1, &T1)
-	VXOR(&T0, &T1, &B0)
-	VPERM(&B0, &B0, &ESPERM, &B0)
-	if hex.EncodeToString(B0.Bytes()) != "67e3e5e7e9ebedeff1f3f5f7f9fbfdff" {
-		t.Errorf("B0 = %v; want 67e3e5e7e9ebedeff1f3f5f7f9fbfdff", hex.EncodeToString(B0.Bytes()))
-	}
-}
-
-func TestXTSMul2GB(t *testing.T) {
-	var (
-		B0     = Vector128{}
-		ESPERM = Vector128{}
-		POLY   = Vector128{}
-		T0     = Vector128{}
-		T1     = Vector128{}
-	)
-	rawBytes, _ := hex.DecodeString("F0F1F2F3F4F5F6F7F8F9FAFBFCFDFEFF")
-	LXVD2X(rawBytes, &B0)
-	LXVD2X_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, &ESPERM)
-	LXVD2X_UINT64([]uint64{0xe100000000000000, 0x0000000000000000}, &POLY)
-	VSPLTB(15, &B0, &T0)
-	VSPLTISB(7, &T1)
-	VSLB(&T0, &T1, &T0)
-	VSRAB(&T0, &T1, &T0)
-	VAND(&POLY, &T0, &T0)
-	VSPLTISB(1, &T1)
-	VSR(&B0, &T1, &B0)
-	VXOR(&T0, &B0, &B0)
-	if hex.EncodeToString(B0.Bytes()) != "9978f979fa7afb7bfc7cfd7dfe7eff7f" {
-		t.Errorf("B0 = %v; want 9978f979fa7afb7bfc7cfd7dfe7eff7f", hex.EncodeToString(B0.Bytes()))
-	}
-}
@@ -0,0 +1,233 @@
+package ppc64
+
+func EIA16Bytes(data []byte, keys []uint32, isPPC64LE bool) uint32 {
+	var (
+		XTMP1         Vector128
+		XTMP2         Vector128
+		XTMP3         Vector128
+		XTMP4         Vector128
+		XDATA         Vector128
+		XDIGEST       Vector128
+		KS_L          Vector128
+		KS_M1         Vector128
+		BIT_REV_TAB_L Vector128
+		BIT_REV_TAB_H Vector128
+	)
+	LXVD2X_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VSPLTISB(4, &XTMP2)
+	VSLB(&BIT_REV_TAB_L, &XTMP2, &BIT_REV_TAB_H)
+	//LXVD2X_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+
+	if isPPC64LE {
+		LXVD2X_PPC64LE(data, &XDATA)
+	} else {
+		LXVD2X(data, &XDATA)
+		// Change byte order (for PPC64)
+		LXVD2X_UINT64([]uint64{0x0706050403020100, 0x0f0e0d0c0b0a0908}, &XTMP1)
+		VPERM(&XDATA, &XDATA, &XTMP1, &XDATA)
+	}
+
+	VPERMXOR(&BIT_REV_TAB_L, &BIT_REV_TAB_H, &XDATA, &XTMP3)
+
+	// ZUC authentication part, 4x32 data bits
+	// setup data
+	VSPLTISW(0, &XTMP2)
+	LXVD2X_UINT64([]uint64{0x0000000010111213, 0x0000000014151617}, &XTMP4)
+	VPERM(&XTMP2, &XTMP3, &XTMP4, &XTMP1)
+	LXVD2X_UINT64([]uint64{0x0000000018191a1b, 0x000000001c1d1e1f}, &XTMP4)
+	VPERM(&XTMP2, &XTMP3, &XTMP4, &XTMP2)
+
+	// setup KS
+	LXVW4X_UINT32(keys, &KS_L)
+	LXVD2X_UINT64([]uint64{0x0405060708090a0b, 0x0001020304050607}, &XTMP4)
+	VPERM(&KS_L, &KS_L, &XTMP4, &KS_L)
+	LXVW4X_UINT32(keys[2:], &KS_M1)
+	VPERM(&KS_M1, &KS_M1, &XTMP4, &KS_M1)
+
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VPMSUMD(&XTMP1, &KS_L, &XTMP3)
+	VPMSUMD(&XTMP2, &KS_M1, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XTMP3)
+	VSPLTW(2, &XTMP3, &XDIGEST)
+
+	// use MFVSRWZ to get the lower 32 bits
+	return XDIGEST.Uint32s()[3]
+}
+
+// EIA256RoundTag8 returns the 64-bit tag with the first tag word in the high 32 bits.
+func EIA256RoundTag8(data []byte, keys []uint32, isPPC64LE bool) uint64 {
+	var (
+		XTMP1         Vector128
+		XTMP2         Vector128
+		XTMP3         Vector128
+		XTMP4         Vector128
+		XTMP5         Vector128
+		XTMP6         Vector128
+		XDATA         Vector128
+		XDIGEST       Vector128
+		ZERO          Vector128
+		KS_L          Vector128
+		KS_M1         Vector128
+		KS_M2         Vector128
+		BIT_REV_TAB_L Vector128
+		BIT_REV_TAB_H Vector128
+	)
+	LXVD2X_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VSPLTISB(4, &XTMP2)
+	VSLB(&BIT_REV_TAB_L, &XTMP2, &BIT_REV_TAB_H)
+	//LXVD2X_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+
+	if isPPC64LE {
+		LXVD2X_PPC64LE(data, &XDATA)
+	} else {
+		LXVD2X(data, &XDATA)
+		// Change byte order (for PPC64)
+		LXVD2X_UINT64([]uint64{0x0706050403020100, 0x0f0e0d0c0b0a0908}, &XTMP1)
+		VPERM(&XDATA, &XDATA, &XTMP1, &XDATA)
+	}
+
+	VPERMXOR(&BIT_REV_TAB_L, &BIT_REV_TAB_H, &XDATA, &XTMP3)
+
+	// ZUC authentication part, 4x32 data bits
+	// setup data
+	VSPLTISW(0, &ZERO)
+	LXVD2X_UINT64([]uint64{0x0000000010111213, 0x0000000014151617}, &XTMP4)
+	VPERM(&ZERO, &XTMP3, &XTMP4, &XTMP1)
+	LXVD2X_UINT64([]uint64{0x0000000018191a1b, 0x000000001c1d1e1f}, &XTMP4)
+	VPERM(&ZERO, &XTMP3, &XTMP4, &XTMP2)
+
+	VOR(&XTMP1, &XTMP1, &XTMP5)
+	VOR(&XTMP2, &XTMP2, &XTMP6)
+
+	// setup KS
+	LXVW4X_UINT32(keys, &KS_L)
+	LXVD2X_UINT64([]uint64{0x0405060708090a0b, 0x0001020304050607}, &XTMP4)
+	VPERM(&KS_L, &KS_L, &XTMP4, &KS_L)
+	LXVW4X_UINT32(keys[2:], &KS_M1)
+	VPERM(&KS_M1, &KS_M1, &XTMP4, &KS_M1)
+	LXVW4X_UINT32(keys[4:], &KS_M2)
+	VPERM(&KS_M2, &KS_M2, &XTMP4, &KS_M2)
+
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VPMSUMD(&XTMP1, &KS_L, &XTMP3)
+	VPMSUMD(&XTMP2, &KS_M1, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XDIGEST)
+	VSLDOI(12, &XDIGEST, &XDIGEST, &XDIGEST)
+
+	// Calculate upper 32 bits of tag
+	VOR(&XTMP5, &XTMP5, &XTMP1)
+	VOR(&XTMP6, &XTMP6, &XTMP2)
+
+	VSLDOI(8, &KS_M1, &KS_L, &KS_L)
+	VPMSUMD(&XTMP1, &KS_L, &XTMP3)
+	VSLDOI(8, &KS_M2, &KS_M1, &KS_M1)
+	VPMSUMD(&XTMP2, &KS_M1, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XTMP3)
+	VSLDOI(8, &XTMP3, &XTMP3, &XTMP3)
+	VSLDOI(4, &XDIGEST, &XTMP3, &XDIGEST)
+
+	return XDIGEST.Uint64s()[1]
+}
+
+// EIA256RoundTag16 returns the 128-bit tag as two big-endian 64-bit words.
+func EIA256RoundTag16(data []byte, keys []uint32, isPPC64LE bool) (uint64, uint64) {
+	var (
+		XTMP1         Vector128
+		XTMP2         Vector128
+		XTMP3         Vector128
+		XTMP4         Vector128
+		XTMP5         Vector128
+		XTMP6         Vector128
+		XDATA         Vector128
+		XDIGEST       Vector128
+		ZERO          Vector128
+		KS_L          Vector128
+		KS_M1         Vector128
+		KS_M2         Vector128
+		KS_H          Vector128
+		BIT_REV_TAB_L Vector128
+		BIT_REV_TAB_H Vector128
+	)
+	LXVD2X_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VSPLTISB(4, &XTMP2)
+	VSLB(&BIT_REV_TAB_L, &XTMP2, &BIT_REV_TAB_H)
+	//LXVD2X_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+
+	if isPPC64LE {
+		LXVD2X_PPC64LE(data, &XDATA)
+	} else {
+		LXVD2X(data, &XDATA)
+		// Change byte order (for PPC64)
+		LXVD2X_UINT64([]uint64{0x0706050403020100, 0x0f0e0d0c0b0a0908}, &XTMP1)
+		VPERM(&XDATA, &XDATA, &XTMP1, &XDATA)
+	}
+
+	VPERMXOR(&BIT_REV_TAB_L, &BIT_REV_TAB_H, &XDATA, &XTMP3)
+
+	// ZUC authentication part, 4x32 data bits
+	// setup data
+	VSPLTISW(0, &ZERO)
+	LXVD2X_UINT64([]uint64{0x0000000010111213, 0x0000000014151617}, &XTMP4)
+	VPERM(&ZERO, &XTMP3, &XTMP4, &XTMP1)
+	LXVD2X_UINT64([]uint64{0x0000000018191a1b, 0x000000001c1d1e1f}, &XTMP4)
+	VPERM(&ZERO, &XTMP3, &XTMP4, &XTMP2)
+
+	VOR(&XTMP1, &XTMP1, &XTMP5)
+	VOR(&XTMP2, &XTMP2, &XTMP6)
+
+	// setup KS
+	LXVW4X_UINT32(keys, &KS_L)
+	LXVD2X_UINT64([]uint64{0x0405060708090a0b, 0x0001020304050607}, &XTMP4)
+	VPERM(&KS_L, &KS_L, &XTMP4, &KS_L)
+	LXVW4X_UINT32(keys[2:], &KS_M1)
+	VPERM(&KS_M1, &KS_M1, &XTMP4, &KS_M1)
+	LXVW4X_UINT32(keys[4:], &KS_M2)
+	VOR(&KS_M2, &KS_M2, &KS_H)
+	VPERM(&KS_M2, &KS_M2, &XTMP4, &KS_M2)
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VPMSUMD(&XTMP1, &KS_L, &XTMP3)
+	VPMSUMD(&XTMP2, &KS_M1, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XDIGEST)
+	VSLDOI(12, &XDIGEST, &XDIGEST, &XDIGEST)
+
+	// Calculate upper 32 bits of tag
+	VOR(&XTMP5, &XTMP5, &XTMP1)
+	VOR(&XTMP6, &XTMP6, &XTMP2)
+
+	VSLDOI(8, &KS_M1, &KS_L, &KS_L)
+	VPMSUMD(&XTMP1, &KS_L, &XTMP3)
+	VSLDOI(8, &KS_M2, &KS_M1, &XTMP1)
+	VPMSUMD(&XTMP2, &XTMP1, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XTMP3)
+
+	VSLDOI(8, &XTMP3, &XTMP3, &XTMP3)
+	VSLDOI(4, &XDIGEST, &XTMP3, &XDIGEST)
+
+	// Prepare data and calculate bits 95-64 of tag
+	VOR(&XTMP5, &XTMP5, &XTMP1)
+	VOR(&XTMP6, &XTMP6, &XTMP2)
+	VPMSUMD(&XTMP1, &KS_M1, &XTMP3)
+	VPMSUMD(&XTMP2, &KS_M2, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XTMP3)
+	VSLDOI(8, &XTMP3, &XTMP3, &XTMP3)
+	VSLDOI(4, &XDIGEST, &XTMP3, &XDIGEST)
+
+	// Prepare data and calculate bits 127-96 of tag
+	VOR(&XTMP5, &XTMP5, &XTMP1)
+	VOR(&XTMP6, &XTMP6, &XTMP2)
+	VSLDOI(8, &KS_M2, &KS_M1, &KS_M1)
+	VPMSUMD(&XTMP1, &KS_M1, &XTMP3)
+	VSLDOI(8, &KS_H, &KS_M2, &KS_M2)
+	VPMSUMD(&XTMP2, &KS_M2, &XTMP4)
+	VXOR(&XTMP3, &XTMP4, &XTMP3)
+	VSLDOI(8, &XTMP3, &XTMP3, &XTMP3)
+	VSLDOI(4, &XDIGEST, &XTMP3, &XDIGEST)
+
+	return XDIGEST.Uint64s()[0], XDIGEST.Uint64s()[1]
+}
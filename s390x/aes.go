@@ -0,0 +1,83 @@
+package s390x
+
+import (
+	"crypto/cipher"
+
+	"github.com/emmansun/simd/alg/aes"
+)
+
+// The CPACF KM (cipher message) function codes of AES, the parameter block is the key.
+const (
+	KM_AES_128 = 18
+	KM_AES_192 = 19
+	KM_AES_256 = 20
+	// KM_DECRYPT is the modifier bit of the function code for the decryption.
+	KM_DECRYPT = 0x80
+)
+
+// KM is a reference model of the CPACF KM instruction for the AES function codes, it encrypts
+// (or decrypts with KM_DECRYPT) each block of src to dst in the ECB mode. The length of src must
+// be a multiple of 16.
+func KM(fc int, param, dst, src []byte) {
+	if len(src)%16 != 0 {
+		panic("KM: src is not a multiple of 16 bytes")
+	}
+	var keyLen int
+	switch fc &^ KM_DECRYPT {
+	case KM_AES_128:
+		keyLen = 16
+	case KM_AES_192:
+		keyLen = 24
+	case KM_AES_256:
+		keyLen = 32
+	default:
+		panic("KM: unsupported function code")
+	}
+	rk, _ := aes.KeyExpansion(param[:keyLen])
+	for i := 0; i < len(src); i += 16 {
+		if fc&KM_DECRYPT != 0 {
+			aes.DecryptBlock(rk, dst[i:], src[i:])
+		} else {
+			aes.EncryptBlock(rk, dst[i:], src[i:])
+		}
+	}
+}
+
+type kmAESCipher struct {
+	function int
+	key      []byte
+}
+
+// NewAESCipher returns the AES-128/192/256 cipher on top of KM.
+func NewAESCipher(key []byte) (cipher.Block, error) {
+	var function int
+	switch len(key) {
+	case 16:
+		function = KM_AES_128
+	case 24:
+		function = KM_AES_192
+	case 32:
+		function = KM_AES_256
+	default:
+		return nil, aes.KeySizeError(len(key))
+	}
+	return &kmAESCipher{function: function, key: append([]byte(nil), key...)}, nil
+}
+
+func (c *kmAESCipher) BlockSize() int {
+	return 16
+}
+
+func (c *kmAESCipher) Encrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("s390x: input not full block")
+	}
+	KM(c.function, c.key, dst[:16], src[:16])
+}
+
+func (c *kmAESCipher) Decrypt(dst, src []byte) {
+	if len(src) < 16 || len(dst) < 16 {
+		panic("s390x: input not full block")
+	}
+	KM(c.function|KM_DECRYPT, c.key, dst[:16], src[:16])
+}
@@ -0,0 +1,34 @@
+package s390x
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/internal/aestest"
+)
+
+func TestAES(t *testing.T) {
+	aestest.TestCipher(t, NewAESCipher)
+}
+
+func TestKM(t *testing.T) {
+	key, _ := hex.DecodeString(aestest.Vectors[0].Key)
+	in, _ := hex.DecodeString(aestest.Vectors[0].In)
+	out, _ := hex.DecodeString(aestest.Vectors[0].Out)
+	src := append(append([]byte{}, in...), out...)
+	dst := make([]byte, 32)
+	KM(KM_AES_128, key, dst, src)
+	c, _ := aes.NewCipher(key)
+	want := make([]byte, 32)
+	c.Encrypt(want, in)
+	c.Encrypt(want[16:], out)
+	if !bytes.Equal(dst, want) {
+		t.Errorf("KM-AES-128 got %x, want %x", dst, want)
+	}
+	KM(KM_AES_128|KM_DECRYPT, key, dst, dst)
+	if !bytes.Equal(dst, src) {
+		t.Errorf("KM-AES-128 decrypt got %x, want %x", dst, src)
+	}
+}
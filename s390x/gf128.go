@@ -0,0 +1,105 @@
+package s390x
+
+// GF128MulXBE multiplies B by x in the big endian convention (CMAC, OCB).
+// T0, T1 are temporary registers.
+func GF128MulXBE(B, T0, T1 *Vector128) {
+	POLY := &Vector128{}
+	VZERO(POLY)
+	VLEIB(15, 0x87, POLY)
+	VESRAF(31, B, T0) // broadcast the carry bit
+	VREPF(0, T0, T0)
+	VN(POLY, T0, T0)
+	VREPIB(1, T1)
+	VSL(T1, B, T1) // B << 1
+	VX(T0, T1, B)
+}
+
+// GF128MulXLE multiplies B by x in the little endian convention (IEEE 1619 XTS).
+// T0, T1 are temporary registers.
+func GF128MulXLE(B, T0, T1 *Vector128) {
+	ESPERM := &Vector128{}
+	VL_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, ESPERM)
+	VPERM(B, B, ESPERM, B)
+	GF128MulXBE(B, T0, T1)
+	VPERM(B, B, ESPERM, B)
+}
+
+// GF128MulXGB multiplies B by x in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value with the reduction 0xe1 << 120.
+// T0, T1 are temporary registers.
+func GF128MulXGB(B, T0, T1 *Vector128) {
+	POLY := &Vector128{}
+	VZERO(POLY)
+	VLEIB(0, 0xe1, POLY)
+	VESLF(31, B, T0)   // move the carry bit to the most significant bit
+	VESRAF(31, T0, T0) // broadcast it
+	VREPF(3, T0, T0)
+	VN(POLY, T0, T0)
+	VREPIB(1, T1)
+	VSRL(T1, B, T1) // B >> 1
+	VX(T0, T1, B)
+}
+
+// checkMulXPow panics unless 1 <= k <= 8, the k bits shifted out of the block fit in a byte.
+func checkMulXPow(k int) {
+	if k < 1 || k > 8 {
+		panic("s390x: GF128MulXPow k out of range")
+	}
+}
+
+// GF128MulXPowBE multiplies B by x^k, 1 <= k <= 8, in the big endian convention (CMAC, OCB).
+// VSL shifts by at most 7 bits, x^8 is a byte shift. T0, T1 are temporary registers.
+func GF128MulXPowBE(B, T0, T1 *Vector128, k int) {
+	checkMulXPow(k)
+	POLY := &Vector128{}
+	ZERO := &Vector128{}
+	VZERO(POLY)
+	VLEIB(15, 0x87, POLY)
+	VZERO(ZERO)
+	VSLDB(1, ZERO, B, T0) // the most significant byte at the bottom
+	VREPIB(uint8(8-k), T1)
+	VSRL(T1, T0, T0)    // the bits shifted out
+	VGFMG(T0, POLY, T0) // ·0x87
+	if k == 8 {
+		VSLDB(1, B, ZERO, B)
+	} else {
+		VREPIB(uint8(k), T1)
+		VSL(T1, B, B)
+	}
+	VX(T0, B, B)
+}
+
+// GF128MulXPowLE multiplies B by x^k, 1 <= k <= 8, in the little endian convention (IEEE 1619 XTS).
+// T0, T1 are temporary registers.
+func GF128MulXPowLE(B, T0, T1 *Vector128, k int) {
+	ESPERM := &Vector128{}
+	VL_UINT64([]uint64{0x0f0e0d0c0b0a0908, 0x0706050403020100}, ESPERM)
+	VPERM(B, B, ESPERM, B)
+	GF128MulXPowBE(B, T0, T1, k)
+	VPERM(B, B, ESPERM, B)
+}
+
+// GF128MulXPowGB multiplies B by x^k, 1 <= k <= 8, in the GB (bit reflected) convention (GB/T 17964 XTS, GHASH),
+// it is a right shift of the big endian value by k bits, the bits shifted out are reduced with 0xe1 << (121 - k).
+// T0, T1 are temporary registers.
+func GF128MulXPowGB(B, T0, T1 *Vector128, k int) {
+	checkMulXPow(k)
+	MASK := &Vector128{}
+	POLY := &Vector128{}
+	ZERO := &Vector128{}
+	VZERO(MASK)
+	VLEIG(1, 1<<k-1, MASK)
+	VZERO(POLY)
+	VLEIG(1, 0xe1<<(57-k), POLY)
+	VZERO(ZERO)
+	VN(B, MASK, T0)        // the bits shifted out
+	VGFMG(T0, POLY, T0)    // ·0xe1 << (57 - k)
+	VSLDB(8, T0, ZERO, T0) // to the high doubleword
+	if k == 8 {
+		VSLDB(15, ZERO, B, B)
+	} else {
+		VREPIB(uint8(k), T1)
+		VSRL(T1, B, B)
+	}
+	VX(T0, B, B)
+}
@@ -0,0 +1,268 @@
+// The same algorithm as the ppc64 kernel, VGFMG is the counterpart of VPMSUMD.
+
+package s390x
+
+import "encoding/binary"
+
+type clmulS390XGhash struct {
+	aggregation       int
+	bytesProductTable []byte // (aggregation * 3 + 1) * 16
+}
+
+// defaultAggregation is the number of blocks processed by a reduction.
+const defaultAggregation = 8
+
+// checkAggregation panics if n is not a supported aggregation depth.
+func checkAggregation(n int) {
+	switch n {
+	case 1, 2, 4, 8, 16:
+	default:
+		panic("s390x: unsupported GHASH aggregation depth")
+	}
+}
+
+func NewClmulS390XGhash(h []byte) *clmulS390XGhash {
+	return newClmulS390XGhash(h, defaultAggregation)
+}
+
+// NewClmulS390XGhashWithAggregation returns the kernel which aggregates n (1, 2, 4, 8 or 16) blocks
+// per reduction, the precomputed table holds n powers of H.
+func NewClmulS390XGhashWithAggregation(h []byte, n int) *clmulS390XGhash {
+	checkAggregation(n)
+	return newClmulS390XGhash(h, n)
+}
+
+func newClmulS390XGhash(h []byte, n int) *clmulS390XGhash {
+	g := &clmulS390XGhash{aggregation: n, bytesProductTable: make([]byte, (n*3+1)*16)}
+	var (
+		POLY = &Vector128{}
+		XC2  = &Vector128{}
+		T0   = &Vector128{}
+		T1   = &Vector128{}
+		ZERO = &Vector128{}
+		H    = &Vector128{}
+		HL   = &Vector128{}
+		HH   = &Vector128{}
+		IN   = &Vector128{}
+		XL   = &Vector128{}
+		XM   = &Vector128{}
+		XH   = &Vector128{}
+	)
+	VL(h, H)
+	VZERO(ZERO)
+	VLEIG(0, 0xc200000000000000, POLY)
+	VLEIG(1, 1, POLY)
+
+	// Multiply by 2 modulo P
+	VREPIB(1, T0)
+	VESRAG(63, H, T1)
+	VPDI(0, T1, T1, T1) // broadcast carry bit
+	VN(T1, POLY, T1)
+	VSL(T0, H, H) // H<<=1
+	VX(H, T1, IN) // twisted H
+	g.splitH(IN, H, HL, HH, ZERO)
+
+	VPDI(6, ZERO, POLY, XC2) // 0.0xc2
+	VST(XC2, g.bytesProductTable[(3*n)*16:])
+	VST(HL, g.bytesProductTable[(3*n-3)*16:])
+	VST(H, g.bytesProductTable[(3*n-2)*16:])
+	VST(HH, g.bytesProductTable[(3*n-1)*16:])
+
+	for i := n - 2; i >= 0; i-- {
+		// Multiplication
+		VGFMG(IN, HL, XL) // H.lo·H.lo
+		VGFMG(IN, H, XM)  // H.hi·H.lo+H.lo·H.hi
+		VGFMG(IN, HH, XH) // H.hi·H.hi
+
+		g.processClmulResult(XL, XM, XH, ZERO, T0)
+		g.fastReduction(IN, XH, XL, T0, XC2)
+
+		g.splitH(IN, T1, XL, XH, ZERO)
+		VST(XL, g.bytesProductTable[(3*i)*16:])
+		VST(T1, g.bytesProductTable[(3*i+1)*16:])
+		VST(XH, g.bytesProductTable[(3*i+2)*16:])
+	}
+	return g
+}
+
+// splitH prepares IN for VGFMG: H = [IN.lo, IN.hi], HL = [0, IN.lo], HH = [IN.hi, 0].
+func (g *clmulS390XGhash) splitH(IN, H, HL, HH, ZERO *Vector128) {
+	VPDI(4, IN, IN, H)
+	VPDI(6, ZERO, H, HL)
+	VPDI(6, H, ZERO, HH)
+}
+
+func (g *clmulS390XGhash) Hash(T *[16]byte, data []byte) {
+	var (
+		XC2  = &Vector128{}
+		T0   = &Vector128{}
+		ZERO = &Vector128{}
+		H    = &Vector128{}
+		HL   = &Vector128{}
+		HH   = &Vector128{}
+		ACC0 = &Vector128{}
+		ACC1 = &Vector128{}
+		ACCM = &Vector128{}
+		B0   = &Vector128{}
+		B    = make([]Vector128, g.aggregation)
+	)
+	VZERO(ZERO)
+	VZERO(ACC0)
+	g.loadPrecomputed(3*g.aggregation, XC2)
+
+	// handle aggregation blocks at a time
+	for len(data) >= 16*g.aggregation {
+		// load blocks
+		for i := range B {
+			VL(data[16*i:], &B[i])
+		}
+
+		// load precomputed values
+		g.loadPrecomputed(0, HL)
+		g.loadPrecomputed(1, H)
+		g.loadPrecomputed(2, HH)
+
+		// process first block
+		// add previous result
+		VX(&B[0], ACC0, &B[0])
+		// multiplication
+		VGFMG(&B[0], HL, ACC0)
+		VGFMG(&B[0], H, ACCM)
+		VGFMG(&B[0], HH, ACC1)
+
+		for i := 1; i < g.aggregation; i++ {
+			g.mulRoundAAD(&B[i], ACC0, ACC1, ACCM, HL, H, HH, i)
+		}
+
+		g.processClmulResult(ACC0, ACCM, ACC1, ZERO, T0)
+		g.fastReduction(ACC0, ACC1, ACC0, T0, XC2)
+
+		data = data[16*g.aggregation:]
+	}
+
+	// load precomputed values
+	g.loadPrecomputed(3*g.aggregation-3, HL)
+	g.loadPrecomputed(3*g.aggregation-2, H)
+	g.loadPrecomputed(3*g.aggregation-1, HH)
+
+	// handle one block at a time
+	for len(data) >= 16 {
+		VL(data, B0)
+		g.mulOneBlock(B0, ACC0, ACCM, ACC1, HL, H, HH, T0, XC2, ZERO)
+		data = data[16:]
+	}
+	if len(data) > 0 {
+		var partialBlock [16]byte
+		copy(partialBlock[:], data)
+		VL(partialBlock[:], B0)
+		g.mulOneBlock(B0, ACC0, ACCM, ACC1, HL, H, HH, T0, XC2, ZERO)
+	}
+	VST(ACC0, T[:])
+}
+
+func (g *clmulS390XGhash) mulOneBlock(B0, ACC0, ACCM, ACC1, HL, H, HH, T0, XC2, ZERO *Vector128) {
+	VX(B0, ACC0, B0)
+	// Multiplication
+	VGFMG(B0, HL, ACC0)
+	VGFMG(B0, H, ACCM)
+	VGFMG(B0, HH, ACC1)
+	g.processClmulResult(ACC0, ACCM, ACC1, ZERO, T0)
+	g.fastReduction(ACC0, ACC1, ACC0, T0, XC2)
+}
+
+// Multiply IN by H^(n-i) and accumulate the result in ACC0, ACC1, ACCM
+// T0, T1, T2 are temporary registers
+func (g *clmulS390XGhash) mulRoundAAD(IN, ACC0, ACC1, ACCM, T0, T1, T2 *Vector128, i int) {
+	// load precomputed values
+	g.loadPrecomputed(3*i, T0)   // H2L
+	g.loadPrecomputed(3*i+1, T1) // H2
+	g.loadPrecomputed(3*i+2, T2) // H2H
+
+	// Multiplication and accumulate
+	VGFMAG(IN, T0, ACC0, ACC0)
+	VGFMAG(IN, T1, ACCM, ACCM)
+	VGFMAG(IN, T2, ACC1, ACC1)
+}
+
+func (g *clmulS390XGhash) loadPrecomputed(i int, T *Vector128) {
+	VL(g.bytesProductTable[i*16:], T)
+}
+
+func (g *clmulS390XGhash) processClmulResult(ACC0, ACCM, ACC1, ZERO, T *Vector128) {
+	VPDI(6, ACCM, ZERO, T) // T = ACCM.lo || zero
+	VX(ACC0, T, ACC0)      // ACC0 = ACC0 ^ (ACCM.lo || zero)
+	VPDI(6, ZERO, ACCM, T) // T = zero || ACCM.hi
+	VX(ACC1, T, ACC1)      // ACC1 = ACC1 ^ (zero || ACCM.hi)
+}
+
+// Fast reduction for [ACC1:ACC0] by POLY and store the result in TARGET
+// T is a temporary register
+func (g *clmulS390XGhash) fastReduction(TARGET, ACC1, ACC0, T, POLY *Vector128) {
+	VGFMG(ACC0, POLY, T) // POLY.hi = 0
+	VPDI(4, ACC0, ACC0, ACC0)
+	VX(ACC0, T, ACC0)
+	VGFMG(ACC0, POLY, T) // POLY.hi = 0
+	VPDI(4, ACC0, ACC0, ACC0)
+	VX(ACC0, T, ACC0)
+	VX(ACC0, ACC1, TARGET)
+}
+
+// KIMD_GHASH is a reference model of the CPACF KIMD-GHASH function (function code 65).
+// The parameter block is the 16 bytes ICV followed by the 16 bytes hash subkey H,
+// each block of src updates ICV = (ICV ^ block) · H. The length of src must be a multiple of 16.
+func KIMD_GHASH(param *[32]byte, src []byte) {
+	if len(src)%16 != 0 {
+		panic("KIMD_GHASH: src is not a multiple of 16 bytes")
+	}
+	h0 := binary.BigEndian.Uint64(param[16:])
+	h1 := binary.BigEndian.Uint64(param[24:])
+	y0 := binary.BigEndian.Uint64(param[0:])
+	y1 := binary.BigEndian.Uint64(param[8:])
+	for ; len(src) > 0; src = src[16:] {
+		y0 ^= binary.BigEndian.Uint64(src)
+		y1 ^= binary.BigEndian.Uint64(src[8:])
+		// bit by bit multiplication, bit 0 is the most significant bit
+		var z0, z1 uint64
+		v0, v1 := h0, h1
+		for i := 0; i < 128; i++ {
+			word := y0
+			if i >= 64 {
+				word = y1
+			}
+			mask := -((word >> (63 - i&63)) & 1)
+			z0 ^= v0 & mask
+			z1 ^= v1 & mask
+			// v = v · x
+			carry := -(v1 & 1)
+			v1 = v1>>1 | v0<<63
+			v0 = v0>>1 ^ (carry & 0xe100000000000000)
+		}
+		y0, y1 = z0, z1
+	}
+	binary.BigEndian.PutUint64(param[0:], y0)
+	binary.BigEndian.PutUint64(param[8:], y1)
+}
+
+type kimdGhash struct {
+	key [16]byte
+}
+
+// NewKIMDGhash returns the GHASH method on top of KIMD-GHASH.
+func NewKIMDGhash(h []byte) *kimdGhash {
+	g := &kimdGhash{}
+	copy(g.key[:], h)
+	return g
+}
+
+func (g *kimdGhash) Hash(T *[16]byte, data []byte) {
+	var param [32]byte
+	copy(param[16:], g.key[:])
+	n := len(data) &^ 15
+	KIMD_GHASH(&param, data[:n])
+	if n < len(data) {
+		var partialBlock [16]byte
+		copy(partialBlock[:], data[n:])
+		KIMD_GHASH(&param, partialBlock[:])
+	}
+	copy(T[:], param[:16])
+}
@@ -0,0 +1,114 @@
+package s390x
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/simd/alg/ghash"
+	"github.com/emmansun/simd/internal/ghashtest"
+)
+
+var ghashCases = []struct {
+	key  string
+	data string
+}{
+	{
+		"66e94bd4ef8a2c3b884cfa59ca342b2e",
+		"48af93501fa62adbcd414cce6034d8",
+	},
+	{
+		"66e94bd4ef8a2c3b884cfa59ca342b2e",
+		"48af93501fa62adbcd414cce6034d89587",
+	},
+	{
+		"66e94bd4ef8a2c3b884cfa59ca342b2e",
+		"48af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e7483094fd12e518ce062c98acee28d95df4416bed31a2f04476c18bb40c84a74b97dc5b16842d4fa186f56ab33256971fa110f4",
+	},
+	{
+		"66e94bd4ef8a2c3b884cfa59ca342b2e",
+		"48af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e7483094fd12e518ce062c98acee28d95df4416bed31a2f04476c18bb40c84a74b97dc5b16842d4fa186f56ab33256971fa110f448af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e7483094fd12e518ce062c98acee28d95df4416bed31a2f04476c18bb40c84a74b97dc5b16842d4fa186f56ab33256971fa110f4abcd",
+	},
+	// keys with the top bit set exercise the carry of the key doubling
+	{
+		"b83b533708bf535d0aa6e52980d53b78",
+		"48af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e7483094fd12e518ce062c98acee28d95df4416bed31a2f04476c18bb40c84a74b97dc5b16842d4fa186f56ab33256971fa110f4",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"48af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e7483094fd12e518ce062c98acee28d95df4416bed31a2f04476c18bb40c84a74b97dc5b16842d4fa186f56ab33256971fa110f4abcd",
+	},
+	{
+		"80000000000000000000000000000001",
+		"48af93501fa62adbcd414cce6034d895dda1bf8f132f042098661572e74830",
+	},
+}
+
+func TestS390XGHash(t *testing.T) {
+	for i, c := range ghashCases {
+		key, _ := hex.DecodeString(c.key)
+		data, _ := hex.DecodeString(c.data)
+		var T0 [16]byte
+		ghash.NewGCMMethod(key).Hash(&T0, data)
+		for name, g := range map[string]ghash.Hasher{"vgfm": NewClmulS390XGhash(key), "kimd": NewKIMDGhash(key)} {
+			var T [16]byte
+			g.Hash(&T, data)
+			if !bytes.Equal(T[:], T0[:]) {
+				t.Errorf("case %d %s: got %v, want %v", i, name, hex.EncodeToString(T[:]), hex.EncodeToString(T0[:]))
+			}
+		}
+	}
+}
+
+func TestS390XGHashStream(t *testing.T) {
+	for i, c := range ghashCases {
+		key, _ := hex.DecodeString(c.key)
+		data, _ := hex.DecodeString(c.data)
+		aad, ct := data[:len(data)/3], data[len(data)/3:]
+		s1 := ghash.NewStream(NewClmulS390XGhash(key))
+		s2 := ghash.NewStream(ghash.NewGCMMethod(key))
+		s1.WriteAAD(aad[:len(aad)/2])
+		s1.WriteAAD(aad[len(aad)/2:])
+		for p := ct; len(p) > 0; p = p[min(7, len(p)):] {
+			s1.Write(p[:min(7, len(p))])
+		}
+		s2.WriteAAD(aad)
+		s2.Write(ct)
+		T1, T2 := s1.Sum(nil), s2.Sum(nil)
+		if !bytes.Equal(T1, T2) {
+			t.Errorf("case %d: got %v, want %v", i, hex.EncodeToString(T1), hex.EncodeToString(T2))
+		}
+	}
+}
+
+func TestKIMDGHASH(t *testing.T) {
+	// GHASH(H, A, C) of the GCM spec test case 2, the ICV is chained over two calls.
+	var param [32]byte
+	h, _ := hex.DecodeString("66e94bd4ef8a2c3b884cfa59ca342b2e")
+	copy(param[16:], h)
+	c, _ := hex.DecodeString("0388dace60b6a392f328c2b971b2fe78")
+	KIMD_GHASH(&param, c)
+	KIMD_GHASH(&param, []byte{15: 0x80})
+	if got, want := hex.EncodeToString(param[:16]), "f38cbb1ad69223dcc3457ae5b6b0f885"; got != want {
+		t.Errorf("KIMD_GHASH = %v; want %v", got, want)
+	}
+	if !bytes.Equal(param[16:], h) {
+		t.Errorf("KIMD_GHASH changed H")
+	}
+}
+
+func newS390XAggregation(key []byte, n int) ghash.Hasher {
+	return NewClmulS390XGhashWithAggregation(key, n)
+}
+
+func TestS390XGHashAggregation(t *testing.T) {
+	ghashtest.TestAggregation(t, newS390XAggregation)
+}
+
+func TestS390XGHashCounts(t *testing.T) {
+	ghashtest.TestCounts(t, newS390XAggregation, SetCounter)
+}
+
+func BenchmarkS390XGHashAggregation(b *testing.B) {
+	ghashtest.BenchmarkAggregation(b, newS390XAggregation, SetCounter)
+}
@@ -1,15 +1,93 @@
 package s390x
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/gf2"
+	"github.com/emmansun/simd/internal/opcount"
+)
 
 type Vector128 struct {
 	bytes [16]byte
+	sym   *gf2.Vec // the symbolic bits of the register, nil if it is not symbolic, a pointer keeps Vector128 comparable
 }
 
 func (m *Vector128) Bytes() []byte {
 	return m.bytes[:]
 }
 
+// SetSymbolic makes dst symbolic with the bits v and sets the bytes to x, bit 8i+k of v is bit k of byte i
+// in the register order, the big endian order of the elements. VX, VPDI and VGFMG with a constant operand
+// carry the symbolic bits, so a GF(2) linear kernel run on symbolic registers computes its linear map,
+// see internal/gf2.
+func SetSymbolic(dst *Vector128, v gf2.Vec, x []byte) {
+	copy(dst.bytes[:], x)
+	dst.setSymbolic(v)
+}
+
+// Symbolic returns the symbolic bits of m in the order of SetSymbolic, nil if m is not symbolic.
+func (m *Vector128) Symbolic() gf2.Vec {
+	if m.sym == nil {
+		return nil
+	}
+	return *m.sym
+}
+
+// counter records the executed instructions, see SetCounter.
+var counter *opcount.Counter
+
+// SetCounter makes the instructions of the GHASH kernel (VL, VST, VLEIG, VREPIB, VZERO, VN, VX,
+// VSL, VESRAG, VPDI, VGFMG and VGFMAG) record themselves into c, nil stops the recording.
+// The recording is not safe for concurrent use.
+func SetCounter(c *opcount.Counter) {
+	counter = c
+}
+
+// tracer reports the table lookups of the executed instructions, see SetTracer.
+var tracer *ctcheck.Tracer
+
+// SetTracer makes VPERM report the indices of its table lookup, the permute control, to t, nil
+// stops the reporting. The reporting is not safe for concurrent use.
+func SetTracer(t *ctcheck.Tracer) {
+	tracer = t
+}
+
+// lookup reports the indices idx of the table lookup of the instruction name.
+func lookup(name string, idx []byte) {
+	if tracer == nil {
+		return
+	}
+	for _, i := range idx {
+		tracer.Lookup(name, int(i))
+	}
+}
+
+// setSymbolic sets the symbolic bits of m to v, m is not symbolic if v is nil.
+func (m *Vector128) setSymbolic(v gf2.Vec) {
+	m.sym = nil
+	if v != nil {
+		m.sym = &v
+	}
+}
+
+// symbolic returns the symbolic bits of the operands, the constant ones must be zero, nil if none is symbolic.
+func symbolic(ms ...*Vector128) []gf2.Vec {
+	for _, m := range ms {
+		if m.sym != nil {
+			v := make([]gf2.Vec, len(ms))
+			for i, m := range ms {
+				v[i] = m.Symbolic()
+				if v[i] == nil {
+					v[i] = gf2.Const(m.bytes[:])
+				}
+			}
+			return v
+		}
+	}
+	return nil
+}
+
 func (m *Vector128) Uint64s() []uint64 {
 	return []uint64{binary.BigEndian.Uint64(m.bytes[:]), binary.BigEndian.Uint64(m.bytes[8:])}
 }
@@ -19,7 +97,9 @@ func (m *Vector128) Uint32s() []uint32 {
 }
 
 func VL(rawbytes []byte, dst *Vector128) {
+	counter.Op("VL", dst)
 	copy(dst.bytes[:], rawbytes)
+	dst.sym = nil
 }
 
 func VL_UINT64(ints []uint64, dst *Vector128) {
@@ -35,11 +115,13 @@ func VL_UINT32(ints []uint32, dst *Vector128) {
 }
 
 func VST(src *Vector128, dst []byte) {
+	counter.Op("VST", nil, src)
 	copy(dst, src.bytes[:])
 }
 
 // AND
 func VN(src1, src2, dst *Vector128) {
+	counter.Op("VN", dst, src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] & src2.bytes[i]
 	}
@@ -47,9 +129,20 @@ func VN(src1, src2, dst *Vector128) {
 
 // XOR
 func VX(src1, src2, dst *Vector128) {
+	counter.Op("VX", dst, src1, src2)
+	vx(src1, src2, dst)
+}
+
+// vx is VX without the recording, for the multiply sum and accumulate instructions.
+func vx(src1, src2, dst *Vector128) {
+	v := symbolic(src1, src2)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = src1.bytes[i] ^ src2.bytes[i]
 	}
+	dst.sym = nil
+	if v != nil {
+		dst.setSymbolic(v[0].Add(v[1]))
+	}
 }
 
 func VO(src1, src2, dst *Vector128) {
@@ -58,7 +151,24 @@ func VO(src1, src2, dst *Vector128) {
 	}
 }
 
+// Vector Select, selects the bits of src1 where the bits of mask are set and the bits of src2 otherwise.
+func VSEL(src1, src2, mask, dst *Vector128) {
+	for i := 0; i < 16; i++ {
+		dst.bytes[i] = src1.bytes[i]&mask.bytes[i] | src2.bytes[i]&^mask.bytes[i]
+	}
+}
+
+// Vector Shift Left Double By Byte, dst is the bytes imm..imm+15 of src1 || src2.
+func VSLDB(imm uint8, src1, src2, dst *Vector128) {
+	imm = imm & 0x0f
+	tmp := Vector128{}
+	copy(tmp.bytes[:], src1.bytes[imm:])
+	copy(tmp.bytes[16-imm:], src2.bytes[:imm])
+	copy(dst.bytes[:], tmp.bytes[:])
+}
+
 func VPERM(src1, src2, perm, dst *Vector128) {
+	lookup("VPERM", perm.bytes[:])
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
 		idx := perm.bytes[i] & 0x1f
@@ -72,6 +182,7 @@ func VPERM(src1, src2, perm, dst *Vector128) {
 }
 
 func VREPIB(imm uint8, dst *Vector128) {
+	counter.Op("VREPIB", dst)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = imm
 	}
@@ -107,6 +218,7 @@ func VREPF(idx uint8, src, dst *Vector128) {
 }
 
 func VZERO(dst *Vector128) {
+	counter.Op("VZERO", dst)
 	for i := 0; i < 16; i++ {
 		dst.bytes[i] = 0
 	}
@@ -114,10 +226,11 @@ func VZERO(dst *Vector128) {
 
 // Vector Shift Left
 func VSL(indicator, src, dst *Vector128) {
-	sh := indicator.bytes[15] & 0x03
+	counter.Op("VSL", dst, src, indicator)
+	sh := indicator.bytes[15] & 0x07
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
-		ind := indicator.bytes[i] & 0x03
+		ind := indicator.bytes[i] & 0x07
 		if sh != ind {
 			panic("VSL: shift amount must be the same for all bytes")
 		}
@@ -132,10 +245,10 @@ func VSL(indicator, src, dst *Vector128) {
 
 // Vector Shift Right Logical
 func VSRL(indicator, src, dst *Vector128) {
-	sh := indicator.bytes[15] & 0x03
+	sh := indicator.bytes[15] & 0x07
 	tmp := Vector128{}
 	for i := 0; i < 16; i++ {
-		ind := indicator.bytes[i] & 0x03
+		ind := indicator.bytes[i] & 0x07
 		if sh != ind {
 			panic("VSRL: shift amount must be the same for all bytes")
 		}
@@ -186,13 +299,14 @@ func VESRAF(imm uint8, src, dst *Vector128) {
 
 // Vector Element(Double Word) Shift Right Arithmetic
 func VESRAG(imm uint8, src, dst *Vector128) {
+	counter.Op("VESRAG", dst, src)
 	if imm > 63 {
 		imm = 63
 	}
 	for i := 0; i < 2; i++ {
-		tmp := int64(binary.BigEndian.Uint64(src.bytes[i*2:]))
+		tmp := int64(binary.BigEndian.Uint64(src.bytes[i*8:]))
 		tmp >>= imm
-		binary.BigEndian.PutUint64(dst.bytes[i*4:], uint64(tmp))
+		binary.BigEndian.PutUint64(dst.bytes[i*8:], uint64(tmp))
 	}
 }
 
@@ -349,7 +463,7 @@ func VLEIH(idx uint8, value uint16, dst *Vector128) {
 	if idx > 7 {
 		idx = 7
 	}
-	binary.BigEndian.PutUint16(dst.bytes[idx:], value)
+	binary.BigEndian.PutUint16(dst.bytes[2*idx:], value)
 }
 
 // Vector Load Element Immediate (Word)
@@ -357,15 +471,16 @@ func VLEIF(idx uint8, value uint32, dst *Vector128) {
 	if idx > 3 {
 		idx = 3
 	}
-	binary.BigEndian.PutUint32(dst.bytes[idx:], value)
+	binary.BigEndian.PutUint32(dst.bytes[4*idx:], value)
 }
 
 // Vector Load Element Immediate (Doubleword)
 func VLEIG(idx uint8, value uint64, dst *Vector128) {
+	counter.Op("VLEIG", dst, dst)
 	if idx > 1 {
 		idx = 1
 	}
-	binary.BigEndian.PutUint64(dst.bytes[idx:], value)
+	binary.BigEndian.PutUint64(dst.bytes[8*idx:], value)
 }
 
 // Vector Subsctraction
@@ -542,11 +657,18 @@ func VMRLF(src1, src2, dst *Vector128) {
 }
 
 func VPDI(imm8 byte, src1, src2, dst *Vector128) {
+	counter.Op("VPDI", dst, src1, src2)
 	d0 := binary.BigEndian.Uint64(src1.bytes[0:])
 	d1 := binary.BigEndian.Uint64(src1.bytes[8:])
 	d2 := binary.BigEndian.Uint64(src2.bytes[0:])
 	d3 := binary.BigEndian.Uint64(src2.bytes[8:])
 	imm8 &= 0xf
+	var sym gf2.Vec
+	if v := symbolic(src1, src2); v != nil {
+		d := [4]gf2.Vec{v[0][:64], v[0][64:], v[1][:64], v[1][64:]}
+		sym = gf2.Concat(d[imm8>>2], d[imm8&0x3])
+	}
+	dst.setSymbolic(sym)
 	switch imm8 >> 2 {
 	case 0:
 		binary.BigEndian.PutUint64(dst.bytes[0:], d0)
@@ -0,0 +1,120 @@
+package s390x
+
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/internal/gf2"
+)
+
+func clmul(a, b uint64) (hi, lo uint64) {
+	var temp uint64
+	for i := 0; i < 64; i++ {
+		temp = a & (b >> i) & 1
+		for j := 1; j < i+1; j++ {
+			temp ^= (a >> j) & (b >> (i - j)) & 1
+		}
+		lo |= temp << i
+	}
+	for i := 64; i < 127; i++ {
+		temp = 0
+		for j := i - 63; j < 64; j++ {
+			temp ^= (a >> j) & (b >> (i - j)) & 1
+		}
+		hi |= temp << (i - 64)
+	}
+	return
+}
+
+// Vector Galois Field Multiply Sum (Byte)
+func VGFMB(src1, src2, dst *Vector128) {
+	tmp := Vector128{}
+	for i := 0; i < 16; i += 2 {
+		_, lo1 := clmul(uint64(src1.bytes[i]), uint64(src2.bytes[i]))
+		_, lo2 := clmul(uint64(src1.bytes[i+1]), uint64(src2.bytes[i+1]))
+		binary.BigEndian.PutUint16(tmp.bytes[i:], uint16(lo1^lo2))
+	}
+	copy(dst.bytes[:], tmp.bytes[:])
+}
+
+// Vector Galois Field Multiply Sum (Halfword)
+func VGFMH(src1, src2, dst *Vector128) {
+	tmp := Vector128{}
+	for i := 0; i < 16; i += 4 {
+		_, lo1 := clmul(uint64(binary.BigEndian.Uint16(src1.bytes[i:])), uint64(binary.BigEndian.Uint16(src2.bytes[i:])))
+		_, lo2 := clmul(uint64(binary.BigEndian.Uint16(src1.bytes[i+2:])), uint64(binary.BigEndian.Uint16(src2.bytes[i+2:])))
+		binary.BigEndian.PutUint32(tmp.bytes[i:], uint32(lo1^lo2))
+	}
+	copy(dst.bytes[:], tmp.bytes[:])
+}
+
+// Vector Galois Field Multiply Sum (Word)
+func VGFMF(src1, src2, dst *Vector128) {
+	tmp := Vector128{}
+	for i := 0; i < 16; i += 8 {
+		_, lo1 := clmul(uint64(binary.BigEndian.Uint32(src1.bytes[i:])), uint64(binary.BigEndian.Uint32(src2.bytes[i:])))
+		_, lo2 := clmul(uint64(binary.BigEndian.Uint32(src1.bytes[i+4:])), uint64(binary.BigEndian.Uint32(src2.bytes[i+4:])))
+		binary.BigEndian.PutUint64(tmp.bytes[i:], lo1^lo2)
+	}
+	copy(dst.bytes[:], tmp.bytes[:])
+}
+
+// Vector Galois Field Multiply Sum (Double Word)
+func VGFMG(src1, src2, dst *Vector128) {
+	counter.Op("VGFMG", dst, src1, src2)
+	vgfmg(src1, src2, dst)
+}
+
+// vgfmg is VGFMG without the recording, for VGFMAG.
+func vgfmg(src1, src2, dst *Vector128) {
+	var sym gf2.Vec
+	switch {
+	case src1.sym != nil && src2.sym != nil:
+		panic("s390x: VGFMG of two symbolic operands is not linear")
+	case src1.sym != nil:
+		sym = gfmgSymbolic(src1.Symbolic(), src2)
+	case src2.sym != nil:
+		sym = gfmgSymbolic(src2.Symbolic(), src1)
+	}
+	hi, lo := clmul(binary.BigEndian.Uint64(src1.bytes[:]), binary.BigEndian.Uint64(src2.bytes[:]))
+	hi1, lo1 := clmul(binary.BigEndian.Uint64(src1.bytes[8:]), binary.BigEndian.Uint64(src2.bytes[8:]))
+	binary.BigEndian.PutUint64(dst.bytes[:], hi^hi1)
+	binary.BigEndian.PutUint64(dst.bytes[8:], lo^lo1)
+	dst.setSymbolic(sym)
+}
+
+// gfmgSymbolic returns the symbolic bits of VGFMG of the symbolic register v and the constant register c.
+func gfmgSymbolic(v gf2.Vec, c *Vector128) gf2.Vec {
+	// the bits of a big endian doubleword are in the reversed byte order of the register
+	p := v[:64].ReverseBytes().Clmul(binary.BigEndian.Uint64(c.bytes[:]))
+	p = p.Add(v[64:].ReverseBytes().Clmul(binary.BigEndian.Uint64(c.bytes[8:])))
+	return p.ReverseBytes()
+}
+
+// Vector Galois Field Multiply Sum and Accumulate (Byte)
+func VGFMAB(src1, src2, src3, dst *Vector128) {
+	tmp := Vector128{}
+	VGFMB(src1, src2, &tmp)
+	vx(&tmp, src3, dst)
+}
+
+// Vector Galois Field Multiply Sum and Accumulate (Halfword)
+func VGFMAH(src1, src2, src3, dst *Vector128) {
+	tmp := Vector128{}
+	VGFMH(src1, src2, &tmp)
+	vx(&tmp, src3, dst)
+}
+
+// Vector Galois Field Multiply Sum and Accumulate (Word)
+func VGFMAF(src1, src2, src3, dst *Vector128) {
+	tmp := Vector128{}
+	VGFMF(src1, src2, &tmp)
+	vx(&tmp, src3, dst)
+}
+
+// Vector Galois Field Multiply Sum and Accumulate (Double Word)
+func VGFMAG(src1, src2, src3, dst *Vector128) {
+	counter.Op("VGFMAG", dst, src1, src2, src3)
+	tmp := Vector128{}
+	vgfmg(src1, src2, &tmp)
+	vx(&tmp, src3, dst)
+}
@@ -1,8 +1,13 @@
 package s390x
 
 import (
+	"bytes"
 	"encoding/hex"
 	"testing"
+
+	"github.com/emmansun/simd/internal/ctcheck"
+	"github.com/emmansun/simd/internal/ctchecktest"
+	"github.com/emmansun/simd/internal/gf2"
 )
 
 func TestTransposeMatrix(t *testing.T) {
@@ -35,3 +40,132 @@ func TestTransposeMatrix(t *testing.T) {
 		t.Errorf("t3 = %v; want 3333333377777777bbbbbbbbffffffff", got3)
 	}
 }
+
+func TestVGFM(t *testing.T) {
+	src1 := &Vector128{}
+	src2 := &Vector128{}
+	src3 := &Vector128{}
+	dst := &Vector128{}
+
+	VL_UINT64([]uint64{0x8000000000000001, 0x0000000000000003}, src1)
+	VL_UINT64([]uint64{0x0000000000000003, 0x0000000000000005}, src2)
+
+	VGFMG(src1, src2, dst)
+	got := hex.EncodeToString(dst.Bytes())
+	if got != "0000000000000001800000000000000c" {
+		t.Errorf("VGFMG = %v; want 0000000000000001800000000000000c", got)
+	}
+
+	VGFMF(src1, src2, dst)
+	got = hex.EncodeToString(dst.Bytes())
+	if got != "0000000000000003000000000000000f" {
+		t.Errorf("VGFMF = %v; want 0000000000000003000000000000000f", got)
+	}
+
+	VL_UINT64([]uint64{0xffffffffffffffff, 0xffffffffffffffff}, src3)
+	VGFMAG(src1, src2, src3, dst)
+	got = hex.EncodeToString(dst.Bytes())
+	if got != "fffffffffffffffe7ffffffffffffff3" {
+		t.Errorf("VGFMAG = %v; want fffffffffffffffe7ffffffffffffff3", got)
+	}
+}
+
+func TestVLEI(t *testing.T) {
+	dst := &Vector128{}
+	VLEIB(15, 0x87, dst)
+	VLEIH(1, 0x0102, dst)
+	VLEIF(2, 0x03040506, dst)
+	VLEIG(0, 0xc200000000000000, dst)
+	got := hex.EncodeToString(dst.Bytes())
+	if got != "c2000000000000000304050600000087" {
+		t.Errorf("VLEI = %v; want c2000000000000000304050600000087", got)
+	}
+	VLEIG(1, 1, dst)
+	got = hex.EncodeToString(dst.Bytes())
+	if got != "c2000000000000000000000000000001" {
+		t.Errorf("VLEIG = %v; want c2000000000000000000000000000001", got)
+	}
+}
+
+func TestVESRAG(t *testing.T) {
+	src := &Vector128{}
+	dst := &Vector128{}
+	VL_UINT64([]uint64{0x8000000000000000, 0x4000000000000001}, src)
+	VESRAG(63, src, dst)
+	got := hex.EncodeToString(dst.Bytes())
+	if got != "ffffffffffffffff0000000000000000" {
+		t.Errorf("VESRAG = %v; want ffffffffffffffff0000000000000000", got)
+	}
+}
+
+func TestVSEL(t *testing.T) {
+	a, b, mask, dst := &Vector128{}, &Vector128{}, &Vector128{}, &Vector128{}
+	VL_UINT64([]uint64{0x0123456789abcdef, 0x0123456789abcdef}, a)
+	VL_UINT64([]uint64{0xfedcba9876543210, 0xfedcba9876543210}, b)
+	VL_UINT64([]uint64{0xffffffff00000000, 0x00ff00ff00ff00ff}, mask)
+	VSEL(a, b, mask, dst)
+	if got := dst.Uint64s(); got[0] != 0x0123456776543210 || got[1] != 0xfe23ba6776ab32ef {
+		t.Errorf("VSEL = %x", got)
+	}
+}
+
+func TestVSLDB(t *testing.T) {
+	a, b, dst := &Vector128{}, &Vector128{}, &Vector128{}
+	VL([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, a)
+	VL([]byte{16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31}, b)
+	for imm := uint8(0); imm < 16; imm++ {
+		VSLDB(imm, a, b, dst)
+		for i, v := range dst.bytes {
+			if v != imm+uint8(i) {
+				t.Fatalf("VSLDB(%d) = %v", imm, dst.bytes)
+			}
+		}
+	}
+}
+
+// TestSymbolic checks the symbolic bits of the instructions which carry them give their bytes.
+func TestSymbolic(t *testing.T) {
+	in, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0ff0e1d2c3b4a5968778695a4b3c2d1e0f")
+	var POLY Vector128
+	poly, _ := hex.DecodeString("c2000000000000010000000000000087")
+	copy(POLY.bytes[:], poly)
+	cases := []struct {
+		name string
+		op   func(x, y *Vector128)
+	}{
+		{"VX", func(x, y *Vector128) { VX(x, y, x) }},
+		{"VPDI", func(x, y *Vector128) { VPDI(6, x, y, x) }},
+		{"VGFMG src1", func(x, y *Vector128) { VGFMG(x, &POLY, x) }},
+		{"VGFMG src2", func(x, y *Vector128) { VGFMG(&POLY, y, x) }},
+	}
+	for _, c := range cases {
+		var x, y Vector128
+		SetSymbolic(&x, gf2.Var(0, 128), in[:16])
+		SetSymbolic(&y, gf2.Var(128, 128), in[16:])
+		c.op(&x, &y)
+		if got := x.Symbolic().Eval(in); !bytes.Equal(got, x.Bytes()) {
+			t.Errorf("%s: the symbolic bits give %x, want %x", c.name, got, x.Bytes())
+		}
+	}
+}
+
+func TestLookupTracer(t *testing.T) {
+	run := func(f func(x *Vector128)) func(*ctcheck.Tracer, []byte) {
+		return func(tr *ctcheck.Tracer, secret []byte) {
+			x := &Vector128{}
+			VL(secret, x)
+			SetTracer(tr)
+			defer SetTracer(nil)
+			f(x)
+		}
+	}
+	reverse := func(x *Vector128) {
+		perm := &Vector128{}
+		VL([]byte{15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, perm)
+		VPERM(x, x, perm, x)
+	}
+	ctchecktest.TestLookups(t, []ctchecktest.Case{
+		{Name: "byte shuffle", Run: run(reverse)},
+		{Name: "permute", Site: "VPERM", Run: run(func(x *Vector128) { VPERM(&Vector128{}, &Vector128{}, x, &Vector128{}) })},
+	})
+}
@@ -0,0 +1,88 @@
+package s390x
+
+import (
+	"encoding/binary"
+
+	"github.com/emmansun/simd/alg/sm3"
+)
+
+// SM3 with the vector message expansion of sm3.Round: each step computes four words with VSLDB,
+// VERLLF and VX. The registers are big-endian, word lane 0 is W[i].
+
+// messageSchedule computes out = W[i+16..i+19] of w0 = W[i..i+3], ..., w3 = W[i+12..i+15], see sm3.Round.
+// The fix of W[i+19] is computed on all lanes and VSEL merges it into lane 3 with LANE3 = [0, 0, 0, 0xffffffff].
+func messageSchedule(w0, w1, w2, w3, ZERO, LANE3, t0, t1, t2, out *Vector128) {
+	VSLDB(12, w1, w2, t0)  // t0 = W[-9] = W7 W8 W9 W10
+	VX(t0, w0, t0)         // t0 = W[-9] ^ W[-16]
+	VSLDB(4, w3, ZERO, t1) // t1 = W[-3] = W13 W14 W15 0
+	VERLLF(15, t1, t1)
+	VX(t0, t1, t0) // t0 = W[-16] ^ W[-9] ^ (W[-3] <<< 15), lane 3 lacks W16 <<< 15
+
+	VERLLF(15, t0, t1)
+	VERLLF(23, t0, t2)
+	VX(t1, t2, t1)
+	VX(t1, t0, out) // out = P1(t0)
+
+	VSLDB(12, w0, w1, t0) // t0 = W[-13] = W3 W4 W5 W6
+	VERLLF(7, t0, t0)
+	VX(out, t0, out)
+	VSLDB(8, w2, w3, t0) // t0 = W[-6] = W10 W11 W12 W13
+	VX(out, t0, out)     // out = W16 W17 W18 W19'
+
+	VREPF(0, out, t0) // t0 = W16 W16 W16 W16
+	VERLLF(15, t0, t1)
+	VERLLF(30, t0, t2)
+	VX(t1, t2, t1)
+	VERLLF(6, t0, t2)
+	VX(t1, t2, t1)
+	VSEL(t1, ZERO, LANE3, t1)
+	VX(out, t1, out) // out = W16 W17 W18 W19
+}
+
+// sm3block is the SM3 block function with the vector message expansion and scalar rounds.
+func sm3block(state *[8]uint32, p []byte) {
+	var (
+		V0    = &Vector128{}
+		V1    = &Vector128{}
+		V2    = &Vector128{}
+		V3    = &Vector128{}
+		V4    = &Vector128{}
+		V5    = &Vector128{}
+		V6    = &Vector128{}
+		V7    = &Vector128{}
+		V8    = &Vector128{}
+		ZERO  = &Vector128{}
+		LANE3 = &Vector128{}
+	)
+	var wt, wt1 [16]byte // W[i..i+3] and W'[i..i+3] of the step
+	VZERO(ZERO)
+	VZERO(LANE3)
+	VLEIF(3, 0xffffffff, LANE3)
+
+	for len(p) >= 64 {
+		s := *state
+		VL(p, V0)
+		VL(p[16:], V1)
+		VL(p[32:], V2)
+		VL(p[48:], V3)
+
+		w := [5]*Vector128{V0, V1, V2, V3, V4}
+		for i := 0; i < 64; i += 4 {
+			// W[i+16..i+19] is needed up to W[67]
+			if i < 52 {
+				messageSchedule(w[0], w[1], w[2], w[3], ZERO, LANE3, V6, V7, V8, w[4])
+			}
+			VX(w[0], w[1], V5) // W'[i..i+3]
+			VST(w[0], wt[:])
+			VST(V5, wt1[:])
+			for j := 0; j < 4; j++ {
+				sm3.Round(i+j, &s, binary.BigEndian.Uint32(wt[4*j:]), binary.BigEndian.Uint32(wt1[4*j:]))
+			}
+			w = [5]*Vector128{w[1], w[2], w[3], w[4], w[0]}
+		}
+		for i := range state {
+			state[i] ^= s[i]
+		}
+		p = p[64:]
+	}
+}
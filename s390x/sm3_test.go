@@ -0,0 +1,11 @@
+package s390x
+
+import (
+	"testing"
+
+	"github.com/emmansun/simd/internal/sm3test"
+)
+
+func TestSM3(t *testing.T) {
+	sm3test.TestBlockFunc(t, sm3block)
+}
@@ -0,0 +1,208 @@
+package s390x
+
+func EIA16Bytes(data []byte, keys []uint32) uint32 {
+	var (
+		XTMP1         = Vector128{}
+		XTMP2         = Vector128{}
+		XTMP3         = Vector128{}
+		XDATA         = Vector128{}
+		XDIGEST       = Vector128{}
+		KS_L          = Vector128{}
+		KS_M1         = Vector128{}
+		ZERO          = Vector128{}
+		NIBBLE_MASK   = Vector128{}
+		BIT_REV_TAB_L = Vector128{}
+		BIT_REV_TAB_H = Vector128{}
+		SHUF_MASK_DW0 = Vector128{}
+		SHUF_MASK_DW2 = Vector128{}
+		SHUF_MASK_KS  = Vector128{}
+	)
+	VL_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VL_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+	VREPIB(0x0f, &NIBBLE_MASK)
+	VZERO(&ZERO)
+	VL_UINT64([]uint64{0x0000000013121110, 0x0000000017161514}, &SHUF_MASK_DW0)
+	VL_UINT64([]uint64{0x000000001b1a1918, 0x000000001f1e1d1c}, &SHUF_MASK_DW2)
+	VL_UINT64([]uint64{0x0001020304050607, 0x0405060708090a0b}, &SHUF_MASK_KS)
+
+	// load data
+	VL(data, &XDATA)
+	VN(&XDATA, &NIBBLE_MASK, &XTMP2)
+	VESRLB(4, &XDATA, &XTMP1)
+	VPERM(&BIT_REV_TAB_H, &BIT_REV_TAB_H, &XTMP2, &XTMP3)
+	VPERM(&BIT_REV_TAB_L, &BIT_REV_TAB_L, &XTMP1, &XTMP1)
+	VX(&XTMP3, &XTMP1, &XTMP3) // XTMP3 - bit reverse data bytes
+
+	// ZUC authentication part, 4x32 data bits
+	// setup KS
+	VL_UINT32(keys, &XTMP1)
+	VL_UINT32(keys[2:], &XTMP2)
+	VPERM(&XTMP1, &XTMP1, &SHUF_MASK_KS, &KS_L)  // KS bits [31:0 63:32 63:32 95:64]
+	VPERM(&XTMP2, &XTMP2, &SHUF_MASK_KS, &KS_M1) // KS bits [95:64 127:96 127:96 159:128]
+
+	// setup data
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW0, &XTMP1) // XTMP1 - Data bits [0s 31:0 0s 63:32]
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW2, &XTMP2) // XTMP2 - Data bits [0s 95:64 0s 127:96]
+
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VGFMG(&KS_L, &XTMP1, &XDIGEST)
+	VGFMAG(&KS_M1, &XTMP2, &XDIGEST, &XDIGEST)
+
+	// use VLGVF to get the 32 bits
+	return XDIGEST.Uint32s()[2]
+}
+
+func EIA256RoundTag8(data []byte, keys []uint32) uint64 {
+	var (
+		XTMP1         = Vector128{}
+		XTMP2         = Vector128{}
+		XTMP3         = Vector128{}
+		XTMP4         = Vector128{}
+		XDATA         = Vector128{}
+		XDIGEST       = Vector128{}
+		KS_L          = Vector128{}
+		KS_M1         = Vector128{}
+		KS_M2         = Vector128{}
+		ZERO          = Vector128{}
+		NIBBLE_MASK   = Vector128{}
+		BIT_REV_TAB_L = Vector128{}
+		BIT_REV_TAB_H = Vector128{}
+		SHUF_MASK_DW0 = Vector128{}
+		SHUF_MASK_DW2 = Vector128{}
+		SHUF_MASK_KS  = Vector128{}
+		SHUF_MASK_KS2 = Vector128{}
+	)
+	VL_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VL_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+	VREPIB(0x0f, &NIBBLE_MASK)
+	VZERO(&ZERO)
+	VL_UINT64([]uint64{0x0000000013121110, 0x0000000017161514}, &SHUF_MASK_DW0)
+	VL_UINT64([]uint64{0x000000001b1a1918, 0x000000001f1e1d1c}, &SHUF_MASK_DW2)
+	VL_UINT64([]uint64{0x0001020304050607, 0x0405060708090a0b}, &SHUF_MASK_KS)
+	VL_UINT64([]uint64{0x08090a0b0c0d0e0f, 0x1011121314151617}, &SHUF_MASK_KS2)
+
+	// load data
+	VL(data, &XDATA)
+	VN(&XDATA, &NIBBLE_MASK, &XTMP2)
+	VESRLB(4, &XDATA, &XTMP1)
+	VPERM(&BIT_REV_TAB_H, &BIT_REV_TAB_H, &XTMP2, &XTMP3)
+	VPERM(&BIT_REV_TAB_L, &BIT_REV_TAB_L, &XTMP1, &XTMP1)
+	VX(&XTMP3, &XTMP1, &XTMP3) // XTMP3 - bit reverse data bytes
+
+	// ZUC authentication part, 4x32 data bits
+	// setup KS
+	VL_UINT32(keys, &XTMP1)
+	VL_UINT32(keys[2:], &XTMP2)
+	VL_UINT32(keys[4:], &XTMP4)
+	VPERM(&XTMP1, &XTMP1, &SHUF_MASK_KS, &KS_L)  // KS bits [31:0 63:32 63:32 95:64]
+	VPERM(&XTMP2, &XTMP2, &SHUF_MASK_KS, &KS_M1) // KS bits [95:64 127:96 127:96 159:128]
+	VPERM(&XTMP4, &XTMP4, &SHUF_MASK_KS, &KS_M2) // KS bits [159:128 191:160 191:160 223:192]
+
+	// setup data
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW0, &XTMP1) // XTMP1 - Data bits [0s 31:0 0s 63:32]
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW2, &XTMP2) // XTMP2 - Data bits [0s 95:64 0s 127:96]
+
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VGFMG(&KS_L, &XTMP1, &XTMP3)
+	VGFMAG(&KS_M1, &XTMP2, &XTMP3, &XTMP3)
+
+	// Calculate upper 32 bits of tag
+	VPERM(&KS_L, &KS_M1, &SHUF_MASK_KS2, &KS_L)   // KS bits [63:32 95:64 95:64 127:96]
+	VPERM(&KS_M1, &KS_M2, &SHUF_MASK_KS2, &KS_M1) // KS bits [127:96 159:128 159:128 191:160]
+	VGFMG(&KS_L, &XTMP1, &XTMP4)
+	VGFMAG(&KS_M1, &XTMP2, &XTMP4, &XTMP4)
+
+	// merge the upper and lower 32 bits of tag
+	VMRLF(&XTMP4, &XTMP3, &XDIGEST)
+
+	// use VLGVG to get the 64 bits
+	return XDIGEST.Uint64s()[0]
+}
+
+func EIA256RoundTag16(data []byte, keys []uint32) (uint64, uint64) {
+	var (
+		XTMP1         = Vector128{}
+		XTMP2         = Vector128{}
+		XTMP3         = Vector128{}
+		XTMP4         = Vector128{}
+		XTMP5         = Vector128{}
+		XTMP6         = Vector128{}
+		XDATA         = Vector128{}
+		XDIGEST       = Vector128{}
+		KS_L          = Vector128{}
+		KS_M1         = Vector128{}
+		KS_M2         = Vector128{}
+		KS_H          = Vector128{}
+		ZERO          = Vector128{}
+		NIBBLE_MASK   = Vector128{}
+		BIT_REV_TAB_L = Vector128{}
+		BIT_REV_TAB_H = Vector128{}
+		SHUF_MASK_DW0 = Vector128{}
+		SHUF_MASK_DW2 = Vector128{}
+		SHUF_MASK_KS  = Vector128{}
+		SHUF_MASK_KS1 = Vector128{}
+		SHUF_MASK_KS2 = Vector128{}
+	)
+	VL_UINT64([]uint64{0x0008040c020a060e, 0x0109050d030b070f}, &BIT_REV_TAB_L)
+	VL_UINT64([]uint64{0x008040c020a060e0, 0x109050d030b070f0}, &BIT_REV_TAB_H)
+	VREPIB(0x0f, &NIBBLE_MASK)
+	VZERO(&ZERO)
+	VL_UINT64([]uint64{0x0000000013121110, 0x0000000017161514}, &SHUF_MASK_DW0)
+	VL_UINT64([]uint64{0x000000001b1a1918, 0x000000001f1e1d1c}, &SHUF_MASK_DW2)
+	VL_UINT64([]uint64{0x0001020304050607, 0x0405060708090a0b}, &SHUF_MASK_KS)
+	VL_UINT64([]uint64{0x0405060708090a0b, 0x08090a0b0c0d0e0f}, &SHUF_MASK_KS1)
+	VL_UINT64([]uint64{0x08090a0b0c0d0e0f, 0x1011121314151617}, &SHUF_MASK_KS2)
+
+	// load data
+	VL(data, &XDATA)
+	VN(&XDATA, &NIBBLE_MASK, &XTMP2)
+	VESRLB(4, &XDATA, &XTMP1)
+	VPERM(&BIT_REV_TAB_H, &BIT_REV_TAB_H, &XTMP2, &XTMP3)
+	VPERM(&BIT_REV_TAB_L, &BIT_REV_TAB_L, &XTMP1, &XTMP1)
+	VX(&XTMP3, &XTMP1, &XTMP3) // XTMP3 - bit reverse data bytes
+
+	// ZUC authentication part, 4x32 data bits
+	// setup KS
+	VL_UINT32(keys, &XTMP1)
+	VL_UINT32(keys[2:], &XTMP2)
+	VL_UINT32(keys[4:], &XTMP4)
+	VPERM(&XTMP1, &XTMP1, &SHUF_MASK_KS, &KS_L)  // KS bits [31:0 63:32 63:32 95:64]
+	VPERM(&XTMP2, &XTMP2, &SHUF_MASK_KS, &KS_M1) // KS bits [95:64 127:96 127:96 159:128]
+	VPERM(&XTMP4, &XTMP4, &SHUF_MASK_KS, &KS_M2) // KS bits [159:128 191:160 191:160 223:192]
+	VPERM(&XTMP4, &XTMP4, &SHUF_MASK_KS1, &KS_H) // KS bits [191:160 223:192 223:192 255:224]
+
+	// setup data
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW0, &XTMP1) // XTMP1 - Data bits [0s 31:0 0s 63:32]
+	VPERM(&ZERO, &XTMP3, &SHUF_MASK_DW2, &XTMP2) // XTMP2 - Data bits [0s 95:64 0s 127:96]
+
+	// clmul
+	// xor the results from 4 32-bit words together
+	// Calculate lower 32 bits of tag
+	VGFMG(&KS_L, &XTMP1, &XTMP3)
+	VGFMAG(&KS_M1, &XTMP2, &XTMP3, &XTMP3)
+
+	// Calculate bits 95-64 of tag
+	VGFMG(&KS_M1, &XTMP1, &XTMP5)
+	VGFMAG(&KS_M2, &XTMP2, &XTMP5, &XTMP5)
+
+	// Calculate bits 63-32 of tag
+	VPERM(&KS_L, &KS_M1, &SHUF_MASK_KS2, &KS_L)   // KS bits [63:32 95:64 95:64 127:96]
+	VPERM(&KS_M1, &KS_M2, &SHUF_MASK_KS2, &KS_M1) // KS bits [127:96 159:128 159:128 191:160]
+	VGFMG(&KS_L, &XTMP1, &XTMP4)
+	VGFMAG(&KS_M1, &XTMP2, &XTMP4, &XTMP4)
+
+	// Calculate bits 127-96 of tag
+	VGFMG(&KS_M1, &XTMP1, &XTMP6)
+	VGFMAG(&KS_H, &XTMP2, &XTMP6, &XTMP6)
+
+	// merge the 4 32-bit words of tag
+	VMRLF(&XTMP4, &XTMP3, &XTMP4)
+	VMRLF(&XTMP6, &XTMP5, &XTMP6)
+	VPDI(2, &XTMP4, &XTMP6, &XDIGEST)
+
+	return XDIGEST.Uint64s()[0], XDIGEST.Uint64s()[1]
+}